@@ -4,9 +4,26 @@ package agent
 
 import (
 	"context"
+	"io"
+	"os"
 	"strings"
+	"time"
 )
 
+// evalWaitDelay bounds how long a cancelled Evaluate subprocess may hold
+// its output pipes open after being killed. Without it, a grandchild
+// that inherited stdout keeps Wait blocked past the context deadline.
+const evalWaitDelay = 5 * time.Second
+
+// stdoutOr returns w, or os.Stdout when w is nil. Backends stream
+// Execute and Session output to it.
+func stdoutOr(w io.Writer) io.Writer {
+	if w != nil {
+		return w
+	}
+	return os.Stdout
+}
+
 // Model is a model name alias (e.g. "haiku", "sonnet", "codex").
 type Model string
 
@@ -46,6 +63,10 @@ func (m Model) Tier() string {
 	return low
 }
 
+// DefaultContextWindow is the context window in tokens assumed for
+// models whose models.profiles entry sets none.
+const DefaultContextWindow = 128_000
+
 // IsLite returns true for models that should use the lite (governance-free)
 // validator prompt. Covers cheap-tier models where latency and token budgets
 // are tight.
@@ -191,6 +191,32 @@ cat
 	}
 }
 
+// TestClaude_Evaluate_JSONResult verifies the --output-format json
+// envelope is unwrapped and its token usage recorded.
+func TestClaude_Evaluate_JSONResult(t *testing.T) {
+	dir := t.TempDir()
+	fakeBin := filepath.Join(dir, "fake-claude")
+	script := `#!/bin/sh
+cat > /dev/null
+echo '{"type":"result","is_error":false,"result":"{\"status\":\"pass\"}","usage":{"input_tokens":12,"cache_read_input_tokens":100,"output_tokens":7}}'
+`
+	if err := os.WriteFile(fakeBin, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake binary: %v", err)
+	}
+
+	ctx, meter := agent.WithUsageMeter(t.Context())
+	out, err := agent.NewClaude(fakeBin).Evaluate(ctx, "sys", "user", agent.Model("sonnet"), agent.ToolsDisabled)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if out != `{"status":"pass"}` {
+		t.Errorf("output = %q, want unwrapped result", out)
+	}
+	if got := meter.Total(); got != (agent.Usage{InputTokens: 112, OutputTokens: 7}) {
+		t.Errorf("usage = %+v, want 112 in / 7 out", got)
+	}
+}
+
 // TestClaude_Evaluate_NoEffortForSonnet verifies --effort is NOT
 // passed for non-haiku models.
 func TestClaude_Evaluate_NoEffortForSonnet(t *testing.T) {
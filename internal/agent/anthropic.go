@@ -3,8 +3,8 @@ package agent
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,24 +13,27 @@ import (
 	"github.com/anthropics/anthropic-sdk-go/option"
 )
 
-// modelProfile holds per-tier token limits for the Messages API.
-type modelProfile struct {
-	maxTokens int64
+// ModelProfile holds per-model generation settings for the Messages
+// API.
+type ModelProfile struct {
+	MaxTokens      int64    // response token cap; 0 = defaultMaxTokens
+	Temperature    *float64 // nil = API default; ignored with thinking
+	ThinkingBudget int64    // extended-thinking tokens; 0 = off
 }
 
-// modelProfiles maps tier names to their token limits.
-var modelProfiles = map[string]modelProfile{
-	"haiku":  {maxTokens: 4096},
-	"sonnet": {maxTokens: 8192},
-	"opus":   {maxTokens: 8192},
-}
+// defaultMaxTokens caps responses for models with no profile, or whose
+// profile sets no max_tokens.
+const defaultMaxTokens = 8192
 
-// modelAliases maps short tier names to full Anthropic model identifiers.
-var modelAliases = map[string]string{
-	"haiku":  string(anthropic.ModelClaudeHaiku4_5_20251001),
-	"sonnet": string(anthropic.ModelClaudeSonnet4_6),
-	"opus":   string(anthropic.ModelClaudeOpus4_6),
-}
+// defaultModel is used when the caller passes no model — e.g. a
+// session started with only -p, or an unset role model — as the CLI
+// backends fall back to their own default. It goes through the alias
+// table like any other model.
+const defaultModel Model = "sonnet"
+
+// thinkingHeadroom is the minimum response budget kept above the
+// thinking budget, which the API requires max_tokens to exceed.
+const thinkingHeadroom = 1024
 
 // claudeCodeSystemPrefix is the system prompt prefix required by the
 // Anthropic API when authenticating with a Claude CLI OAuth token.
@@ -40,8 +43,11 @@ const claudeCodeSystemPrefix = "You are Claude Code, Anthropic's official CLI fo
 type AnthropicOption func(*anthropicConfig)
 
 type anthropicConfig struct {
-	apiKey  string
-	baseURL string
+	apiKey   string
+	baseURL  string
+	aliases  map[string]string
+	profiles map[string]ModelProfile
+	tools    ToolConfig
 }
 
 // WithAPIKey sets an explicit API key, overriding ANTHROPIC_API_KEY.
@@ -59,10 +65,44 @@ func WithBaseURL(url string) AnthropicOption {
 	}
 }
 
+// WithModelAliases sets the alias table (models.aliases) applied to
+// models passed to the backend directly rather than through the Router.
+func WithModelAliases(aliases map[string]string) AnthropicOption {
+	return func(c *anthropicConfig) {
+		c.aliases = aliases
+	}
+}
+
+// WithModelProfiles sets the generation profiles (models.profiles),
+// keyed by full model identifier or tier.
+func WithModelProfiles(profiles map[string]ModelProfile) AnthropicOption {
+	return func(c *anthropicConfig) {
+		c.profiles = profiles
+	}
+}
+
+// WithTools configures the sandboxed tool set used by Execute and
+// Session.
+func WithTools(tc ToolConfig) AnthropicOption {
+	return func(c *anthropicConfig) {
+		c.tools = tc
+	}
+}
+
 // Anthropic implements Agent via the Anthropic Messages API.
 type Anthropic struct {
-	client anthropic.Client
-	oauth  bool // true when using Claude CLI OAuth token
+	client   anthropic.Client
+	oauth    bool // true when using Claude CLI OAuth token
+	aliases  map[string]string
+	profiles map[string]ModelProfile
+	tools    ToolConfig
+	stdout   io.Writer
+}
+
+// backend creates the Anthropic backend for client with the configured
+// aliases, profiles, and tools.
+func (c *anthropicConfig) backend(client anthropic.Client, oauth bool) *Anthropic {
+	return &Anthropic{client: client, oauth: oauth, aliases: c.aliases, profiles: c.profiles, tools: c.tools}
 }
 
 // NewAnthropic creates an Anthropic backend. Returns nil when no
@@ -91,8 +131,7 @@ func NewAnthropic(opts ...AnthropicOption) *Anthropic {
 		if cfg.baseURL != "" {
 			opts = append(opts, option.WithBaseURL(cfg.baseURL))
 		}
-		client := anthropic.NewClient(opts...)
-		return &Anthropic{client: client}
+		return cfg.backend(anthropic.NewClient(opts...), false)
 	}
 
 	// 2. Claude CLI OAuth token — match the Claude Code request shape
@@ -116,8 +155,7 @@ func NewAnthropic(opts ...AnthropicOption) *Anthropic {
 		if cfg.baseURL != "" {
 			oauthOpts = append(oauthOpts, option.WithBaseURL(cfg.baseURL))
 		}
-		client := anthropic.NewClient(oauthOpts...)
-		return &Anthropic{client: client, oauth: true}
+		return cfg.backend(anthropic.NewClient(oauthOpts...), true)
 	}
 
 	// 3. ANTHROPIC_API_KEY environment variable (billed to API credits).
@@ -126,8 +164,7 @@ func NewAnthropic(opts ...AnthropicOption) *Anthropic {
 		if cfg.baseURL != "" {
 			envOpts = append(envOpts, option.WithBaseURL(cfg.baseURL))
 		}
-		client := anthropic.NewClient(envOpts...)
-		return &Anthropic{client: client}
+		return cfg.backend(anthropic.NewClient(envOpts...), false)
 	}
 
 	return nil
@@ -164,28 +201,34 @@ func (a *Anthropic) Name() string { return "anthropic" }
 // IsOAuth reports whether this backend is using a Claude CLI OAuth token.
 func (a *Anthropic) IsOAuth() bool { return a.oauth }
 
-// Session returns an error — the direct API backend cannot attach
-// to a terminal for interactive sessions.
-func (a *Anthropic) Session(_ context.Context, _ string, _ []string) error {
-	return errors.New("anthropic direct API does not support interactive sessions")
-}
-
-// Execute returns an error — the direct API backend does not
-// support tool-enabled execute mode.
-func (a *Anthropic) Execute(_ context.Context, _, _ string, _ Model) error {
-	return fmt.Errorf("anthropic: execute mode requires tool use (not supported)")
-}
-
 // Evaluate calls the Anthropic Messages API directly.
 // The tools parameter is accepted for interface compliance but has no
-// effect — the direct API backend does not support tool use.
+// effect — Evaluate answers from the prompt alone.
 func (a *Anthropic) Evaluate(ctx context.Context, systemPrompt, userPrompt string, model Model, _ ToolPolicy) (string, error) {
-	resolvedModel := resolveModel(string(model))
-	profile := profileFor(model.Tier())
+	params, reqOpts := a.newParams(systemPrompt, model)
+	params.Messages = []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(userPrompt)),
+	}
+
+	msg, err := a.send(ctx, params, reqOpts)
+	if err != nil {
+		return "", err
+	}
+	return extractText(msg), nil
+}
+
+// newParams builds the request parameters, without messages, shared by
+// Evaluate and the tool-use loop.
+func (a *Anthropic) newParams(systemPrompt string, model Model) (anthropic.MessageNewParams, []option.RequestOption) {
+	if model == "" {
+		model = defaultModel
+	}
+	resolvedModel := string(resolveAlias(a.aliases, model))
+	profile := profileFor(a.profiles, resolvedModel, model.Tier())
 
 	if os.Getenv("BONSAI_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "[bonsai:debug] anthropic model=%s resolved=%s maxTokens=%d oauth=%v\n",
-			model, resolvedModel, profile.maxTokens, a.oauth)
+		fmt.Fprintf(os.Stderr, "[bonsai:debug] anthropic model=%s resolved=%s maxTokens=%d thinking=%d oauth=%v\n",
+			model, resolvedModel, profile.MaxTokens, profile.ThinkingBudget, a.oauth)
 	}
 
 	// Build system prompt blocks. OAuth path requires the Claude Code
@@ -200,12 +243,10 @@ func (a *Anthropic) Evaluate(ctx context.Context, systemPrompt, userPrompt strin
 
 	params := anthropic.MessageNewParams{
 		Model:     anthropic.Model(resolvedModel),
-		MaxTokens: profile.maxTokens,
+		MaxTokens: profile.MaxTokens,
 		System:    system,
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(userPrompt)),
-		},
 	}
+	applyProfile(&params, profile)
 
 	// OAuth path: add metadata and ?beta=true query param.
 	var reqOpts []option.RequestOption
@@ -215,32 +256,54 @@ func (a *Anthropic) Evaluate(ctx context.Context, systemPrompt, userPrompt strin
 		}
 		reqOpts = append(reqOpts, option.WithQuery("beta", "true"))
 	}
+	return params, reqOpts
+}
 
+// send makes one Messages API call and records its token usage.
+func (a *Anthropic) send(ctx context.Context, params anthropic.MessageNewParams, reqOpts []option.RequestOption) (*anthropic.Message, error) {
 	msg, err := a.client.Messages.New(ctx, params, reqOpts...)
 	if err != nil {
-		return "", fmt.Errorf("anthropic API call failed: %w", err)
+		return nil, fmt.Errorf("anthropic API call failed: %w", err)
 	}
-
-	return extractText(msg), nil
+	recordUsage(ctx, Usage{
+		InputTokens:  msg.Usage.InputTokens + msg.Usage.CacheCreationInputTokens + msg.Usage.CacheReadInputTokens,
+		OutputTokens: msg.Usage.OutputTokens,
+	})
+	return msg, nil
 }
 
-// resolveModel maps a short alias (e.g. "haiku") to the full Anthropic
-// model identifier. Returns the input unchanged if no alias matches.
-func resolveModel(name string) string {
-	low := strings.ToLower(name)
-	if full, ok := modelAliases[low]; ok {
-		return full
+// profileFor returns the profile for the first of names present in
+// profiles. Falls back to the sonnet profile for unknown models, and to
+// defaultMaxTokens when the profile sets no token cap.
+func profileFor(profiles map[string]ModelProfile, names ...string) ModelProfile {
+	p, ok := ModelProfile{}, false
+	for _, n := range names {
+		if p, ok = profiles[n]; ok {
+			break
+		}
+	}
+	if !ok {
+		p = profiles["sonnet"]
 	}
-	return name
+	if p.MaxTokens <= 0 {
+		p.MaxTokens = defaultMaxTokens
+	}
+	return p
 }
 
-// profileFor returns the token profile for a given tier. Falls back to
-// the sonnet profile for unknown tiers.
-func profileFor(tier string) modelProfile {
-	if p, ok := modelProfiles[tier]; ok {
-		return p
+// applyProfile sets the optional generation parameters of a profile.
+// Extended thinking requires the default temperature and max_tokens
+// above the thinking budget, so it takes precedence over Temperature
+// and raises MaxTokens as needed.
+func applyProfile(params *anthropic.MessageNewParams, p ModelProfile) {
+	if p.ThinkingBudget > 0 {
+		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(p.ThinkingBudget)
+		params.MaxTokens = max(params.MaxTokens, p.ThinkingBudget+thinkingHeadroom)
+		return
+	}
+	if p.Temperature != nil {
+		params.Temperature = anthropic.Float(*p.Temperature)
 	}
-	return modelProfiles["sonnet"]
 }
 
 // extractText concatenates all text blocks from an Anthropic response.
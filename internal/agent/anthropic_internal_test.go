@@ -2,24 +2,22 @@ package agent
 
 import "testing"
 
-func TestResolveModel(t *testing.T) {
+func TestResolveAlias(t *testing.T) {
+	aliases := map[string]string{"haiku": "claude-haiku-4-5-20251001", "sonnet": "claude-sonnet-4-6"}
 	tests := []struct {
-		input string
-		want  string
+		input Model
+		want  Model
 	}{
 		{"haiku", "claude-haiku-4-5-20251001"},
-		{"sonnet", "claude-sonnet-4-6"},
-		{"opus", "claude-opus-4-6"},
-		{"claude-haiku-4-5-20251001", "claude-haiku-4-5-20251001"},
+		{"Sonnet", "claude-sonnet-4-6"},
 		{"claude-sonnet-4-6", "claude-sonnet-4-6"},
 		{"unknown-model", "unknown-model"},
 		{"", ""},
 	}
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := resolveModel(tt.input)
-			if got != tt.want {
-				t.Errorf("resolveModel(%q) = %q, want %q", tt.input, got, tt.want)
+		t.Run(string(tt.input), func(t *testing.T) {
+			if got := resolveAlias(aliases, tt.input); got != tt.want {
+				t.Errorf("resolveAlias(%q) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
@@ -35,11 +33,12 @@ func TestProfileFor(t *testing.T) {
 		{"opus", 8192},
 		{"unknown", 8192}, // falls back to sonnet
 	}
+	profiles := map[string]ModelProfile{"haiku": {MaxTokens: 4096}, "sonnet": {MaxTokens: 8192}, "opus": {}}
 	for _, tt := range tests {
 		t.Run(tt.tier, func(t *testing.T) {
-			p := profileFor(tt.tier)
-			if p.maxTokens != tt.wantMax {
-				t.Errorf("profileFor(%q).maxTokens = %d, want %d", tt.tier, p.maxTokens, tt.wantMax)
+			p := profileFor(profiles, tt.tier)
+			if p.MaxTokens != tt.wantMax {
+				t.Errorf("profileFor(%q).MaxTokens = %d, want %d", tt.tier, p.MaxTokens, tt.wantMax)
 			}
 		})
 	}
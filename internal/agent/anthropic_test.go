@@ -29,17 +29,6 @@ func TestAnthropic_Name(t *testing.T) {
 	}
 }
 
-func TestAnthropic_SessionReturnsError(t *testing.T) {
-	a := agent.NewAnthropic(agent.WithAPIKey("test-key"))
-	if a == nil {
-		t.Fatal("NewAnthropic returned nil with explicit key")
-	}
-	err := a.Session(t.Context(), "sys", nil)
-	if err == nil {
-		t.Error("Session should return an error for direct API backend")
-	}
-}
-
 func TestNewAnthropic_NilWithoutKey(t *testing.T) {
 	t.Setenv("ANTHROPIC_API_KEY", "")
 	// Point HOME to an empty dir so the OAuth credential file lookup
@@ -238,6 +227,7 @@ func TestAnthropic_RequestShape_APIKey(t *testing.T) {
 	a := agent.NewAnthropic(
 		agent.WithAPIKey("sk-test-key-123"),
 		agent.WithBaseURL(srv.URL),
+		agent.WithModelAliases(map[string]string{"haiku": "claude-haiku-4-5-20251001"}),
 	)
 	if a == nil {
 		t.Fatal("expected non-nil Anthropic")
@@ -283,8 +273,8 @@ func TestAnthropic_RequestShape_APIKey(t *testing.T) {
 	if err := json.Unmarshal(capturedBody, &body); err != nil {
 		t.Fatalf("unmarshal body: %v", err)
 	}
-	if model, ok := body["model"].(string); !ok || !strings.Contains(model, "haiku") {
-		t.Errorf("body model = %v, want haiku variant", body["model"])
+	if body["model"] != "claude-haiku-4-5-20251001" {
+		t.Errorf("body model = %v, want the aliased claude-haiku-4-5-20251001", body["model"])
 	}
 }
 
@@ -385,3 +375,73 @@ func TestAnthropic_RequestShape_OAuth(t *testing.T) {
 		t.Errorf("metadata.user_id = %q, want bonsai", uid)
 	}
 }
+
+func TestAnthropic_RecordsUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(anthropicStubResponse()))
+	}))
+	defer srv.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	a := agent.NewAnthropic(agent.WithAPIKey("sk-test"), agent.WithBaseURL(srv.URL))
+
+	ctx, meter := agent.WithUsageMeter(t.Context())
+	for range 2 {
+		if _, err := a.Evaluate(ctx, "sys", "user", agent.Model("haiku"), agent.ToolsDisabled); err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+	}
+	if got := meter.Total(); got != (agent.Usage{InputTokens: 20, OutputTokens: 2}) {
+		t.Errorf("usage = %+v, want 20 in / 2 out", got)
+	}
+}
+
+// TestAnthropic_ModelProfiles verifies configured profiles shape the
+// request: temperature, and thinking raising max_tokens above its budget.
+func TestAnthropic_ModelProfiles(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(raw, &body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(anthropicStubResponse()))
+	}))
+	defer srv.Close()
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("HOME", t.TempDir())
+
+	temp := 0.2
+	a := agent.NewAnthropic(
+		agent.WithAPIKey("sk-test"),
+		agent.WithBaseURL(srv.URL),
+		agent.WithModelProfiles(map[string]agent.ModelProfile{
+			"haiku":           {MaxTokens: 2048, Temperature: &temp},
+			"claude-opus-4-6": {MaxTokens: 4096, Temperature: &temp, ThinkingBudget: 8000},
+		}),
+	)
+
+	if _, err := a.Evaluate(t.Context(), "s", "u", agent.Model("haiku"), agent.ToolsDisabled); err != nil {
+		t.Fatalf("Evaluate(haiku): %v", err)
+	}
+	if body["max_tokens"] != 2048.0 || body["temperature"] != 0.2 || body["thinking"] != nil {
+		t.Errorf("haiku body = max_tokens %v, temperature %v, thinking %v; want 2048, 0.2, none",
+			body["max_tokens"], body["temperature"], body["thinking"])
+	}
+
+	if _, err := a.Evaluate(t.Context(), "s", "u", agent.Model("claude-opus-4-6"), agent.ToolsDisabled); err != nil {
+		t.Fatalf("Evaluate(opus): %v", err)
+	}
+	thinking, _ := body["thinking"].(map[string]any)
+	if thinking["budget_tokens"] != 8000.0 {
+		t.Errorf("thinking = %v, want budget_tokens 8000", body["thinking"])
+	}
+	if mt, _ := body["max_tokens"].(float64); mt <= 8000 {
+		t.Errorf("max_tokens = %v, want above the thinking budget", body["max_tokens"])
+	}
+	if _, ok := body["temperature"]; ok {
+		t.Errorf("temperature = %v, want omitted with thinking", body["temperature"])
+	}
+}
@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Backend names usable in fallback chains, alongside command template
+// names.
+const (
+	BackendAnthropic    = "anthropic"
+	BackendClaudeCLI    = "claude-cli"
+	BackendCodexCLI     = "codex-cli"
+	BackendOpenAICompat = "openai-compat"
+)
+
+// Model families keying fallback chains.
+const (
+	FamilyClaude  = "claude"
+	FamilyCodex   = "codex"
+	FamilyDefault = "default"
+)
+
+// defaultChains are used for families with no chain in Router.Chains.
+// They reproduce the original routing: the direct API falling back to
+// the Claude CLI for claude-family models, and no fallback otherwise.
+var defaultChains = map[string][]string{
+	FamilyClaude:  {BackendAnthropic, BackendClaudeCLI},
+	FamilyCodex:   {BackendCodexCLI},
+	FamilyDefault: {BackendClaudeCLI},
+}
+
+// Family returns the fallback-chain family of the model: claude, codex,
+// or default for anything else (including the empty model).
+func (m Model) Family() string {
+	switch {
+	case m.IsCodex():
+		return FamilyCodex
+	case m.IsClaude():
+		return FamilyClaude
+	}
+	return FamilyDefault
+}
+
+// breaker counts consecutive failures per backend within a run. The
+// zero value is ready to use.
+type breaker struct {
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// open reports whether name has failed at least threshold times in a
+// row. A threshold <= 0 never opens.
+func (b *breaker) open(name string, threshold int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return threshold > 0 && b.failures[name] >= threshold
+}
+
+// record notes the outcome of a call to name.
+func (b *breaker) record(name string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures == nil {
+		b.failures = make(map[string]int)
+	}
+	if ok {
+		b.failures[name] = 0
+	} else {
+		b.failures[name]++
+	}
+}
+
+// SetChains sets the fallback chain per model family, validating each
+// backend name against the built-in backends and the registered
+// command templates. Unknown names are dropped and reported in the
+// returned error; the rest of the chain is kept.
+func (r *Router) SetChains(chains map[string][]string) error {
+	var unknown []string
+	r.Chains = make(map[string][]string, len(chains))
+	for family, chain := range chains {
+		for _, name := range chain {
+			if !r.knownBackend(name) {
+				unknown = append(unknown, family+": "+name)
+				continue
+			}
+			r.Chains[family] = append(r.Chains[family], name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("unknown routing backends dropped: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// knownBackend reports whether name is a built-in backend or a
+// registered command template.
+func (r *Router) knownBackend(name string) bool {
+	switch name {
+	case BackendAnthropic, BackendClaudeCLI, BackendCodexCLI, BackendOpenAICompat:
+		return true
+	}
+	_, ok := r.Templates[name]
+	return ok
+}
+
+// backend returns the named backend, or nil when it is not configured
+// (no Anthropic credentials, no OpenAI-compatible endpoint, unknown).
+func (r *Router) backend(name string) Agent {
+	switch name {
+	case BackendAnthropic:
+		return r.Anthropic
+	case BackendClaudeCLI:
+		if r.Claude == nil {
+			return nil
+		}
+		return r.Claude
+	case BackendCodexCLI:
+		if r.Codex == nil {
+			return nil
+		}
+		return r.Codex
+	case BackendOpenAICompat:
+		return r.OpenAICompat
+	}
+	return r.Templates[name]
+}
+
+// chainFor returns the fallback chain for the model's family.
+func (r *Router) chainFor(model Model) []string {
+	family := model.Family()
+	if chain, ok := r.Chains[family]; ok {
+		return chain
+	}
+	return defaultChains[family]
+}
+
+// nativeFamily maps the built-in single-vendor backends to the model
+// family they serve.
+var nativeFamily = map[string]string{
+	BackendAnthropic: FamilyClaude,
+	BackendClaudeCLI: FamilyClaude,
+	BackendCodexCLI:  FamilyCodex,
+}
+
+// modelFor returns the model a backend is called with. The CLI and
+// Anthropic backends get the empty model (their default) for models of
+// another family, which they could not serve; the OpenAI-compatible
+// backend and templates get the model unchanged for their model tables
+// to map.
+func modelFor(name string, model Model) Model {
+	if family, ok := nativeFamily[name]; ok && model.Family() != family && model.Family() != FamilyDefault {
+		return ""
+	}
+	return model
+}
+
+// evaluateChain tries each configured backend of the model's chain in
+// order, retrying each per Retries, until one answers. Backends that
+// are unconfigured or whose circuit is open are skipped. Context
+// cancellation stops the chain immediately.
+func (r *Router) evaluateChain(ctx context.Context, systemPrompt, userPrompt string, model Model, tools ToolPolicy) (string, error) {
+	chain := r.chainFor(model)
+	var errs []error
+	for _, name := range chain {
+		a := r.backend(name)
+		if a == nil || r.breaker.open(name, r.BreakerThreshold) {
+			continue
+		}
+		out, err := r.tryBackend(ctx, name, a, systemPrompt, userPrompt, modelFor(name, model), tools)
+		if err == nil {
+			return out, nil
+		}
+		if isCancellation(ctx, err) {
+			return "", err
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("no backend available for model %q: chain %v is unconfigured or circuit-broken", model, chain)
+	}
+	return "", errors.Join(errs...)
+}
+
+// tryBackend calls one backend up to 1+Retries[name] times and records
+// the outcome on the breaker and the context's trace.
+func (r *Router) tryBackend(ctx context.Context, name string, a Agent, systemPrompt, userPrompt string, model Model, tools ToolPolicy) (string, error) {
+	var err error
+	for attempt := 0; attempt <= max(r.Retries[name], 0); attempt++ {
+		var out string
+		if out, err = a.Evaluate(ctx, systemPrompt, userPrompt, model, tools); err == nil {
+			r.breaker.record(name, true)
+			recordBackend(ctx, name)
+			return out, nil
+		}
+		if isCancellation(ctx, err) {
+			return "", err
+		}
+		recordFallback(ctx, name)
+		if os.Getenv("BONSAI_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[bonsai:debug] %s failed (attempt %d): %v\n", name, attempt+1, err)
+		}
+	}
+	r.breaker.record(name, false)
+	return "", fmt.Errorf("%s: %w", name, err)
+}
+
+// isCancellation reports whether the caller is done: falling back would
+// just add noise and latency. Check both the context and the error
+// chain: the context reflects the caller's intent, while the error
+// chain catches transport-level timeouts where ctx.Err() may still be
+// nil.
+func isCancellation(ctx context.Context, err error) bool {
+	return ctx.Err() != nil ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
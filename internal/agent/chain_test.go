@@ -0,0 +1,151 @@
+package agent_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+)
+
+// chainRouter returns a router whose named backends are mocks, with the
+// given chain for claude-family models.
+func chainRouter(backends map[string]agent.Agent, chain ...string) *agent.Router {
+	return &agent.Router{
+		Templates: backends,
+		Chains:    map[string][]string{agent.FamilyClaude: chain},
+	}
+}
+
+func TestModel_Family(t *testing.T) {
+	tests := []struct {
+		model agent.Model
+		want  string
+	}{
+		{"sonnet", agent.FamilyClaude},
+		{"claude-opus-4-6", agent.FamilyClaude},
+		{"codex-mini", agent.FamilyCodex},
+		{"gpt-4o", agent.FamilyDefault},
+		{"", agent.FamilyDefault},
+	}
+	for _, tt := range tests {
+		if got := tt.model.Family(); got != tt.want {
+			t.Errorf("Model(%q).Family() = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestRouter_ChainFallsThroughAndTraces(t *testing.T) {
+	down := &agent.MockAgent{NameVal: "down", EvaluateErr: errors.New("503 overloaded")}
+	up := &agent.MockAgent{NameVal: "up", EvaluateResponse: "ok"}
+	r := chainRouter(map[string]agent.Agent{"down": down, "up": up}, "down", "up")
+
+	ctx, trace := agent.WithBackendTrace(t.Context())
+	out, err := r.Evaluate(ctx, "sys", "user", agent.Model("sonnet"), agent.ToolsDisabled)
+	if err != nil || out != "ok" {
+		t.Fatalf("Evaluate = %q, %v; want ok via fallback", out, err)
+	}
+	if got := trace.Backend(); got != "up" {
+		t.Errorf("Backend() = %q, want up", got)
+	}
+	if got := trace.Fallbacks(); len(got) != 1 || got[0] != "down" {
+		t.Errorf("Fallbacks() = %v, want [down]", got)
+	}
+	if up.EvaluateCalls[0].Model != "sonnet" {
+		t.Errorf("template got model %q, want sonnet unchanged", up.EvaluateCalls[0].Model)
+	}
+}
+
+func TestRouter_ChainRetries(t *testing.T) {
+	down := &agent.MockAgent{NameVal: "down", EvaluateErr: errors.New("timeout")}
+	r := chainRouter(map[string]agent.Agent{"down": down}, "down")
+	r.Retries = map[string]int{"down": 2}
+
+	_, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("haiku"), agent.ToolsDisabled)
+	if err == nil || !strings.Contains(err.Error(), "down: timeout") {
+		t.Errorf("err = %v, want backend-tagged error", err)
+	}
+	if down.CallCount() != 3 {
+		t.Errorf("CallCount = %d, want 3 (1 + 2 retries)", down.CallCount())
+	}
+}
+
+func TestRouter_CircuitBreakerSkipsFailingBackend(t *testing.T) {
+	down := &agent.MockAgent{NameVal: "down", EvaluateErr: errors.New("401 authentication_error")}
+	up := &agent.MockAgent{NameVal: "up", EvaluateResponse: "ok"}
+	r := chainRouter(map[string]agent.Agent{"down": down, "up": up}, "down", "up")
+	r.BreakerThreshold = 2
+
+	for range 4 {
+		if _, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("sonnet"), agent.ToolsDisabled); err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+	}
+	if down.CallCount() != 2 {
+		t.Errorf("down CallCount = %d, want 2 (circuit open after 2 failures)", down.CallCount())
+	}
+	if up.CallCount() != 4 {
+		t.Errorf("up CallCount = %d, want 4", up.CallCount())
+	}
+}
+
+func TestRouter_ChainAllBroken(t *testing.T) {
+	down := &agent.MockAgent{NameVal: "down", EvaluateErr: errors.New("boom")}
+	r := chainRouter(map[string]agent.Agent{"down": down}, "down")
+	r.BreakerThreshold = 1
+
+	if _, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("sonnet"), agent.ToolsDisabled); err == nil {
+		t.Fatal("first Evaluate should fail")
+	}
+	_, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("sonnet"), agent.ToolsDisabled)
+	if err == nil || !strings.Contains(err.Error(), "no backend available") {
+		t.Errorf("err = %v, want no backend available", err)
+	}
+	if down.CallCount() != 1 {
+		t.Errorf("CallCount = %d, want 1", down.CallCount())
+	}
+}
+
+func TestRouter_CodexChainFallback(t *testing.T) {
+	up := &agent.MockAgent{NameVal: "up", EvaluateResponse: "ok"}
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.Templates = map[string]agent.Agent{"up": up}
+	if err := r.SetChains(map[string][]string{agent.FamilyCodex: {agent.BackendCodexCLI, "up"}}); err != nil {
+		t.Fatalf("SetChains: %v", err)
+	}
+
+	out, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("codex"), agent.ToolsDisabled)
+	if err != nil || out != "ok" {
+		t.Errorf("Evaluate = %q, %v; want ok after codex CLI failure", out, err)
+	}
+}
+
+func TestRouter_SetChainsDropsUnknown(t *testing.T) {
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	err := r.SetChains(map[string][]string{agent.FamilyClaude: {"anthropic", "gemini-cli", "claude-cli"}})
+	if err == nil || !strings.Contains(err.Error(), "claude: gemini-cli") {
+		t.Errorf("err = %v, want unknown backend reported", err)
+	}
+	if got := r.Chains[agent.FamilyClaude]; len(got) != 2 || got[0] != "anthropic" || got[1] != "claude-cli" {
+		t.Errorf("chain = %v, want [anthropic claude-cli]", got)
+	}
+}
+
+func TestRouter_ResolvesAliases(t *testing.T) {
+	up := &agent.MockAgent{NameVal: "up", EvaluateResponse: "ok"}
+	r := chainRouter(map[string]agent.Agent{"up": up}, "up")
+	r.Aliases = map[string]string{"sonnet": "claude-sonnet-4-5", "fast": "up:small"}
+
+	for _, model := range []agent.Model{"Sonnet", "fast"} {
+		if _, err := r.Evaluate(t.Context(), "sys", "user", model, agent.ToolsDisabled); err != nil {
+			t.Fatalf("Evaluate(%s): %v", model, err)
+		}
+	}
+	if got := up.EvaluateCalls[0].Model; got != "claude-sonnet-4-5" {
+		t.Errorf("chain backend got model %q, want pinned claude-sonnet-4-5", got)
+	}
+	// An alias may name a prefixed model, which then routes directly.
+	if got := up.EvaluateCalls[1].Model; got != "small" {
+		t.Errorf("template got model %q, want small", got)
+	}
+}
@@ -3,7 +3,9 @@ package agent
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -13,6 +15,8 @@ import (
 type Claude struct {
 	// Bin is the path to the claude binary. Defaults to "claude".
 	Bin string
+	// Stdout receives Session and Execute output. Defaults to os.Stdout.
+	Stdout io.Writer
 }
 
 // NewClaude creates a Claude agent with the given binary path.
@@ -23,6 +27,16 @@ func NewClaude(bin string) *Claude {
 	return &Claude{Bin: bin}
 }
 
+// installed reports whether the claude binary can be found. A nil
+// Claude is not installed.
+func (c *Claude) installed() bool {
+	if c == nil {
+		return false
+	}
+	_, err := exec.LookPath(c.Bin)
+	return err == nil
+}
+
 // Name returns "claude".
 func (c *Claude) Name() string { return "claude" }
 
@@ -34,7 +48,7 @@ func (c *Claude) Session(ctx context.Context, systemPrompt string, extraArgs []s
 
 	cmd := exec.CommandContext(ctx, c.Bin, args...)
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdoutOr(c.Stdout)
 	cmd.Stderr = os.Stderr
 
 	// Remove CLAUDECODE from env so nested invocations work
@@ -68,7 +82,7 @@ func (c *Claude) Evaluate(ctx context.Context, systemPrompt, userPrompt string,
 		"--system-prompt", systemPrompt,
 		"--disable-slash-commands",
 		"--no-session-persistence",
-		"--output-format", "text",
+		"--output-format", "json",
 	)
 
 	switch tools {
@@ -103,12 +117,45 @@ func (c *Claude) Evaluate(ctx context.Context, systemPrompt, userPrompt string,
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	cmd.WaitDelay = evalWaitDelay
 
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("claude invocation failed: %w\nstderr: %s", err, strings.TrimSpace(stderr.String()))
 	}
 
-	return stdout.String(), nil
+	return parseClaudeResult(ctx, stdout.Bytes())
+}
+
+// claudeResult is the relevant subset of the envelope printed by
+// `claude -p --output-format json`.
+type claudeResult struct {
+	Type    string `json:"type"`
+	IsError bool   `json:"is_error"`
+	Result  string `json:"result"`
+	Usage   struct {
+		InputTokens              int64 `json:"input_tokens"`
+		CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		OutputTokens             int64 `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// parseClaudeResult unwraps the JSON result envelope and records its
+// token usage. Output that is not an envelope (older CLIs, wrappers)
+// is returned verbatim.
+func parseClaudeResult(ctx context.Context, out []byte) (string, error) {
+	var res claudeResult
+	if err := json.Unmarshal(out, &res); err != nil || res.Type != "result" {
+		return string(out), nil
+	}
+	recordUsage(ctx, Usage{
+		InputTokens:  res.Usage.InputTokens + res.Usage.CacheCreationInputTokens + res.Usage.CacheReadInputTokens,
+		OutputTokens: res.Usage.OutputTokens,
+	})
+	if res.IsError {
+		return "", fmt.Errorf("claude invocation failed: %s", res.Result)
+	}
+	return res.Result, nil
 }
 
 // Execute runs claude in print mode with tools enabled.
@@ -132,7 +179,7 @@ func (c *Claude) Execute(ctx context.Context, systemPrompt, userPrompt string, m
 
 	cmd := exec.CommandContext(ctx, c.Bin, args...)
 	cmd.Stdin = strings.NewReader(userPrompt)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdoutOr(c.Stdout)
 	cmd.Stderr = os.Stderr
 	cmd.Env = filterEnv(os.Environ(), "CLAUDECODE")
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -13,6 +14,8 @@ import (
 type Codex struct {
 	// Bin is the path to the codex binary. Defaults to "codex".
 	Bin string
+	// Stdout receives Session and Execute output. Defaults to os.Stdout.
+	Stdout io.Writer
 }
 
 // NewCodex creates a Codex agent with the given binary path.
@@ -34,7 +37,7 @@ func (c *Codex) Session(ctx context.Context, systemPrompt string, extraArgs []st
 
 	cmd := exec.CommandContext(ctx, c.Bin, args...)
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdoutOr(c.Stdout)
 	cmd.Stderr = os.Stderr
 
 	// Remove CLAUDECODE from env so nested invocations work
@@ -77,6 +80,7 @@ func (c *Codex) Evaluate(ctx context.Context, systemPrompt, userPrompt string, m
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	cmd.WaitDelay = evalWaitDelay
 
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("codex invocation failed: %w: %s", err, strings.TrimSpace(stderr.String()))
@@ -99,7 +103,7 @@ func (c *Codex) Execute(ctx context.Context, systemPrompt, userPrompt string, mo
 
 	cmd := exec.CommandContext(ctx, c.Bin, args...)
 	cmd.Stdin = strings.NewReader(combined)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdoutOr(c.Stdout)
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
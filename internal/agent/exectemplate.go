@@ -0,0 +1,257 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+)
+
+// ExecSpec describes how to drive an arbitrary AI CLI. Args and
+// ExecuteArgs are text/template strings over:
+//
+//	{{.Model}}        model name after the "<template>:" prefix
+//	{{.SystemPrompt}} the system prompt
+//	{{.PromptFile}}   path of the prompt temp file (Input "file" only)
+//
+// The prompt sent on stdin or in the file is the user prompt when any
+// arg references .SystemPrompt, and the system prompt followed by the
+// user prompt otherwise.
+type ExecSpec struct {
+	Bin         string   // binary name or path
+	Args        []string // argv template for Evaluate
+	ExecuteArgs []string // argv template for Execute; empty = unsupported
+	Input       string   // "stdin" (default) or "file"
+	Output      string   // "text" (default) or "json"
+	ResultField string   // dotted path to the response text in JSON output; default "result"
+}
+
+// ExecTemplate implements Agent by running a CLI described by an
+// ExecSpec, so new vendor CLIs (or renamed flags) need only config.
+type ExecTemplate struct {
+	name        string
+	spec        ExecSpec
+	args        argv
+	executeArgs argv
+	stdout      io.Writer
+}
+
+// argv is a parsed argument template set: Args or ExecuteArgs.
+type argv struct {
+	tmpls      []*template.Template
+	withSystem bool // the args carry the system prompt separately
+	withModel  bool // the args need a model name
+}
+
+// execData is the data the argv templates render against.
+type execData struct {
+	Model        string
+	SystemPrompt string
+	PromptFile   string
+}
+
+// NewExecTemplate validates spec and creates the named backend.
+func NewExecTemplate(name string, spec ExecSpec) (*ExecTemplate, error) {
+	if spec.Bin == "" {
+		return nil, fmt.Errorf("agent template %q: bin is required", name)
+	}
+	if spec.Input == "" {
+		spec.Input = "stdin"
+	}
+	if spec.Output == "" {
+		spec.Output = "text"
+	}
+	if spec.ResultField == "" {
+		spec.ResultField = "result"
+	}
+	if spec.Input != "stdin" && spec.Input != "file" {
+		return nil, fmt.Errorf("agent template %q: input %q must be stdin or file", name, spec.Input)
+	}
+	if spec.Output != "text" && spec.Output != "json" {
+		return nil, fmt.Errorf("agent template %q: output %q must be text or json", name, spec.Output)
+	}
+	args, err := parseArgTemplates(name, spec.Args)
+	if err != nil {
+		return nil, err
+	}
+	executeArgs, err := parseArgTemplates(name, spec.ExecuteArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecTemplate{
+		name:        name,
+		spec:        spec,
+		args:        args,
+		executeArgs: executeArgs,
+	}, nil
+}
+
+// parseArgTemplates parses each arg and renders it once against sample
+// data, so unknown fields fail at load rather than mid-run.
+func parseArgTemplates(name string, args []string) (argv, error) {
+	tmpls := make([]*template.Template, len(args))
+	for i, a := range args {
+		t, err := template.New(name).Option("missingkey=error").Parse(a)
+		if err == nil {
+			err = t.Execute(io.Discard, execData{})
+		}
+		if err != nil {
+			return argv{}, fmt.Errorf("agent template %q: arg %d: %w", name, i, err)
+		}
+		tmpls[i] = t
+	}
+	return argv{
+		tmpls:      tmpls,
+		withSystem: referencesField(args, ".SystemPrompt"),
+		withModel:  referencesField(args, ".Model"),
+	}, nil
+}
+
+// referencesField reports whether any arg uses the template field.
+func referencesField(args []string, field string) bool {
+	for _, a := range args {
+		if strings.Contains(a, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the template name from config.
+func (e *ExecTemplate) Name() string { return e.name }
+
+// Session returns an error — templates describe non-interactive
+// invocations only.
+func (e *ExecTemplate) Session(_ context.Context, _ string, _ []string) error {
+	return fmt.Errorf("agent template %q does not support interactive sessions", e.name)
+}
+
+// Evaluate renders the argv template, runs the CLI with the prompt on
+// stdin or in a temp file, and parses its stdout per the output mode.
+// The tools parameter is accepted for interface compliance but has no
+// effect — tool flags, if any, belong in the template.
+func (e *ExecTemplate) Evaluate(ctx context.Context, systemPrompt, userPrompt string, model Model, _ ToolPolicy) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd, cleanup, err := e.command(ctx, e.args, systemPrompt, userPrompt, model)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = evalWaitDelay
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s invocation failed: %w: %s", e.name, err, strings.TrimSpace(stderr.String()))
+	}
+	if e.spec.Output == "json" {
+		return extractField(stdout.Bytes(), e.spec.ResultField)
+	}
+	return stdout.String(), nil
+}
+
+// Execute runs the ExecuteArgs template with output streamed to the
+// terminal. Returns an error when the template defines no ExecuteArgs.
+func (e *ExecTemplate) Execute(ctx context.Context, systemPrompt, userPrompt string, model Model) error {
+	if len(e.spec.ExecuteArgs) == 0 {
+		return fmt.Errorf("agent template %q: execute mode not configured (execute_args)", e.name)
+	}
+	cmd, cleanup, err := e.command(ctx, e.executeArgs, systemPrompt, userPrompt, model)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	cmd.Stdout = stdoutOr(e.stdout)
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = evalWaitDelay
+	return cmd.Run()
+}
+
+// command renders av and wires the prompt to stdin or a temp file,
+// prepending the system prompt unless av carries it separately. A bare
+// template route (no model) is rejected when av renders {{.Model}}, so
+// the CLI never receives an empty model flag. The returned cleanup
+// removes the temp file.
+func (e *ExecTemplate) command(ctx context.Context, av argv, systemPrompt, userPrompt string, model Model) (*exec.Cmd, func(), error) {
+	if av.withModel && model == "" {
+		return nil, nil, fmt.Errorf("agent template %q: args use {{.Model}}; route as %q", e.name, e.name+":<model>")
+	}
+	prompt := userPrompt
+	if !av.withSystem {
+		prompt = systemPrompt + "\n\n" + userPrompt
+	}
+	data := execData{Model: string(model), SystemPrompt: systemPrompt}
+	cleanup := func() {}
+	if e.spec.Input == "file" {
+		path, err := writePromptFile(prompt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("agent template %q: %w", e.name, err)
+		}
+		data.PromptFile = path
+		cleanup = func() { _ = os.Remove(path) }
+	}
+
+	args := make([]string, len(av.tmpls))
+	for i, t := range av.tmpls {
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("agent template %q: arg %d: %w", e.name, i, err)
+		}
+		args[i] = b.String()
+	}
+
+	if os.Getenv("BONSAI_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "[bonsai:debug] %s: %s [%d args, input=%s, output=%s]\n",
+			e.name, e.spec.Bin, len(args), e.spec.Input, e.spec.Output)
+	}
+
+	cmd := exec.CommandContext(ctx, e.spec.Bin, args...)
+	if e.spec.Input == "stdin" {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+	cmd.Env = filterEnv(os.Environ(), "CLAUDECODE")
+	return cmd, cleanup, nil
+}
+
+// writePromptFile writes prompt to a new temp file and returns its path.
+func writePromptFile(prompt string) (string, error) {
+	f, err := os.CreateTemp("", "bonsai-prompt-*.md")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(prompt); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// extractField decodes JSON output and returns the string at the
+// dotted path (e.g. "response.text").
+func extractField(out []byte, path string) (string, error) {
+	var v any
+	if err := json.Unmarshal(out, &v); err != nil {
+		return "", fmt.Errorf("parse JSON output: %w", err)
+	}
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("JSON output: %q is not an object", key)
+		}
+		if v, ok = obj[key]; !ok {
+			return "", fmt.Errorf("JSON output has no field %q", path)
+		}
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("JSON output: field %q is not a string", path)
+	}
+	return s, nil
+}
@@ -0,0 +1,200 @@
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+)
+
+// writeFakeCLI writes an executable shell script and returns its path.
+func writeFakeCLI(t *testing.T, script string) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "fake-cli")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return bin
+}
+
+func TestExecTemplate_Implements(_ *testing.T) {
+	// Compile-time interface check.
+	var _ agent.Agent = (*agent.ExecTemplate)(nil)
+}
+
+func TestNewExecTemplate_Validates(t *testing.T) {
+	tests := []struct {
+		name string
+		spec agent.ExecSpec
+	}{
+		{"missing bin", agent.ExecSpec{}},
+		{"bad input", agent.ExecSpec{Bin: "x", Input: "pipe"}},
+		{"bad output", agent.ExecSpec{Bin: "x", Output: "yaml"}},
+		{"bad syntax", agent.ExecSpec{Bin: "x", Args: []string{"{{.Model"}}},
+		{"unknown field", agent.ExecSpec{Bin: "x", Args: []string{"{{.Temperature}}"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := agent.NewExecTemplate("vendor", tt.spec); err == nil {
+				t.Error("NewExecTemplate should reject the spec")
+			}
+		})
+	}
+}
+
+func TestExecTemplate_EvaluateStdinText(t *testing.T) {
+	// Echo argv, then the prompt from stdin.
+	bin := writeFakeCLI(t, `echo "args: $*"; cat`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{
+		Bin:  bin,
+		Args: []string{"--model", "{{.Model}}", "--quiet"},
+	})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	if e.Name() != "vendor" {
+		t.Errorf("Name() = %q, want vendor", e.Name())
+	}
+
+	out, err := e.Evaluate(t.Context(), "SYS", "USER", agent.Model("v2-large"), agent.ToolsDisabled)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if !strings.Contains(out, "args: --model v2-large --quiet") {
+		t.Errorf("output = %q, want rendered argv", out)
+	}
+	// No .SystemPrompt in args: the system prompt is prepended.
+	if !strings.Contains(out, "SYS\n\nUSER") {
+		t.Errorf("output = %q, want combined prompt on stdin", out)
+	}
+}
+
+func TestExecTemplate_EvaluateFileJSON(t *testing.T) {
+	// $2 is the prompt file; wrap its contents in a JSON envelope.
+	bin := writeFakeCLI(t, `printf '{"response":{"text":"%s|%s"}}' "$4" "$(cat "$2")"`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{
+		Bin:         bin,
+		Args:        []string{"--prompt-file", "{{.PromptFile}}", "--system", "{{.SystemPrompt}}"},
+		Input:       "file",
+		Output:      "json",
+		ResultField: "response.text",
+	})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+
+	out, err := e.Evaluate(t.Context(), "SYS", "USER", agent.Model(""), agent.ToolsDisabled)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	// .SystemPrompt is in args: the file carries only the user prompt.
+	if out != "SYS|USER" {
+		t.Errorf("output = %q, want SYS|USER", out)
+	}
+}
+
+func TestExecTemplate_EvaluateJSONMissingField(t *testing.T) {
+	bin := writeFakeCLI(t, `echo '{"other":"x"}'`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{Bin: bin, Output: "json"})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	if _, err := e.Evaluate(t.Context(), "s", "u", "", agent.ToolsDisabled); err == nil || !strings.Contains(err.Error(), "result") {
+		t.Errorf("err = %v, want missing result field", err)
+	}
+}
+
+func TestExecTemplate_EvaluateFailureIncludesStderr(t *testing.T) {
+	bin := writeFakeCLI(t, `echo "unknown flag --model" >&2; exit 2`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{Bin: bin})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	_, err = e.Evaluate(t.Context(), "s", "u", "", agent.ToolsDisabled)
+	if err == nil || !strings.Contains(err.Error(), "unknown flag --model") {
+		t.Errorf("err = %v, want stderr in error", err)
+	}
+}
+
+func TestExecTemplate_ExecuteRequiresExecuteArgs(t *testing.T) {
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{Bin: "true"})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	if err := e.Execute(t.Context(), "s", "u", ""); err == nil {
+		t.Error("Execute should fail without execute_args")
+	}
+	if err := e.Session(t.Context(), "s", nil); err == nil {
+		t.Error("Session should return an error for command templates")
+	}
+}
+
+func TestExecTemplate_ExecuteSystemPromptPerArgSet(t *testing.T) {
+	// Args pass the system prompt as a flag; ExecuteArgs do not, so
+	// Execute must still prepend it to the prompt on stdin.
+	bin := writeFakeCLI(t, `cat`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{
+		Bin:         bin,
+		Args:        []string{"--system", "{{.SystemPrompt}}"},
+		ExecuteArgs: []string{"--model", "{{.Model}}"},
+	})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	var out strings.Builder
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.UseExecTemplate(e)
+	r.SetStdout(&out)
+	if err := r.Execute(t.Context(), "SYS", "USER", "vendor:v2"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if out.String() != "SYS\n\nUSER" {
+		t.Errorf("Execute stdin = %q, want the system prompt prepended", out.String())
+	}
+}
+
+func TestRouter_SetStdoutRedirectsExecute(t *testing.T) {
+	bin := writeFakeCLI(t, `echo "executed $1"`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{Bin: bin, ExecuteArgs: []string{"{{.Model}}"}})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.UseExecTemplate(e)
+
+	var out strings.Builder
+	r.SetStdout(&out)
+	if err := r.Execute(t.Context(), "s", "u", "vendor:v2"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "executed v2" {
+		t.Errorf("redirected output = %q, want %q", out.String(), "executed v2")
+	}
+}
+
+func TestRouter_RoutesToExecTemplate(t *testing.T) {
+	bin := writeFakeCLI(t, `echo "model=$1"`)
+	e, err := agent.NewExecTemplate("vendor", agent.ExecSpec{Bin: bin, Args: []string{"{{.Model}}"}})
+	if err != nil {
+		t.Fatalf("NewExecTemplate: %v", err)
+	}
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.UseExecTemplate(e)
+
+	out, err := r.Evaluate(t.Context(), "s", "u", "vendor:v2-large", agent.ToolsDisabled)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if strings.TrimSpace(out) != "model=v2-large" {
+		t.Errorf("Evaluate = %q, want %q", out, "model=v2-large")
+	}
+
+	// A bare route cannot fill {{.Model}}: it is rejected rather than
+	// rendering an empty model flag.
+	if _, err := r.Evaluate(t.Context(), "s", "u", "vendor", agent.ToolsDisabled); err == nil ||
+		!strings.Contains(err.Error(), "vendor:<model>") {
+		t.Errorf("bare route: err = %v, want a request for vendor:<model>", err)
+	}
+}
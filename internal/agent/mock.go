@@ -40,6 +40,10 @@ type MockAgent struct {
 	ExecuteErr       error
 	ExecuteCalls     []ExecuteCall
 
+	// EvaluateUsage is recorded on the context's UsageMeter for every
+	// Evaluate call, as a real backend would.
+	EvaluateUsage Usage
+
 	// EvaluateFunc, when set, is called instead of returning
 	// the static EvaluateResponse/EvaluateErr. Useful for
 	// per-call mock responses in parallel tests.
@@ -76,8 +80,10 @@ func (m *MockAgent) Evaluate(ctx context.Context, systemPrompt, userPrompt strin
 	fn := m.EvaluateFunc
 	resp := m.EvaluateResponse
 	err := m.EvaluateErr
+	usage := m.EvaluateUsage
 	m.mu.Unlock()
 
+	recordUsage(ctx, usage)
 	if fn != nil {
 		return fn(ctx, systemPrompt, userPrompt, model, tools)
 	}
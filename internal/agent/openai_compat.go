@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultCompatPrefix is the model prefix the Router dispatches to the
+// OpenAI-compatible backend when no other prefix is configured, e.g.
+// "local:qwen2.5-coder".
+const DefaultCompatPrefix = "local"
+
+// maxErrorBody bounds how much of a failed response body is quoted in
+// the returned error.
+const maxErrorBody = 512
+
+// compatRequestTimeout bounds one chat completion request, including
+// reading the response. Local models on modest hardware can take
+// minutes on a large prompt, so it is generous; it exists so a server
+// that stops responding does not hang the run.
+const compatRequestTimeout = 10 * time.Minute
+
+// OpenAICompat implements Agent against any OpenAI-compatible
+// /chat/completions endpoint (Ollama, vLLM, llama.cpp, LM Studio,
+// OpenRouter, ...).
+type OpenAICompat struct {
+	baseURL  string
+	apiKey   string
+	models   map[string]string
+	profiles map[string]ModelProfile
+	client   *http.Client
+}
+
+// OpenAICompatOption configures the OpenAI-compatible backend.
+type OpenAICompatOption func(*OpenAICompat)
+
+// WithCompatProfiles sets the generation profiles (models.profiles),
+// keyed by the model name after the router prefix.
+func WithCompatProfiles(profiles map[string]ModelProfile) OpenAICompatOption {
+	return func(o *OpenAICompat) {
+		o.profiles = profiles
+	}
+}
+
+// NewOpenAICompat creates an OpenAI-compatible backend. baseURL is the
+// API root including any version segment (e.g.
+// "http://localhost:11434/v1"); apiKey, when non-empty, is sent as a
+// bearer token; models maps short names to the served model ids.
+// Returns nil when baseURL is empty, leaving the backend disabled.
+func NewOpenAICompat(baseURL, apiKey string, models map[string]string, opts ...OpenAICompatOption) *OpenAICompat {
+	if baseURL == "" {
+		return nil
+	}
+	o := &OpenAICompat{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		models:  models,
+		client:  &http.Client{Timeout: compatRequestTimeout},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Name returns "openai_compat".
+func (o *OpenAICompat) Name() string { return "openai_compat" }
+
+// Session returns an error — the HTTP backend cannot attach to a
+// terminal for interactive sessions.
+func (o *OpenAICompat) Session(_ context.Context, _ string, _ []string) error {
+	return errors.New("openai_compat backend does not support interactive sessions")
+}
+
+// Execute returns an error — the HTTP backend does not support
+// tool-enabled execute mode.
+func (o *OpenAICompat) Execute(_ context.Context, _, _ string, _ Model) error {
+	return fmt.Errorf("openai_compat: execute mode requires tool use (not supported)")
+}
+
+// chatRequest is the subset of the chat completions request bonsai sends.
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"` // 0 unless profiled: reviews should be repeatable
+	MaxTokens   int64         `json:"max_tokens,omitempty"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatResponse is the subset of the chat completions response bonsai reads.
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Evaluate posts the prompts as a system and a user message to
+// {baseURL}/chat/completions and returns the first choice's content.
+// model is the name after the router prefix; it is mapped through the
+// configured model table and otherwise sent unchanged. The model's
+// profile supplies max_tokens and temperature when set. The tools
+// parameter is accepted for interface compliance but has no effect.
+func (o *OpenAICompat) Evaluate(ctx context.Context, systemPrompt, userPrompt string, model Model, _ ToolPolicy) (string, error) {
+	resolved := o.resolveModel(string(model))
+	if os.Getenv("BONSAI_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "[bonsai:debug] openai_compat model=%s resolved=%s url=%s\n",
+			model, resolved, o.baseURL)
+	}
+
+	req := chatRequest{
+		Model: resolved,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+	}
+	o.applyProfile(&req, string(model))
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("openai_compat: encode request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("openai_compat: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("openai_compat API call failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return decodeChatResponse(ctx, resp)
+}
+
+// decodeChatResponse checks the status, records token usage, and
+// returns the first choice's message content.
+func decodeChatResponse(ctx context.Context, resp *http.Response) (string, error) {
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return "", fmt.Errorf("openai_compat API call failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var out chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("openai_compat: decode response: %w", err)
+	}
+	recordUsage(ctx, Usage{
+		InputTokens:  out.Usage.PromptTokens,
+		OutputTokens: out.Usage.CompletionTokens,
+	})
+	if len(out.Choices) == 0 {
+		return "", errors.New("openai_compat: response has no choices")
+	}
+	return out.Choices[0].Message.Content, nil
+}
+
+// applyProfile sets the request's generation parameters from the
+// profile for name. Unlike the Anthropic
+// backend there is no fallback profile: an unprofiled model keeps
+// temperature 0 and the server's default token cap. thinking_budget
+// has no chat completions equivalent and is ignored.
+func (o *OpenAICompat) applyProfile(req *chatRequest, name string) {
+	p, ok := o.profiles[name]
+	if !ok {
+		return
+	}
+	req.MaxTokens = p.MaxTokens
+	if p.Temperature != nil {
+		req.Temperature = *p.Temperature
+	}
+}
+
+// resolveModel maps a short name through the model table. Returns the
+// input unchanged if no entry matches.
+func (o *OpenAICompat) resolveModel(name string) string {
+	if id, ok := o.models[name]; ok {
+		return id
+	}
+	return name
+}
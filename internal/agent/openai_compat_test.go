@@ -0,0 +1,180 @@
+package agent_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+)
+
+func TestOpenAICompat_Implements(_ *testing.T) {
+	// Compile-time interface check.
+	var _ agent.Agent = (*agent.OpenAICompat)(nil)
+}
+
+func TestNewOpenAICompat_NilWithoutBaseURL(t *testing.T) {
+	if c := agent.NewOpenAICompat("", "key", nil); c != nil {
+		t.Error("NewOpenAICompat should return nil when base URL is empty")
+	}
+}
+
+// compatServer serves one canned chat completion and captures the
+// request it received.
+func compatServer(t *testing.T, status int, reply string) (*httptest.Server, *http.Request, map[string]any) {
+	t.Helper()
+	var gotReq http.Request
+	gotBody := map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = *r
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gotReq, gotBody
+}
+
+func TestOpenAICompat_Evaluate(t *testing.T) {
+	srv, req, body := compatServer(t, http.StatusOK,
+		`{"choices":[{"message":{"role":"assistant","content":"{\"findings\":[]}"}}],"usage":{"prompt_tokens":120,"completion_tokens":7}}`)
+
+	c := agent.NewOpenAICompat(srv.URL+"/v1/", "sk-local", map[string]string{"coder": "qwen2.5-coder:32b"})
+	ctx, meter := agent.WithUsageMeter(t.Context())
+	out, err := c.Evaluate(ctx, "sys", "user", agent.Model("coder"), agent.ToolsDisabled)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if out != `{"findings":[]}` {
+		t.Errorf("output = %q", out)
+	}
+	if req.URL.Path != "/v1/chat/completions" {
+		t.Errorf("path = %q, want /v1/chat/completions", req.URL.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer sk-local" {
+		t.Errorf("Authorization = %q, want bearer token", got)
+	}
+	if body["model"] != "qwen2.5-coder:32b" {
+		t.Errorf("model = %v, want mapped id", body["model"])
+	}
+	msgs, _ := body["messages"].([]any)
+	if len(msgs) != 2 {
+		t.Fatalf("messages = %v, want system + user", body["messages"])
+	}
+	if m, _ := msgs[0].(map[string]any); m["role"] != "system" || m["content"] != "sys" {
+		t.Errorf("messages[0] = %v", m)
+	}
+	if u := meter.Total(); u.InputTokens != 120 || u.OutputTokens != 7 {
+		t.Errorf("usage = %+v, want 120/7", u)
+	}
+}
+
+func TestOpenAICompat_EvaluateNoKeyNoAuthHeader(t *testing.T) {
+	srv, req, body := compatServer(t, http.StatusOK, `{"choices":[{"message":{"content":"ok"}}]}`)
+
+	c := agent.NewOpenAICompat(srv.URL, "", nil)
+	if _, err := c.Evaluate(t.Context(), "sys", "user", agent.Model("llama3"), agent.ToolsDisabled); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none without a key", got)
+	}
+	if body["model"] != "llama3" {
+		t.Errorf("model = %v, want unmapped name passed through", body["model"])
+	}
+}
+
+func TestOpenAICompat_EvaluateAppliesProfile(t *testing.T) {
+	srv, _, body := compatServer(t, http.StatusOK, `{"choices":[{"message":{"content":"ok"}}]}`)
+
+	temp := 0.3
+	c := agent.NewOpenAICompat(srv.URL, "", map[string]string{"coder": "qwen2.5-coder:32b"},
+		agent.WithCompatProfiles(map[string]agent.ModelProfile{"coder": {MaxTokens: 2048, Temperature: &temp}}))
+	if _, err := c.Evaluate(t.Context(), "sys", "user", agent.Model("coder"), agent.ToolsDisabled); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if body["max_tokens"] != float64(2048) || body["temperature"] != 0.3 {
+		t.Errorf("max_tokens = %v, temperature = %v; want the profile's 2048, 0.3", body["max_tokens"], body["temperature"])
+	}
+
+	// An unprofiled model keeps temperature 0 and sends no token cap.
+	srv, _, body = compatServer(t, http.StatusOK, `{"choices":[{"message":{"content":"ok"}}]}`)
+	c = agent.NewOpenAICompat(srv.URL, "", nil,
+		agent.WithCompatProfiles(map[string]agent.ModelProfile{"coder": {MaxTokens: 2048}}))
+	if _, err := c.Evaluate(t.Context(), "sys", "user", agent.Model("llama3"), agent.ToolsDisabled); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if _, ok := body["max_tokens"]; ok || body["temperature"] != float64(0) {
+		t.Errorf("unprofiled request = %v, want temperature 0 and no max_tokens", body)
+	}
+}
+
+func TestOpenAICompat_EvaluateHTTPError(t *testing.T) {
+	srv, _, _ := compatServer(t, http.StatusNotFound, `{"error":"model not found"}`)
+
+	c := agent.NewOpenAICompat(srv.URL, "", nil)
+	_, err := c.Evaluate(t.Context(), "sys", "user", agent.Model("missing"), agent.ToolsDisabled)
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("err = %v, want status and body", err)
+	}
+}
+
+func TestOpenAICompat_SessionAndExecuteReturnErrors(t *testing.T) {
+	c := agent.NewOpenAICompat("http://localhost:1", "", nil)
+	if err := c.Session(t.Context(), "sys", nil); err == nil {
+		t.Error("Session should return an error for the HTTP backend")
+	}
+	if err := c.Execute(t.Context(), "sys", "user", agent.Model("x")); err == nil {
+		t.Error("Execute should return an error for the HTTP backend")
+	}
+}
+
+func TestRouter_RoutesPrefixedModelToOpenAICompat(t *testing.T) {
+	srv, _, body := compatServer(t, http.StatusOK, `{"choices":[{"message":{"content":"from-local"}}]}`)
+
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.UseOpenAICompat("", agent.NewOpenAICompat(srv.URL, "", nil))
+	out, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("local:qwen2.5-coder"), agent.ToolsDisabled)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if out != "from-local" {
+		t.Errorf("output = %q, want from-local", out)
+	}
+	if body["model"] != "qwen2.5-coder" {
+		t.Errorf("model = %v, want prefix stripped", body["model"])
+	}
+}
+
+func TestRouter_CustomCompatPrefix(t *testing.T) {
+	srv, _, body := compatServer(t, http.StatusOK, `{"choices":[{"message":{"content":"ok"}}]}`)
+
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.UseOpenAICompat("vllm", agent.NewOpenAICompat(srv.URL, "", nil))
+	if _, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("vllm:mistral"), agent.ToolsDisabled); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if body["model"] != "mistral" {
+		t.Errorf("model = %v, want mistral", body["model"])
+	}
+}
+
+func TestRouter_PrefixedModelWithoutEndpoint(t *testing.T) {
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.UseOpenAICompat("", nil)
+	if r.OpenAICompat != nil {
+		t.Fatal("UseOpenAICompat(nil) should leave the backend unset")
+	}
+	_, err := r.Evaluate(t.Context(), "sys", "user", agent.Model("local:qwen"), agent.ToolsDisabled)
+	if err == nil || !strings.Contains(err.Error(), "openai_compat") {
+		t.Errorf("err = %v, want configuration hint", err)
+	}
+	if err := r.Execute(t.Context(), "sys", "user", agent.Model("local:qwen")); err == nil {
+		t.Error("Execute should fail for a prefixed model without an endpoint")
+	}
+}
@@ -2,26 +2,45 @@ package agent
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"os"
+	"io"
+	"slices"
+	"strings"
 )
 
-// Router implements Agent by dispatching to Claude CLI, Codex CLI, or
-// the direct Anthropic API backend based on the model string.
+// Router implements Agent by dispatching to Claude CLI, Codex CLI, the
+// direct Anthropic API backend, an OpenAI-compatible endpoint, or a
+// configured command template based on the model string.
 //
-// Dispatch order for Evaluate:
+// Models are first resolved through Aliases, so every backend of a
+// chain runs the same pinned model. Dispatch order for Evaluate:
 //
-//	IsCodex()                      → Codex CLI
-//	IsClaude() && Anthropic != nil → Anthropic direct API
-//	default                        → Claude CLI (fallback)
+//	"<template>[:<name>]"   → command template (<name>)
+//	"<CompatPrefix>:<name>" → OpenAI-compatible endpoint (<name>)
+//	otherwise               → the fallback chain for Model.Family()
 //
-// Session dispatches based on --model in extraArgs (Codex → Codex CLI,
-// default → Claude CLI).
+// The default chains are anthropic → claude-cli for claude-family
+// models, codex-cli for codex models, and claude-cli for the rest;
+// Chains overrides them per family.
+//
+// Session and Execute dispatch Codex models to the Codex CLI and the
+// rest to the Claude CLI, or, when its binary is not installed, to the
+// Anthropic backend's native tool-use loop (see toolAgent).
 type Router struct {
-	Claude    *Claude
-	Codex     *Codex
-	Anthropic Agent // nil when no API key is available
+	Claude       *Claude
+	Codex        *Codex
+	Anthropic    Agent            // nil when no API key is available
+	OpenAICompat Agent            // nil when no endpoint is configured
+	CompatPrefix string           // model prefix for OpenAICompat; empty = DefaultCompatPrefix
+	Templates    map[string]Agent // command templates by name
+
+	Aliases map[string]string // model name → model identifier; case-insensitive keys
+
+	Chains           map[string][]string // backend names per model family; see SetChains
+	Retries          map[string]int      // extra attempts per backend name
+	BreakerThreshold int                 // consecutive failures before a backend is skipped for the run; 0 = never
+
+	breaker breaker
 }
 
 // NewRouter creates an agent router with all backends configured.
@@ -41,17 +60,109 @@ func NewRouter(claudeBin, codexBin string, opts ...AnthropicOption) *Router {
 	return r
 }
 
+// UseOpenAICompat routes models named "<prefix>:<name>" to c. A nil c
+// leaves the backend disabled; an empty prefix means
+// DefaultCompatPrefix.
+func (r *Router) UseOpenAICompat(prefix string, c *OpenAICompat) {
+	r.CompatPrefix = prefix
+	// Same nil-concrete-in-interface guard as NewRouter.
+	if c != nil {
+		r.OpenAICompat = c
+	}
+}
+
+// UseExecTemplate routes models named "<name>" or "<name>:<model>",
+// where <name> is t's name, to t.
+func (r *Router) UseExecTemplate(t *ExecTemplate) {
+	if r.Templates == nil {
+		r.Templates = make(map[string]Agent)
+	}
+	r.Templates[t.Name()] = t
+}
+
+// routePrefixed resolves a model naming a command template ("<name>"
+// or "<name>:<model>") or carrying the OpenAI-compatible prefix
+// ("<prefix>:<model>") to its backend and the model it receives. ok is
+// false for any other model; err is set when the OpenAI-compatible
+// prefix is used with no endpoint configured.
+func (r *Router) routePrefixed(model Model) (a Agent, name Model, ok bool, err error) {
+	prefix, rest, hasColon := strings.Cut(string(model), ":")
+	if t, found := r.Templates[prefix]; found {
+		return t, Model(rest), true, nil
+	}
+	compatPrefix := r.CompatPrefix
+	if compatPrefix == "" {
+		compatPrefix = DefaultCompatPrefix
+	}
+	if !hasColon || prefix != compatPrefix {
+		return nil, "", false, nil
+	}
+	if r.OpenAICompat == nil {
+		return nil, "", true, fmt.Errorf("model %q needs providers.openai_compat.base_url to be set", model)
+	}
+	return r.OpenAICompat, Model(rest), true, nil
+}
+
+// resolveAlias returns the model an alias maps to, or model unchanged
+// when it is no alias. Lookup is case-insensitive.
+func resolveAlias(aliases map[string]string, model Model) Model {
+	if id, ok := aliases[string(model)]; ok {
+		return Model(id)
+	}
+	if id, ok := aliases[strings.ToLower(string(model))]; ok {
+		return Model(id)
+	}
+	return model
+}
+
 // Name returns "router".
 func (r *Router) Name() string { return "router" }
 
 // Session starts an interactive session. Dispatches based on the model
 // extracted from extraArgs (--model / -m flag), matching Execute behavior.
-// Codex models route to Codex CLI; all others route to Claude CLI.
+// Codex models route to Codex CLI; all others to toolAgent.
 func (r *Router) Session(ctx context.Context, systemPrompt string, extraArgs []string) error {
-	if model := extractModelArg(extraArgs); Model(model).IsCodex() {
+	model := Model(extractModelArg(extraArgs))
+	if resolved := resolveAlias(r.Aliases, model); resolved != model {
+		model = resolved
+		extraArgs = replaceModelArg(extraArgs, string(model))
+	}
+	if a, _, ok, err := r.routePrefixed(model); ok {
+		if err != nil {
+			return err
+		}
+		return a.Session(ctx, systemPrompt, extraArgs)
+	}
+	if model.IsCodex() {
 		return r.Codex.Session(ctx, systemPrompt, extraArgs)
 	}
-	return r.Claude.Session(ctx, systemPrompt, extraArgs)
+	return r.toolAgent().Session(ctx, systemPrompt, extraArgs)
+}
+
+// SetStdout directs the Session and Execute output of every backend to
+// w, e.g. stderr while stdout carries an event stream.
+func (r *Router) SetStdout(w io.Writer) {
+	r.Claude.Stdout = w
+	r.Codex.Stdout = w
+	if a, ok := r.Anthropic.(*Anthropic); ok {
+		a.stdout = w
+	}
+	for _, t := range r.Templates {
+		if e, ok := t.(*ExecTemplate); ok {
+			e.stdout = w
+		}
+	}
+}
+
+// toolAgent returns the backend for tool-using claude-family work: the
+// Claude CLI when its binary is installed or no API credentials are
+// available, and the Anthropic backend's tool-use loop otherwise — so
+// fix and implement sessions work where only an API key is.
+func (r *Router) toolAgent() Agent {
+	if r.Anthropic == nil || r.Claude.installed() {
+		return r.Claude
+	}
+	return r.Anthropic
 }
 
 // extractModelArg scans a CLI arg slice for --model or -m and returns
@@ -65,51 +176,51 @@ func extractModelArg(args []string) string {
 	return ""
 }
 
+// replaceModelArg returns a copy of args with the value of its --model
+// or -m flag set to model.
+func replaceModelArg(args []string, model string) []string {
+	out := slices.Clone(args)
+	for i, a := range out {
+		if (a == "--model" || a == "-m") && i+1 < len(out) {
+			out[i+1] = model
+			break
+		}
+	}
+	return out
+}
+
 // Evaluate dispatches based on the model string.
 // The tools parameter is forwarded to the selected backend.
-// When the Anthropic direct API is selected but fails (auth error,
-// outage, network), it falls back to Claude CLI automatically.
+// Unprefixed models walk their family's fallback chain: when a backend
+// fails (auth error, outage, network) after its retries, the next one
+// is tried automatically. The backend that answered, and those that
+// failed, are recorded on the context's BackendTrace.
 func (r *Router) Evaluate(ctx context.Context, systemPrompt, userPrompt string, model Model, tools ToolPolicy) (string, error) {
-	switch {
-	case model.IsCodex():
-		return r.Codex.Evaluate(ctx, systemPrompt, userPrompt, model, tools)
-	case model.IsClaude() && r.Anthropic != nil:
-		out, err := r.Anthropic.Evaluate(ctx, systemPrompt, userPrompt, model, tools)
-		if err == nil {
-			return out, nil
-		}
-		// Context cancellation or deadline exceeded means the caller is
-		// done — falling back would just add noise and latency.  Check
-		// both the context and the error chain: the context reflects the
-		// caller's intent, while the error chain catches transport-level
-		// timeouts where ctx.Err() may still be nil.
-		if ctx.Err() != nil ||
-			errors.Is(err, context.Canceled) ||
-			errors.Is(err, context.DeadlineExceeded) {
+	model = resolveAlias(r.Aliases, model)
+	if a, name, ok, err := r.routePrefixed(model); ok {
+		if err != nil {
 			return "", err
 		}
-		// Anthropic failed — fall back to Claude CLI so a bad key or
-		// transient outage doesn't hard-fail the entire check run.
-		if os.Getenv("BONSAI_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "[bonsai:debug] anthropic failed, falling back to claude CLI: %v\n", err)
-		}
-		out, fallbackErr := r.Claude.Evaluate(ctx, systemPrompt, userPrompt, model, tools)
-		if fallbackErr != nil {
-			return "", errors.Join(err, fallbackErr)
-		}
-		return out, nil
-	default:
-		return r.Claude.Evaluate(ctx, systemPrompt, userPrompt, model, tools)
+		return a.Evaluate(ctx, systemPrompt, userPrompt, name, tools)
 	}
+	return r.evaluateChain(ctx, systemPrompt, userPrompt, model, tools)
 }
 
 // Execute dispatches based on the model string.
-// Codex supports autonomous tool-use; for claude-family models the
-// Claude CLI is used (the Anthropic direct API does not support
-// execute mode).
+// Codex models run the Codex CLI; others the Claude CLI, or the
+// Anthropic tool-use loop when the CLI is not installed (see
+// toolAgent). Template models run the template's execute_args;
+// prefixed OpenAI-compatible models are rejected by that backend.
 func (r *Router) Execute(ctx context.Context, systemPrompt, userPrompt string, model Model) error {
+	model = resolveAlias(r.Aliases, model)
+	if a, name, ok, err := r.routePrefixed(model); ok {
+		if err != nil {
+			return err
+		}
+		return a.Execute(ctx, systemPrompt, userPrompt, name)
+	}
 	if model.IsCodex() {
 		return r.Codex.Execute(ctx, systemPrompt, userPrompt, model)
 	}
-	return r.Claude.Execute(ctx, systemPrompt, userPrompt, model)
+	return r.toolAgent().Execute(ctx, systemPrompt, userPrompt, model)
 }
@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+
+	"github.com/pithecene-io/bonsai/internal/gitutil"
+)
+
+// defaultMaxTurns bounds the model round-trips for one prompt when
+// ToolConfig.MaxTurns is unset.
+const defaultMaxTurns = 50
+
+// ToolConfig configures the Anthropic backend's native tool-use loop,
+// which backs Execute and Session: read_file, list_dir, grep, edit_file,
+// apply_patch, and run_command, all confined to Root.
+type ToolConfig struct {
+	Root            string        // directory the tools are confined to; empty = git toplevel of the working directory
+	AllowedCommands []string      // argv prefixes run_command accepts, e.g. "go test"; empty = none
+	MaxTurns        int           // model round-trips per prompt; 0 = defaultMaxTurns
+	CommandTimeout  time.Duration // deadline per run_command or apply_patch; 0 = 5 minutes
+}
+
+// toolbox creates the toolbox for the configured root, defaulting to
+// the repository containing the working directory — which the CLI
+// backends run in too, including worktrees bonsai has changed into.
+func (tc ToolConfig) toolbox() (*toolbox, error) {
+	root := tc.Root
+	if root == "" {
+		root = "."
+		if top, err := gitutil.ShowToplevel("."); err == nil {
+			root = top
+		}
+	}
+	tb, err := newToolbox(root, tc.AllowedCommands)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic tools: %w", err)
+	}
+	tb.timeout = tc.CommandTimeout
+	return tb, nil
+}
+
+// maxTurns returns the round-trip limit per prompt.
+func (tc ToolConfig) maxTurns() int {
+	if tc.MaxTurns > 0 {
+		return tc.MaxTurns
+	}
+	return defaultMaxTurns
+}
+
+// conversation is one tool-using exchange with the model: the request
+// parameters, with the message history so far, and the toolbox.
+type conversation struct {
+	a       *Anthropic
+	tb      *toolbox
+	params  anthropic.MessageNewParams
+	reqOpts []option.RequestOption
+	out     io.Writer // model text is streamed here
+}
+
+// newConversation prepares a tool-using exchange under systemPrompt.
+func (a *Anthropic) newConversation(systemPrompt string, model Model, out io.Writer) (*conversation, error) {
+	tb, err := a.tools.toolbox()
+	if err != nil {
+		return nil, err
+	}
+	params, reqOpts := a.newParams(systemPrompt, model)
+	params.Tools = tb.toolDefs()
+	return &conversation{a: a, tb: tb, params: params, reqOpts: reqOpts, out: out}, nil
+}
+
+// send adds a user prompt and runs the tool-use loop: each tool call
+// the model makes is run and its result sent back, until the model ends
+// its turn or the turn limit is reached.
+func (c *conversation) send(ctx context.Context, prompt string) error {
+	c.params.Messages = append(c.params.Messages, anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)))
+	for range c.a.tools.maxTurns() {
+		msg, err := c.a.send(ctx, c.params, c.reqOpts)
+		if err != nil {
+			return err
+		}
+		c.params.Messages = append(c.params.Messages, msg.ToParam())
+		results := c.handle(ctx, msg)
+		if msg.StopReason != anthropic.StopReasonToolUse {
+			return nil
+		}
+		c.params.Messages = append(c.params.Messages, anthropic.NewUserMessage(results...))
+	}
+	return fmt.Errorf("anthropic: no final answer after %d turns (tools.max_turns)", c.a.tools.maxTurns())
+}
+
+// handle streams the text of msg and runs its tool calls, returning one
+// result block per call.
+func (c *conversation) handle(ctx context.Context, msg *anthropic.Message) []anthropic.ContentBlockParamUnion {
+	var results []anthropic.ContentBlockParamUnion
+	for i := range msg.Content {
+		switch block := msg.Content[i].AsAny().(type) {
+		case anthropic.TextBlock:
+			fmt.Fprintln(c.out, block.Text)
+		case anthropic.ToolUseBlock:
+			out, err := c.tb.run(ctx, block.Name, block.Input)
+			if os.Getenv("BONSAI_DEBUG") != "" {
+				fmt.Fprintf(os.Stderr, "[bonsai:debug] anthropic tool %s %s (err=%v)\n", block.Name, block.Input, err)
+			}
+			if err != nil {
+				results = append(results, anthropic.NewToolResultBlock(block.ID, err.Error(), true))
+				continue
+			}
+			results = append(results, anthropic.NewToolResultBlock(block.ID, out, false))
+		}
+	}
+	return results
+}
+
+// Execute runs the prompt through the native tool-use loop: the model
+// reads, searches, and edits the repository and runs allow-listed
+// commands until it is done. Its text streams to stdout.
+func (a *Anthropic) Execute(ctx context.Context, systemPrompt, userPrompt string, model Model) error {
+	c, err := a.newConversation(systemPrompt, model, stdoutOr(a.stdout))
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, userPrompt)
+}
+
+// Session runs an interactive tool-using conversation on the terminal,
+// one stdin line per prompt, until EOF or "/exit". Of the Claude CLI
+// flags in extraArgs, --model/-m selects the model and -p/--print runs
+// its prompt once (like Execute); the rest are ignored.
+func (a *Anthropic) Session(ctx context.Context, systemPrompt string, extraArgs []string) error {
+	model := Model(extractModelArg(extraArgs))
+	if prompt := extractPrintArg(extraArgs); prompt != "" {
+		return a.Execute(ctx, systemPrompt, prompt, model)
+	}
+	c, err := a.newConversation(systemPrompt, model, stdoutOr(a.stdout))
+	if err != nil {
+		return err
+	}
+	return c.repl(ctx, os.Stdin, os.Stderr)
+}
+
+// repl reads prompts from in, prompting on tty, until EOF or "/exit".
+// A failed turn is reported and the session continues.
+func (c *conversation) repl(ctx context.Context, in io.Reader, tty io.Writer) error {
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(tty, "> ")
+		if !sc.Scan() {
+			return sc.Err()
+		}
+		line := strings.TrimSpace(sc.Text())
+		switch line {
+		case "":
+			continue
+		case "/exit":
+			return nil
+		}
+		if err := c.send(ctx, line); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			fmt.Fprintf(tty, "error: %v\n", err)
+		}
+	}
+}
+
+// extractPrintArg returns the prompt following -p or --print in a
+// Claude CLI arg slice, or "" when there is none.
+func extractPrintArg(args []string) string {
+	for i, a := range args {
+		if (a == "-p" || a == "--print") && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package agent_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+)
+
+// toolUseResponse is a Messages API response calling edit_file.
+const toolUseResponse = `{
+	"id": "msg_1",
+	"type": "message",
+	"role": "assistant",
+	"model": "claude-sonnet-4-6",
+	"content": [
+		{"type": "text", "text": "Fixing the typo."},
+		{"type": "tool_use", "id": "toolu_1", "name": "edit_file",
+		 "input": {"path": "README.md", "old_string": "teh", "new_string": "the"}}
+	],
+	"stop_reason": "tool_use",
+	"usage": {"input_tokens": 10, "output_tokens": 5}
+}`
+
+// toolServer serves the given responses in order and records each
+// request body.
+func toolServer(t *testing.T, responses ...string) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(raw, &body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[min(len(bodies), len(responses))-1]))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func TestAnthropic_ExecuteRunsToolLoop(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("teh readme\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	srv, bodies := toolServer(t, toolUseResponse, anthropicStubResponse())
+	t.Setenv("HOME", t.TempDir())
+
+	a := agent.NewAnthropic(
+		agent.WithAPIKey("sk-test"),
+		agent.WithBaseURL(srv.URL),
+		agent.WithTools(agent.ToolConfig{Root: root}),
+	)
+	if err := a.Execute(t.Context(), "sys", "fix the typo", agent.Model("sonnet")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(root, "README.md"))
+	if string(data) != "the readme\n" {
+		t.Errorf("README.md = %q, want edit applied", data)
+	}
+	if len(*bodies) != 2 {
+		t.Fatalf("requests = %d, want 2 (tool call, then final answer)", len(*bodies))
+	}
+	if tools, _ := (*bodies)[0]["tools"].([]any); len(tools) == 0 {
+		t.Error("first request should declare the tools")
+	}
+	// The second request replays the tool call and carries its result.
+	msgs, _ := (*bodies)[1]["messages"].([]any)
+	if len(msgs) != 3 {
+		t.Fatalf("messages = %d, want user, assistant, tool result", len(msgs))
+	}
+	last, _ := json.Marshal(msgs[2])
+	if !strings.Contains(string(last), `"tool_use_id":"toolu_1"`) || !strings.Contains(string(last), "edited README.md") {
+		t.Errorf("tool result message = %s", last)
+	}
+}
+
+func TestAnthropic_ExecuteStopsAtMaxTurns(t *testing.T) {
+	root := t.TempDir()
+	srv, bodies := toolServer(t, toolUseResponse)
+	t.Setenv("HOME", t.TempDir())
+
+	a := agent.NewAnthropic(
+		agent.WithAPIKey("sk-test"),
+		agent.WithBaseURL(srv.URL),
+		agent.WithTools(agent.ToolConfig{Root: root, MaxTurns: 2}),
+	)
+	err := a.Execute(t.Context(), "sys", "loop forever", agent.Model("sonnet"))
+	if err == nil || !strings.Contains(err.Error(), "after 2 turns") {
+		t.Errorf("err = %v, want turn limit", err)
+	}
+	if len(*bodies) != 2 {
+		t.Errorf("requests = %d, want 2", len(*bodies))
+	}
+}
+
+func TestRouter_ExecuteFallsBackToAPIToolLoop(t *testing.T) {
+	api := &agent.MockAgent{NameVal: "anthropic"}
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.Anthropic = api
+
+	if err := r.Execute(t.Context(), "sys", "user", agent.Model("sonnet")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(api.ExecuteCalls) != 1 {
+		t.Errorf("Anthropic ExecuteCalls = %d, want 1 when the claude binary is missing", len(api.ExecuteCalls))
+	}
+}
+
+func TestAnthropic_ExecuteDefaultsEmptyModel(t *testing.T) {
+	srv, bodies := toolServer(t, anthropicStubResponse())
+	t.Setenv("HOME", t.TempDir())
+
+	a := agent.NewAnthropic(
+		agent.WithAPIKey("sk-test"),
+		agent.WithBaseURL(srv.URL),
+		agent.WithModelAliases(map[string]string{"sonnet": "claude-sonnet-4-6"}),
+		agent.WithTools(agent.ToolConfig{Root: t.TempDir()}),
+	)
+	if err := a.Session(t.Context(), "sys", []string{"-p", "scaffold ARCH_INDEX.md"}); err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if got := (*bodies)[0]["model"]; got != "claude-sonnet-4-6" {
+		t.Errorf("model = %v, want the sonnet alias for an empty model", got)
+	}
+}
+
+func TestRouter_SessionResolvesAliases(t *testing.T) {
+	tmpl := &agent.MockAgent{NameVal: "vendor"}
+	r := agent.NewRouter("nonexistent-claude", "nonexistent-codex")
+	r.Templates = map[string]agent.Agent{"vendor": tmpl}
+	r.Aliases = map[string]string{"fast": "vendor:small"}
+
+	args := []string{"--model", "fast", "--verbose"}
+	if err := r.Session(t.Context(), "sys", args); err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if len(tmpl.SessionCalls) != 1 {
+		t.Fatalf("template SessionCalls = %d, want 1 via the alias", len(tmpl.SessionCalls))
+	}
+	if got := tmpl.SessionCalls[0].ExtraArgs[1]; got != "vendor:small" {
+		t.Errorf("--model = %q, want the resolved vendor:small", got)
+	}
+	if args[1] != "fast" {
+		t.Error("caller's args should not be modified")
+	}
+}
@@ -0,0 +1,412 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Tool output limits keep a single result from flooding the context.
+const (
+	maxToolOutput  = 64 << 10 // bytes of file or command output per result
+	maxGrepMatches = 200
+	commandTimeout = 5 * time.Minute // default per-command deadline
+)
+
+// commandEnv names the variables passed through to commands; API keys,
+// cloud credentials, and the rest of bonsai's environment are not.
+var commandEnv = []string{
+	"PATH", "HOME", "USER", "LANG", "LC_ALL", "TERM", "TMPDIR",
+	"GOPATH", "GOROOT", "GOCACHE", "GOMODCACHE", "GOFLAGS", "GOPROXY", "GOTOOLCHAIN",
+}
+
+// execFlags let a command run an arbitrary program (go test -exec,
+// go build -toolexec), which would defeat the prefix allow-list.
+var execFlags = []string{"-exec", "-toolexec"}
+
+// errUnknownTool is returned for tool names outside the toolbox.
+var errUnknownTool = errors.New("unknown tool")
+
+// toolbox runs the native tool set of the Anthropic tool-use loop. Every
+// path is confined to root, and run_command accepts only argv lists
+// starting with an allowed prefix and never runs a shell.
+type toolbox struct {
+	root    string        // absolute, symlinks resolved
+	allowed [][]string    // argv prefixes run_command accepts
+	timeout time.Duration // per-command deadline; 0 = commandTimeout
+}
+
+// newToolbox creates a toolbox confined to root. allowed holds
+// whitespace-separated command prefixes (e.g. "go test").
+func newToolbox(root string, allowed []string) (*toolbox, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if abs, err = filepath.EvalSymlinks(abs); err != nil {
+		return nil, err
+	}
+	tb := &toolbox{root: abs}
+	for _, c := range allowed {
+		if f := strings.Fields(c); len(f) > 0 {
+			tb.allowed = append(tb.allowed, f)
+		}
+	}
+	return tb, nil
+}
+
+// toolDefs returns the tool definitions sent with each request. The
+// run_command description lists the allowed prefixes so the model does
+// not waste turns on rejected commands.
+func (tb *toolbox) toolDefs() []anthropic.ToolUnionParam {
+	allowed := make([]string, len(tb.allowed))
+	for i, a := range tb.allowed {
+		allowed[i] = strings.Join(a, " ")
+	}
+	runDesc := "Run a command in the repository root without a shell. Only commands starting with one of these prefixes are allowed: " + strings.Join(allowed, ", ")
+	if len(allowed) == 0 {
+		runDesc = "Run a command in the repository root. No commands are currently allowed."
+	}
+	defs := []struct {
+		name, desc string
+		props      map[string]any
+		required   []string
+	}{
+		{
+			"read_file", "Read a file. Paths are relative to the repository root.",
+			map[string]any{"path": strProp("file path")},
+			[]string{"path"},
+		},
+		{
+			"list_dir", "List a directory; subdirectories end in /.",
+			map[string]any{"path": strProp("directory path; empty for the root")},
+			nil,
+		},
+		{
+			"grep", "Search files for a regular expression (RE2 syntax). Returns path:line: text.",
+			map[string]any{"pattern": strProp("regular expression"), "path": strProp("file or directory to search; empty for the root")},
+			[]string{"pattern"},
+		},
+		{
+			"edit_file", "Replace old_string, which must occur exactly once, with new_string. With an empty old_string, create the file with new_string as its content.",
+			map[string]any{"path": strProp("file path"), "old_string": strProp("exact text to replace"), "new_string": strProp("replacement text")},
+			[]string{"path", "old_string", "new_string"},
+		},
+		{
+			"apply_patch", "Apply a unified diff (git apply) to the repository.",
+			map[string]any{"patch": strProp("unified diff")},
+			[]string{"patch"},
+		},
+		{
+			"run_command", runDesc,
+			map[string]any{"argv": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "program and arguments"}},
+			[]string{"argv"},
+		},
+	}
+	tools := make([]anthropic.ToolUnionParam, len(defs))
+	for i, d := range defs {
+		tools[i] = anthropic.ToolUnionParamOfTool(anthropic.ToolInputSchemaParam{Properties: d.props, Required: d.required}, d.name)
+		tools[i].OfTool.Description = anthropic.String(d.desc)
+	}
+	return tools
+}
+
+func strProp(desc string) map[string]any {
+	return map[string]any{"type": "string", "description": desc}
+}
+
+// toolInput is the union of every tool's input fields.
+type toolInput struct {
+	Path      string   `json:"path"`
+	Pattern   string   `json:"pattern"`
+	OldString string   `json:"old_string"`
+	NewString string   `json:"new_string"`
+	Patch     string   `json:"patch"`
+	Argv      []string `json:"argv"`
+}
+
+// run executes the named tool. A returned error is reported to the
+// model as a failed tool result, not to the caller.
+func (tb *toolbox) run(ctx context.Context, name string, raw json.RawMessage) (string, error) {
+	var in toolInput
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return "", fmt.Errorf("invalid input: %w", err)
+	}
+	switch name {
+	case "read_file":
+		return tb.readFile(in.Path)
+	case "list_dir":
+		return tb.listDir(in.Path)
+	case "grep":
+		return tb.grep(in.Pattern, in.Path)
+	case "edit_file":
+		return tb.editFile(in.Path, in.OldString, in.NewString)
+	case "apply_patch":
+		return tb.command(ctx, []string{"git", "apply", "--whitespace=nowarn", "-"}, in.Patch)
+	case "run_command":
+		return tb.runCommand(ctx, in.Argv)
+	}
+	return "", fmt.Errorf("%w %q", errUnknownTool, name)
+}
+
+// resolve maps a root-relative (or absolute) path to an absolute path
+// inside root. Symlinks along the existing part of the path are
+// resolved, so a link cannot lead outside root either.
+func (tb *toolbox) resolve(p string) (string, error) {
+	abs := filepath.Clean(p)
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(tb.root, abs)
+	}
+	real, err := evalExisting(abs)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(tb.root, real)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("path %q is outside the repository", p)
+	}
+	return real, nil
+}
+
+// evalExisting resolves symlinks in the longest existing prefix of path
+// and appends the rest unchanged.
+func evalExisting(path string) (string, error) {
+	real, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return real, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+	realParent, err := evalExisting(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(realParent, filepath.Base(path)), nil
+}
+
+// readFile returns a file's contents, truncated to maxToolOutput.
+func (tb *toolbox) readFile(p string) (string, error) {
+	path, err := tb.resolve(p)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return truncateOutput(data), nil
+}
+
+// listDir lists a directory, marking subdirectories with a trailing /.
+func (tb *toolbox) listDir(p string) (string, error) {
+	path, err := tb.resolve(p)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(e.Name())
+		if e.IsDir() {
+			b.WriteByte('/')
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// grep searches the text files under p for pattern, skipping .git and
+// binary files, and reports up to maxGrepMatches matching lines.
+func (tb *toolbox) grep(pattern, p string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	start, err := tb.resolve(p)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	err = filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || len(matches) >= maxGrepMatches {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		matches = append(matches, tb.grepFile(re, path, maxGrepMatches-len(matches))...)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "no matches", nil
+	}
+	return strings.Join(matches, "\n"), nil
+}
+
+// grepFile returns up to limit "path:line: text" matches in one file.
+// Unreadable and binary files yield none, as do symlinks leading
+// outside root: the walk does not resolve them, so the file is read
+// through resolve like every other tool path.
+func (tb *toolbox) grepFile(re *regexp.Regexp, path string, limit int) []string {
+	real, err := tb.resolve(path)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(real)
+	if err != nil || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil
+	}
+	rel, _ := filepath.Rel(tb.root, path)
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, len(data)+1)
+	for n := 1; sc.Scan() && len(out) < limit; n++ {
+		if re.Match(sc.Bytes()) {
+			out = append(out, fmt.Sprintf("%s:%d: %s", filepath.ToSlash(rel), n, sc.Text()))
+		}
+	}
+	return out
+}
+
+// editFile replaces the single occurrence of oldString with newString,
+// or creates the file when oldString is empty. Files under .git are
+// never written.
+func (tb *toolbox) editFile(p, oldString, newString string) (string, error) {
+	path, err := tb.resolve(p)
+	if err != nil {
+		return "", err
+	}
+	if rel, _ := filepath.Rel(tb.root, path); strings.HasPrefix(filepath.ToSlash(rel)+"/", ".git/") {
+		return "", fmt.Errorf("path %q is inside .git", p)
+	}
+	if oldString == "" {
+		if _, err := os.Stat(path); err == nil {
+			return "", fmt.Errorf("%s exists; pass old_string to edit it", p)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", err
+		}
+		return "created " + p, os.WriteFile(path, []byte(newString), 0o644)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	switch n := strings.Count(string(data), oldString); n {
+	case 0:
+		return "", fmt.Errorf("old_string not found in %s", p)
+	case 1:
+	default:
+		return "", fmt.Errorf("old_string occurs %d times in %s; include more context", n, p)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	edited := strings.Replace(string(data), oldString, newString, 1)
+	return "edited " + p, os.WriteFile(path, []byte(edited), info.Mode().Perm())
+}
+
+// runCommand runs argv when it starts with an allowed prefix and
+// passes no flag that runs another program. The allow-list is still
+// only as narrow as its prefixes: "make" runs any make target, and
+// "go test" runs the repository's test code.
+func (tb *toolbox) runCommand(ctx context.Context, argv []string) (string, error) {
+	if !tb.commandAllowed(argv) {
+		return "", fmt.Errorf("command %q is not allowed", strings.Join(argv, " "))
+	}
+	for _, a := range argv[1:] {
+		flag, _, _ := strings.Cut(strings.Replace(a, "--", "-", 1), "=")
+		if slices.Contains(execFlags, flag) {
+			return "", fmt.Errorf("command %q is not allowed: %s runs another program", strings.Join(argv, " "), flag)
+		}
+	}
+	return tb.command(ctx, argv, "")
+}
+
+// commandAllowed reports whether argv starts with an allowed prefix.
+func (tb *toolbox) commandAllowed(argv []string) bool {
+	for _, prefix := range tb.allowed {
+		if len(argv) >= len(prefix) && slices.Equal(argv[:len(prefix)], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// command runs argv in root with stdin, returning combined output. A
+// non-zero exit is a result for the model to act on, so it is reported
+// in the output rather than as an error.
+func (tb *toolbox) command(ctx context.Context, argv []string, stdin string) (string, error) {
+	timeout := tb.timeout
+	if timeout <= 0 {
+		timeout = commandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = tb.root
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.Env = minimalEnv()
+	// Test runners and build tools fork helpers that can hold the
+	// output pipe open after the deadline kills the parent.
+	cmd.WaitDelay = evalWaitDelay
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return truncateOutput(out.Bytes()) + "\n" + exitErr.Error(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return truncateOutput(out.Bytes()), nil
+}
+
+// minimalEnv returns the commandEnv subset of the environment.
+func minimalEnv() []string {
+	var env []string
+	for _, name := range commandEnv {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// truncateOutput caps tool output at maxToolOutput bytes.
+func truncateOutput(data []byte) string {
+	if len(data) <= maxToolOutput {
+		return string(data)
+	}
+	return fmt.Sprintf("%s\n[truncated %d bytes]", data[:maxToolOutput], len(data)-maxToolOutput)
+}
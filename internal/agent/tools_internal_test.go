@@ -0,0 +1,161 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testToolbox returns a toolbox over a temp dir holding main.go.
+func testToolbox(t *testing.T, allowed ...string) *toolbox {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	tb, err := newToolbox(root, allowed)
+	if err != nil {
+		t.Fatalf("newToolbox: %v", err)
+	}
+	return tb
+}
+
+func runTool(t *testing.T, tb *toolbox, name string, input map[string]any) (string, error) {
+	t.Helper()
+	raw, err := json.Marshal(input)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return tb.run(t.Context(), name, raw)
+}
+
+func TestToolbox_ConfinesPaths(t *testing.T) {
+	tb := testToolbox(t)
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(tb.root, "escape")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	for _, p := range []string{"../x", "/etc/passwd", "escape/new.go", "sub/../../x"} {
+		if _, err := tb.resolve(p); err == nil || !strings.Contains(err.Error(), "outside the repository") {
+			t.Errorf("resolve(%q) err = %v, want outside the repository", p, err)
+		}
+	}
+	for _, p := range []string{"", ".", "main.go", "new/dir/file.go"} {
+		if _, err := tb.resolve(p); err != nil {
+			t.Errorf("resolve(%q): %v", p, err)
+		}
+	}
+}
+
+func TestToolbox_ReadListGrep(t *testing.T) {
+	tb := testToolbox(t)
+
+	if out, err := runTool(t, tb, "read_file", map[string]any{"path": "main.go"}); err != nil || !strings.Contains(out, "func main") {
+		t.Errorf("read_file = %q, %v", out, err)
+	}
+	if out, err := runTool(t, tb, "list_dir", map[string]any{}); err != nil || out != "main.go\n" {
+		t.Errorf("list_dir = %q, %v", out, err)
+	}
+	if out, err := runTool(t, tb, "grep", map[string]any{"pattern": `func \w+`}); err != nil || out != "main.go:3: func main() {}" {
+		t.Errorf("grep = %q, %v", out, err)
+	}
+	if _, err := runTool(t, tb, "delete_repo", map[string]any{}); err == nil {
+		t.Error("unknown tool should fail")
+	}
+}
+
+func TestToolbox_GrepSkipsLinksOutsideRoot(t *testing.T) {
+	tb := testToolbox(t)
+	secret := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(secret, []byte("PRIVATE KEY\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(tb.root, "key.txt")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	out, err := runTool(t, tb, "grep", map[string]any{"pattern": "PRIVATE"})
+	if err != nil || out != "no matches" {
+		t.Errorf("grep = %q, %v; want the link outside the root skipped", out, err)
+	}
+}
+
+func TestToolbox_EditFile(t *testing.T) {
+	tb := testToolbox(t)
+
+	if _, err := runTool(t, tb, "edit_file", map[string]any{"path": "main.go", "old_string": "func main() {}", "new_string": "func main() { run() }"}); err != nil {
+		t.Fatalf("edit_file: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tb.root, "main.go"))
+	if !strings.Contains(string(data), "run()") {
+		t.Errorf("main.go = %q, want edit applied", data)
+	}
+
+	tests := []struct {
+		name  string
+		input map[string]any
+	}{
+		{"missing", map[string]any{"path": "main.go", "old_string": "nope", "new_string": "x"}},
+		{"ambiguous", map[string]any{"path": "main.go", "old_string": "main", "new_string": "x"}},
+		{"create existing", map[string]any{"path": "main.go", "old_string": "", "new_string": "x"}},
+		{"git dir", map[string]any{"path": ".git/config", "old_string": "", "new_string": "x"}},
+	}
+	for _, tt := range tests {
+		if _, err := runTool(t, tb, "edit_file", tt.input); err == nil {
+			t.Errorf("%s: edit_file should fail", tt.name)
+		}
+	}
+
+	if _, err := runTool(t, tb, "edit_file", map[string]any{"path": "pkg/new.go", "old_string": "", "new_string": "package pkg\n"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tb.root, "pkg", "new.go")); err != nil {
+		t.Errorf("created file missing: %v", err)
+	}
+}
+
+func TestToolbox_RunCommandAllowList(t *testing.T) {
+	tb := testToolbox(t, "echo hello", "false")
+
+	if out, err := runTool(t, tb, "run_command", map[string]any{"argv": []string{"echo", "hello", "world"}}); err != nil || out != "hello world\n" {
+		t.Errorf("allowed command = %q, %v", out, err)
+	}
+	for _, argv := range [][]string{{"echo", "bye"}, {"echo"}, {"rm", "-rf", "."}, {}} {
+		if _, err := runTool(t, tb, "run_command", map[string]any{"argv": argv}); err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("run_command(%v) err = %v, want not allowed", argv, err)
+		}
+	}
+	// A failing command is a result for the model, not a tool error.
+	if out, err := runTool(t, tb, "run_command", map[string]any{"argv": []string{"false"}}); err != nil || !strings.Contains(out, "exit status 1") {
+		t.Errorf("failing command = %q, %v; want exit status in output", out, err)
+	}
+}
+
+func TestToolbox_RunCommandRejectsExecFlags(t *testing.T) {
+	tb := testToolbox(t, "go test")
+	for _, argv := range [][]string{
+		{"go", "test", "-exec", "/bin/sh", "./..."},
+		{"go", "test", "--toolexec=/tmp/x", "./..."},
+	} {
+		if _, err := runTool(t, tb, "run_command", map[string]any{"argv": argv}); err == nil || !strings.Contains(err.Error(), "runs another program") {
+			t.Errorf("run_command(%v) err = %v, want exec flag rejected", argv, err)
+		}
+	}
+}
+
+func TestToolbox_RunCommandMinimalEnv(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "sk-secret")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "aws-secret")
+	tb := testToolbox(t, "env")
+
+	out, err := runTool(t, tb, "run_command", map[string]any{"argv": []string{"env"}})
+	if err != nil {
+		t.Fatalf("run_command: %v", err)
+	}
+	if strings.Contains(out, "secret") || !strings.Contains(out, "PATH=") {
+		t.Errorf("env = %q, want PATH only, no credentials", out)
+	}
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// BackendTrace records which Router backends served, and which failed,
+// the Evaluate calls made with a context returned by WithBackendTrace.
+// Safe for concurrent use.
+type BackendTrace struct {
+	mu        sync.Mutex
+	backend   string
+	fallbacks []string
+}
+
+// Backend returns the backend that answered the most recent call, or
+// "" when no call has succeeded through the Router.
+func (t *BackendTrace) Backend() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.backend
+}
+
+// Fallbacks returns the backends whose attempts failed before another
+// answered, one entry per failed attempt, in call order.
+func (t *BackendTrace) Fallbacks() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.fallbacks...)
+}
+
+type backendTraceKey struct{}
+
+// WithBackendTrace returns a context that records the backend chosen
+// for every Router.Evaluate call made with it, including repair
+// re-prompts and diff chunks.
+func WithBackendTrace(ctx context.Context) (context.Context, *BackendTrace) {
+	t := &BackendTrace{}
+	return context.WithValue(ctx, backendTraceKey{}, t), t
+}
+
+// recordBackend notes the backend that answered on the trace carried by
+// ctx, if any.
+func recordBackend(ctx context.Context, name string) {
+	if t, ok := ctx.Value(backendTraceKey{}).(*BackendTrace); ok {
+		t.mu.Lock()
+		t.backend = name
+		t.mu.Unlock()
+	}
+}
+
+// recordFallback notes a failed backend attempt on the trace carried by
+// ctx, if any.
+func recordFallback(ctx context.Context, name string) {
+	if t, ok := ctx.Value(backendTraceKey{}).(*BackendTrace); ok {
+		t.mu.Lock()
+		t.fallbacks = append(t.fallbacks, name)
+		t.mu.Unlock()
+	}
+}
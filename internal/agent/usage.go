@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// Usage counts the tokens consumed by one or more model calls.
+// InputTokens includes prompt-cache reads and writes, which the
+// backends report separately; they are priced at the input rate.
+type Usage struct {
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens + o.InputTokens,
+		OutputTokens: u.OutputTokens + o.OutputTokens,
+	}
+}
+
+// UsageMeter accumulates the usage reported by backends for calls made
+// with a context returned by WithUsageMeter. Safe for concurrent use.
+type UsageMeter struct {
+	mu    sync.Mutex
+	total Usage
+}
+
+// Total returns the usage accumulated so far.
+func (m *UsageMeter) Total() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.total
+}
+
+func (m *UsageMeter) add(u Usage) {
+	m.mu.Lock()
+	m.total = m.total.Add(u)
+	m.mu.Unlock()
+}
+
+type usageMeterKey struct{}
+
+// WithUsageMeter returns a context that records the token usage of
+// every Evaluate call made with it, including repair re-prompts and
+// router fallbacks. Backends that cannot report usage (codex) record
+// nothing.
+func WithUsageMeter(ctx context.Context) (context.Context, *UsageMeter) {
+	m := &UsageMeter{}
+	return context.WithValue(ctx, usageMeterKey{}, m), m
+}
+
+// recordUsage adds u to the meter carried by ctx, if any.
+func recordUsage(ctx context.Context, u Usage) {
+	if m, ok := ctx.Value(usageMeterKey{}).(*UsageMeter); ok {
+		m.add(u)
+	}
+}
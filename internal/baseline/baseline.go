@@ -0,0 +1,185 @@
+// Package baseline snapshots accepted findings into a committed file so
+// that only findings introduced after the snapshot fail the gate.
+//
+// Each finding is identified by a fingerprint that is stable across
+// runs: it covers the skill, severity, file path, rule id, and a
+// normalized message, but not line numbers, so unrelated edits that
+// shift code up or down do not resurrect baselined findings.
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// DefaultPath is the repo-relative location of the baseline file.
+const DefaultPath = "ai/baseline.json"
+
+// fileVersion is the on-disk format version.
+const fileVersion = 1
+
+// Entry is a single baselined finding.
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	Skill       string `json:"skill"`
+	Severity    string `json:"severity"`
+	Path        string `json:"path,omitempty"`
+	Rule        string `json:"rule,omitempty"`
+	Message     string `json:"message"`
+}
+
+// File is the baseline file contents. It implements
+// orchestrator.Baseline.
+type File struct {
+	Version  int     `json:"version"`
+	Source   string  `json:"source,omitempty"`
+	Findings []Entry `json:"findings"`
+
+	index map[string]bool
+}
+
+var (
+	digitRun = regexp.MustCompile(`[0-9]+`)
+	spaceRun = regexp.MustCompile(`\s+`)
+)
+
+// normalizeMessage canonicalizes a finding message for fingerprinting:
+// case-folded, whitespace-collapsed, with digit runs (counts, line
+// references) replaced by a placeholder.
+func normalizeMessage(msg string) string {
+	msg = strings.ToLower(strings.TrimSpace(msg))
+	msg = digitRun.ReplaceAllString(msg, "#")
+	return spaceRun.ReplaceAllString(msg, " ")
+}
+
+// Fingerprint returns the stable identity of a finding.
+func Fingerprint(skillName, severity string, f skill.Finding) string {
+	h := sha256.New()
+	for _, part := range []string{
+		skillName,
+		severity,
+		filepath.ToSlash(f.Path),
+		f.Rule,
+		normalizeMessage(f.Message),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// FromReport snapshots every finding in the report, including findings
+// that were already baselined, so re-creating a baseline never drops
+// previously accepted entries. Entries are deduplicated and sorted for
+// stable diffs.
+func FromReport(r *orchestrator.Report) *File {
+	b := &File{Version: fileVersion, Source: r.Source}
+	seen := make(map[string]bool)
+	for i := range r.Results {
+		for _, e := range entriesFor(&r.Results[i]) {
+			if !seen[e.Fingerprint] {
+				seen[e.Fingerprint] = true
+				b.Findings = append(b.Findings, e)
+			}
+		}
+	}
+	sort.Slice(b.Findings, func(i, j int) bool {
+		return entryLess(b.Findings[i], b.Findings[j])
+	})
+	b.buildIndex()
+	return b
+}
+
+// entriesFor returns the active and already-baselined findings of a
+// single result as baseline entries.
+func entriesFor(res *orchestrator.Result) []Entry {
+	var entries []Entry
+	for _, sev := range orchestrator.Severities {
+		for _, f := range res.FindingsFor(sev) {
+			entries = append(entries, newEntry(res.Name, sev, f))
+		}
+	}
+	for _, bf := range res.BaselinedFindings {
+		entries = append(entries, newEntry(res.Name, bf.Severity, bf.Finding))
+	}
+	return entries
+}
+
+// newEntry builds a baseline entry for a finding.
+func newEntry(skillName, severity string, f skill.Finding) Entry {
+	return Entry{
+		Fingerprint: Fingerprint(skillName, severity, f),
+		Skill:       skillName,
+		Severity:    severity,
+		Path:        filepath.ToSlash(f.Path),
+		Rule:        f.Rule,
+		Message:     f.Message,
+	}
+}
+
+// entryLess orders entries by skill, path, then fingerprint.
+func entryLess(x, y Entry) bool {
+	if x.Skill != y.Skill {
+		return x.Skill < y.Skill
+	}
+	if x.Path != y.Path {
+		return x.Path < y.Path
+	}
+	return x.Fingerprint < y.Fingerprint
+}
+
+// Load reads a baseline file. A missing file is reported as an error
+// satisfying errors.Is(err, fs.ErrNotExist).
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b File
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	if b.Version != fileVersion {
+		return nil, fmt.Errorf("baseline %s: unsupported version %d (want %d)", path, b.Version, fileVersion)
+	}
+	b.buildIndex()
+	return &b, nil
+}
+
+// Write saves the baseline to path, creating parent directories.
+func (b *File) Write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create baseline dir: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal baseline: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Contains reports whether the finding is baselined.
+func (b *File) Contains(skillName, severity string, f skill.Finding) bool {
+	return b.index[Fingerprint(skillName, severity, f)]
+}
+
+// Len returns the number of baselined findings.
+func (b *File) Len() int { return len(b.Findings) }
+
+// buildIndex populates the fingerprint lookup set.
+func (b *File) buildIndex() {
+	b.index = make(map[string]bool, len(b.Findings))
+	for _, e := range b.Findings {
+		b.index[e.Fingerprint] = true
+	}
+}
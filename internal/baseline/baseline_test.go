@@ -0,0 +1,86 @@
+package baseline_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/baseline"
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+func TestFingerprint_IgnoresLinesAndNoise(t *testing.T) {
+	a := skill.Finding{Message: "Secret found  on line 12", Path: "cmd/main.go", StartLine: 12}
+	b := skill.Finding{Message: "secret found on line 40", Path: "cmd/main.go", StartLine: 40, EndLine: 41}
+	if baseline.Fingerprint("s", "blocking", a) != baseline.Fingerprint("s", "blocking", b) {
+		t.Error("fingerprint should ignore line numbers, case, and whitespace")
+	}
+
+	c := b
+	c.Path = "cmd/other.go"
+	if baseline.Fingerprint("s", "blocking", b) == baseline.Fingerprint("s", "blocking", c) {
+		t.Error("fingerprint should depend on path")
+	}
+	if baseline.Fingerprint("s", "blocking", a) == baseline.Fingerprint("s", "major", a) {
+		t.Error("fingerprint should depend on severity")
+	}
+	if baseline.Fingerprint("s", "blocking", a) == baseline.Fingerprint("t", "blocking", a) {
+		t.Error("fingerprint should depend on skill")
+	}
+}
+
+func TestFromReport_RoundTrip(t *testing.T) {
+	rep := &orchestrator.Report{
+		Source: "mode:AUDIT",
+		Results: []orchestrator.Result{
+			{
+				Name:             "s1",
+				BlockingFindings: []skill.Finding{{Message: "bad", Path: "a.go", StartLine: 3}},
+				MajorDetails:     []string{"legacy detail"},
+				BaselinedFindings: []orchestrator.BaselinedFinding{
+					{Severity: "warning", Finding: skill.Finding{Message: "old"}},
+				},
+			},
+			{
+				Name:             "s2",
+				BlockingFindings: []skill.Finding{{Message: "dup"}, {Message: "dup"}},
+			},
+		},
+	}
+
+	b := baseline.FromReport(rep)
+	if b.Len() != 4 {
+		t.Fatalf("Len = %d, want 4 (deduplicated, baselined findings retained)", b.Len())
+	}
+
+	path := filepath.Join(t.TempDir(), "ai", "baseline.json")
+	if err := b.Write(path); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	loaded, err := baseline.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !loaded.Contains("s1", "blocking", skill.Finding{Message: "bad", Path: "a.go", StartLine: 99}) {
+		t.Error("moved finding should still match")
+	}
+	if !loaded.Contains("s1", "major", skill.Finding{Message: "legacy detail"}) {
+		t.Error("detail-only finding should match")
+	}
+	if !loaded.Contains("s1", "warning", skill.Finding{Message: "old"}) {
+		t.Error("previously baselined finding should be retained")
+	}
+	if loaded.Contains("s1", "blocking", skill.Finding{Message: "new problem", Path: "a.go"}) {
+		t.Error("new finding should not match")
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	_, err := baseline.Load(filepath.Join(t.TempDir(), "nope.json"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("err = %v, want fs.ErrNotExist", err)
+	}
+}
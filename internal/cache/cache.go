@@ -0,0 +1,180 @@
+// Package cache provides a content-addressed on-disk store for skill
+// evaluation results. Entries are keyed by a SHA-256 digest of every
+// input that can influence an evaluation, so a hit is always safe to
+// reuse and invalidation is implicit: changing any input changes the key.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// formatVersion is mixed into every key. Bump it when the cached
+// payload format changes so stale entries are never decoded.
+const formatVersion = "bonsai-result-cache/v1"
+
+// entryExt is the file extension for cache entries.
+const entryExt = ".json"
+
+// Store is a directory of cache entries laid out as <dir>/<kk>/<key>.json,
+// where kk is the first two hex digits of the key. It is safe for
+// concurrent use by multiple goroutines and processes: writes go through
+// a temp file and an atomic rename.
+type Store struct {
+	dir string
+}
+
+// Open returns a Store rooted at dir, creating it if needed.
+func Open(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, errors.New("cache dir is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// DefaultDir returns the per-user cache directory for evaluation results
+// (e.g. ~/.cache/bonsai/results on Linux).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "bonsai", "results"), nil
+}
+
+// Dir returns the store's root directory.
+func (s *Store) Dir() string { return s.dir }
+
+// Key derives a cache key from the given parts. Each part is
+// length-prefixed before hashing so that part boundaries are
+// unambiguous ("ab"+"c" and "a"+"bc" produce different keys).
+func Key(parts ...string) string {
+	h := sha256.New()
+	var n [8]byte
+	for _, p := range append([]string{formatVersion}, parts...) {
+		binary.BigEndian.PutUint64(n[:], uint64(len(p)))
+		h.Write(n[:])
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the on-disk location for key.
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key+entryExt)
+}
+
+// Get returns the entry for key. A hit refreshes the entry's
+// modification time so Prune evicts least-recently-used entries first.
+func (s *Store) Get(key string) ([]byte, bool) {
+	p := s.path(key)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(p, now, now) // best-effort LRU bookkeeping
+	return data, true
+}
+
+// Put stores data under key, replacing any existing entry.
+func (s *Store) Put(key string, data []byte) error {
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("create cache shard: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("close cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("commit cache entry: %w", err)
+	}
+	return nil
+}
+
+// PruneStats summarizes a Prune pass.
+type PruneStats struct {
+	Removed int   // entries deleted
+	Kept    int   // entries retained
+	Bytes   int64 // bytes reclaimed
+}
+
+// Prune removes entries not used within maxAge. A maxAge <= 0 removes
+// every entry. Empty shard directories are removed as well.
+func (s *Store) Prune(maxAge time.Duration) (PruneStats, error) {
+	var stats PruneStats
+	cutoff := time.Now().Add(-maxAge)
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isEntry(d.Name()) {
+			return nil
+		}
+		return pruneEntry(path, d, maxAge, cutoff, &stats)
+	})
+	if err != nil {
+		return stats, fmt.Errorf("prune cache: %w", err)
+	}
+	s.removeEmptyShards()
+	return stats, nil
+}
+
+// pruneEntry removes a single entry unless it was used after cutoff.
+func pruneEntry(path string, d fs.DirEntry, maxAge time.Duration, cutoff time.Time, stats *PruneStats) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	if maxAge > 0 && info.ModTime().After(cutoff) {
+		stats.Kept++
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	stats.Removed++
+	stats.Bytes += info.Size()
+	return nil
+}
+
+// isEntry reports whether name is a committed cache entry (temp files
+// from interrupted writes are also eligible, as they are never read).
+func isEntry(name string) bool {
+	return strings.HasSuffix(name, entryExt) || strings.HasPrefix(name, ".tmp-")
+}
+
+// removeEmptyShards deletes shard directories left empty by Prune.
+func (s *Store) removeEmptyShards() {
+	shards, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, sh := range shards {
+		if sh.IsDir() {
+			_ = os.Remove(filepath.Join(s.dir, sh.Name())) // fails harmlessly if non-empty
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pithecene-io/bonsai/internal/cache"
+)
+
+func TestKey_DeterministicAndBoundarySafe(t *testing.T) {
+	if cache.Key("a", "b") != cache.Key("a", "b") {
+		t.Error("Key is not deterministic")
+	}
+	if cache.Key("ab", "c") == cache.Key("a", "bc") {
+		t.Error("Key must distinguish part boundaries")
+	}
+	if len(cache.Key()) != 64 {
+		t.Errorf("Key length = %d, want 64 hex chars", len(cache.Key()))
+	}
+}
+
+func TestStore_PutGet(t *testing.T) {
+	store, err := cache.Open(filepath.Join(t.TempDir(), "results"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	key := cache.Key("prompt")
+	if _, ok := store.Get(key); ok {
+		t.Fatal("expected miss on empty store")
+	}
+	if err := store.Put(key, []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	data, ok := store.Get(key)
+	if !ok || string(data) != `{"ok":true}` {
+		t.Errorf("Get = %q, %v", data, ok)
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	store, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	fresh, stale := cache.Key("fresh"), cache.Key("stale")
+	for _, k := range []string{fresh, stale} {
+		if err := store.Put(k, []byte("x")); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	stalePath := filepath.Join(store.Dir(), stale[:2], stale+".json")
+	if err := os.Chtimes(stalePath, old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	stats, err := store.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if stats.Removed != 1 || stats.Kept != 1 {
+		t.Errorf("stats = %+v, want 1 removed, 1 kept", stats)
+	}
+	if _, ok := store.Get(stale); ok {
+		t.Error("stale entry survived prune")
+	}
+	if _, ok := store.Get(fresh); !ok {
+		t.Error("fresh entry was pruned")
+	}
+
+	stats, err = store.Prune(0)
+	if err != nil {
+		t.Fatalf("Prune(0): %v", err)
+	}
+	if stats.Removed != 1 || stats.Kept != 0 {
+		t.Errorf("Prune(0) stats = %+v, want everything removed", stats)
+	}
+}
+
+func TestOpen_EmptyDir(t *testing.T) {
+	if _, err := cache.Open(""); err == nil {
+		t.Error("expected error for empty dir")
+	}
+}
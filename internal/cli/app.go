@@ -29,6 +29,8 @@ func NewApp() *cli.App {
 			listCommand(),
 			migrateCommand(),
 			hooksCommand(),
+			cacheCommand(),
+			baselineCommand(),
 			completionCommand(),
 		},
 	}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/pithecene-io/bonsai/internal/baseline"
+	"github.com/pithecene-io/bonsai/internal/config"
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+)
+
+func baselineCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "baseline",
+		Usage: "Manage the findings baseline",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "Snapshot the latest check report's findings as accepted",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "from", Usage: "Report to snapshot (default: <output_dir>/ai-check.json)"},
+					&cli.StringFlag{Name: "output", Usage: "Baseline file to write (default: config check.baseline)"},
+				},
+				Action: runBaselineCreate,
+			},
+		},
+	}
+}
+
+func runBaselineCreate(c *cli.Context) error {
+	env, err := bootstrapLight(detectRepoRoot())
+	if err != nil {
+		return err
+	}
+
+	from := c.String("from")
+	if from == "" {
+		from = filepath.Join(env.RepoRoot, env.Config.Output.Dir, "ai-check.json")
+	}
+	rep, err := readReport(from)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("no report at %s — run bonsai check first", from)
+		}
+		return err
+	}
+
+	out := c.String("output")
+	if out == "" {
+		out = baselinePath(env.RepoRoot, env.Config, "")
+	}
+	b := baseline.FromReport(rep)
+	if err := b.Write(out); err != nil {
+		return err
+	}
+
+	fmt.Printf("Baselined %d finding(s) from %s\n", b.Len(), from)
+	fmt.Printf("Output: %s\n", out)
+	return nil
+}
+
+// readReport decodes an ai-check.json report.
+func readReport(path string) (*orchestrator.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rep orchestrator.Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("parse report %s: %w", path, err)
+	}
+	return &rep, nil
+}
+
+// baselinePath resolves the baseline file: flag > config, relative to
+// the repo root unless absolute.
+func baselinePath(repoRoot string, cfg *config.Config, flag string) string {
+	path := cfg.Check.Baseline
+	if flag != "" {
+		path = flag
+	}
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(repoRoot, path)
+}
+
+// loadBaseline loads the findings baseline for a gated run. A missing
+// file means no baseline; a malformed one is an error so that a broken
+// baseline never silently fails open or closed.
+func loadBaseline(repoRoot string, cfg *config.Config, flag string, disabled bool) (orchestrator.Baseline, error) {
+	path := baselinePath(repoRoot, cfg, flag)
+	if disabled || path == "" {
+		return nil, nil //nolint:nilnil // nil baseline disables baselining
+	}
+	b, err := baseline.Load(path)
+	if errors.Is(err, fs.ErrNotExist) && flag == "" {
+		return nil, nil //nolint:nilnil // no baseline committed yet
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load baseline: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/pithecene-io/bonsai/internal/cache"
+)
+
+// defaultPruneAge is the idle age after which prune evicts entries.
+const defaultPruneAge = 7 * 24 * time.Hour
+
+func cacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the skill result cache",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "prune",
+				Usage: "Remove cached skill results not used recently",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{Name: "older-than", Value: defaultPruneAge, Usage: "Evict entries idle longer than this"},
+					&cli.BoolFlag{Name: "all", Usage: "Remove every cached result"},
+				},
+				Action: runCachePrune,
+			},
+		},
+	}
+}
+
+func runCachePrune(c *cli.Context) error {
+	env, err := bootstrapLight(detectRepoRoot())
+	if err != nil {
+		return err
+	}
+	dir, err := resolveCacheDir(env.Config)
+	if err != nil {
+		return fmt.Errorf("resolve cache dir: %w", err)
+	}
+	store, err := cache.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	maxAge := c.Duration("older-than")
+	if c.Bool("all") {
+		maxAge = 0
+	}
+	stats, err := store.Prune(maxAge)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pruned %d cached result(s) (%d bytes), kept %d: %s\n",
+		stats.Removed, stats.Bytes, stats.Kept, store.Dir())
+	return nil
+}
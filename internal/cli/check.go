@@ -5,32 +5,50 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v2"
-	"golang.org/x/term"
 
+	"github.com/pithecene-io/bonsai/internal/assets"
 	"github.com/pithecene-io/bonsai/internal/config"
 	"github.com/pithecene-io/bonsai/internal/orchestrator"
 	"github.com/pithecene-io/bonsai/internal/registry"
+	"github.com/pithecene-io/bonsai/internal/report"
+	"github.com/pithecene-io/bonsai/internal/skill"
 	"github.com/pithecene-io/bonsai/internal/tui"
 )
 
 func checkCommand() *cli.Command {
 	return &cli.Command{
-		Name:  "check",
-		Usage: "Run governance skills (bundle or mode-based)",
+		Name:      "check",
+		Usage:     "Run governance skills (bundle or mode-based)",
+		ArgsUsage: "[path...]",
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "bundle", Value: "default", Usage: "Bundle name"},
 			&cli.StringFlag{Name: "mode", Usage: "Governance mode (PATCH, NORMAL, STRUCTURAL, API, HEAVY, AUDIT)"},
-			&cli.StringFlag{Name: "scope", Usage: "Comma-separated path prefixes"},
+			&cli.StringFlag{Name: "scope", Usage: "Comma-separated path prefixes; limits the tree, diff, and diff profile (positional paths are added)"},
 			&cli.StringFlag{Name: "base", Usage: "Git ref for diff context"},
 			&cli.BoolFlag{Name: "fail-fast", Usage: "Stop on first mandatory failure"},
-			&cli.StringFlag{Name: "diff-profile", Usage: "JSON diff profile (reserved)"},
+			&cli.StringFlag{Name: "diff-profile", Usage: "Diff profile for skill applicability: inline JSON or a file path (default: computed from --base)"},
 			&cli.IntFlag{Name: "jobs", Aliases: []string{"j"}, Usage: "Max parallel skill invocations"},
 			&cli.BoolFlag{Name: "no-progress", Usage: "Disable TUI progress display"},
 			&cli.StringFlag{Name: "model", Usage: "Override model for all skills (e.g. haiku, sonnet, opus)"},
+			&cli.StringFlag{Name: "format", Value: "json", Usage: "Additional report format (json, sarif, junit)"},
+			&cli.StringFlag{Name: "output", Usage: "Path for the --format report (default: <output_dir>/ai-check.<ext>)"},
+			&cli.BoolFlag{Name: "no-cache", Usage: "Re-evaluate every skill, ignoring cached results"},
+			&cli.StringFlag{Name: "baseline", Usage: "Findings baseline file (default: config check.baseline)"},
+			&cli.BoolFlag{Name: "no-baseline", Usage: "Ignore the findings baseline; every finding counts"},
+			&cli.StringFlag{Name: "fail-on", Usage: "Lowest severity that fails a skill (blocking, major, warning)"},
+			&cli.DurationFlag{Name: "timeout", Usage: "Deadline for the whole run, e.g. 10m (default: config check.timeout)"},
+			&cli.Float64Flag{Name: "budget", Usage: "Stop dispatching skills once this many USD are spent (default: config check.max_cost)"},
+			&cli.StringFlag{Name: "strategy", Usage: "Dispatch strategy: parallel, or staged by cost tier (default: config check.strategy)"},
+			eventsFlag(),
 		},
 		Action: runCheck,
 	}
@@ -45,17 +63,47 @@ type checkArgs struct {
 	failFast      bool
 	noProgress    bool
 	modelOverride string
+	format        report.Format
+	output        string
+	noCache       bool
+	baseline      string
+	noBaseline    bool
+	failOn        string
+	timeout       time.Duration
+	budget        float64
+	events        string
+	diffProfile   string
+	strategy      string
 }
 
 func parseCheckArgs(c *cli.Context) (checkArgs, error) {
 	a := checkArgs{
 		mode:          c.String("mode"),
 		bundle:        c.String("bundle"),
-		scope:         c.String("scope"),
 		baseRef:       c.String("base"),
 		failFast:      c.Bool("fail-fast"),
 		noProgress:    c.Bool("no-progress"),
 		modelOverride: c.String("model"),
+		output:        c.String("output"),
+		noCache:       c.Bool("no-cache"),
+		baseline:      c.String("baseline"),
+		noBaseline:    c.Bool("no-baseline"),
+		failOn:        c.String("fail-on"),
+		timeout:       c.Duration("timeout"),
+		budget:        c.Float64("budget"),
+		events:        c.String("events"),
+		diffProfile:   c.String("diff-profile"),
+		strategy:      c.String("strategy"),
+	}
+
+	format, err := report.ParseFormat(c.String("format"))
+	if err != nil {
+		return a, err
+	}
+	a.format = format
+
+	if a.scope, err = resolveScope(c.String("scope"), c.Args().Slice()); err != nil {
+		return a, err
 	}
 
 	if a.mode != "" && c.IsSet("bundle") {
@@ -75,6 +123,14 @@ func runCheck(c *cli.Context) error {
 		return err
 	}
 
+	return withEventStream(args.events, func(stream *eventStream) error {
+		return executeCheck(c, args, stream)
+	})
+}
+
+// executeCheck runs the check with parsed arguments, streaming events
+// to stream when non-nil.
+func executeCheck(c *cli.Context, args checkArgs, stream *eventStream) error {
 	env, err := bootstrap()
 	if err != nil {
 		return err
@@ -85,67 +141,119 @@ func runCheck(c *cli.Context) error {
 		return err
 	}
 
-	concurrency := resolveConcurrency(env.Config, c)
-
-	orch := orchestrator.New(newAgentRouter(env.Config), env.Resolver)
-	opts := orchestrator.RunOpts{
-		Skills:              ss.Skills,
-		Source:              ss.Source,
-		BaseRef:             args.baseRef,
-		Scope:               args.scope,
-		FailFast:            args.failFast,
-		RepoRoot:            env.RepoRoot,
-		Config:              env.Config,
-		DefaultRequiresDiff: env.Registry.Defaults.EffectiveRequiresDiff(),
-		Concurrency:         concurrency,
-		ModelOverride:       args.modelOverride,
+	opts, err := buildCheckOpts(env, args, ss, resolveConcurrency(env.Config, c))
+	if err != nil {
+		return err
 	}
+	orch := orchestrator.New(newAgentRouter(env.Config), env.Resolver)
 
-	useTUI := term.IsTerminal(int(os.Stdout.Fd())) && !args.noProgress
-
-	var report *orchestrator.Report
-	if useTUI {
-		report, err = runCheckTUI(c.Context, orch, opts, ss.Source, ss.Skills)
+	var rep *orchestrator.Report
+	if stream.useTUI(args.noProgress) {
+		rep, err = runCheckTUI(c.Context, orch, opts, ss.Source, stream)
 	} else {
-		report, err = runCheckPlain(c.Context, orch, opts)
+		rep, err = runWithEvents(c.Context, orch, opts, stream)
 	}
 	if err != nil {
 		return err
 	}
-	if report == nil {
+	if rep == nil {
 		return nil // TUI interrupted
 	}
 
-	reportPath, err := writeCheckReport(env.RepoRoot, env.Config, report)
+	outputs, err := writeCheckOutputs(env, args, ss.Skills, rep)
 	if err != nil {
 		return err
 	}
 
-	printCheckSummary(ss.Source, reportPath, report, args.baseRef)
+	printCheckSummary(stream.out(), ss.Source, outputs, rep, args.baseRef)
 
-	if report.ShouldFail() {
+	if rep.ShouldFail() {
 		return cli.Exit("", 1)
 	}
 	return nil
 }
 
+// buildCheckOpts resolves the baseline and failure threshold and
+// assembles the orchestrator options for a check run.
+func buildCheckOpts(env cmdEnv, args checkArgs, ss skillSet, concurrency int) (orchestrator.RunOpts, error) {
+	bl, err := loadBaseline(env.RepoRoot, env.Config, args.baseline, args.noBaseline)
+	if err != nil {
+		return orchestrator.RunOpts{}, err
+	}
+	failOn, err := resolveFailOn(env.Registry, env.Config, args.failOn, args.mode, args.bundle)
+	if err != nil {
+		return orchestrator.RunOpts{}, err
+	}
+	profile, err := loadDiffProfile(args.diffProfile)
+	if err != nil {
+		return orchestrator.RunOpts{}, err
+	}
+	strategy, err := resolveStrategy(env.Config, args.strategy)
+	if err != nil {
+		return orchestrator.RunOpts{}, err
+	}
+	return orchestrator.RunOpts{
+		Skills:              ss.Skills,
+		Source:              ss.Source,
+		BaseRef:             args.baseRef,
+		Scope:               args.scope,
+		FailFast:            args.failFast,
+		RepoRoot:            env.RepoRoot,
+		Config:              env.Config,
+		DefaultRequiresDiff: env.Registry.Defaults.EffectiveRequiresDiff(),
+		DefaultTimeout:      env.Registry.Defaults.Timeout,
+		Concurrency:         concurrency,
+		ModelOverride:       args.modelOverride,
+		Cache:               openResultCache(env.Config, args.noCache),
+		Baseline:            bl,
+		FailOn:              failOn,
+		Timeout:             resolveTimeout(env.Config, args.timeout),
+		MaxCost:             resolveMaxCost(env.Config, args.budget),
+		Profile:             profile,
+		IgnoreTriggers:      registry.GovMode(args.mode) == registry.GovModeAudit,
+		Strategy:            strategy,
+	}, nil
+}
+
+// writeCheckOutputs writes ai-check.json and, when a non-JSON format or
+// explicit output path is requested, the formatted report. It returns the
+// paths written.
+func writeCheckOutputs(env cmdEnv, args checkArgs, skills []registry.Skill, rep *orchestrator.Report) ([]string, error) {
+	reportPath, err := writeCheckReport(env.RepoRoot, env.Config, rep)
+	if err != nil {
+		return nil, err
+	}
+	outputs := []string{reportPath}
+
+	if args.format != report.FormatJSON || args.output != "" {
+		meta := report.Meta{ToolVersion: Version, Skills: collectSkillInfo(env.Resolver, skills)}
+		formattedPath, err := writeFormattedReport(env.RepoRoot, env.Config, args, rep, meta)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, formattedPath)
+	}
+	return outputs, nil
+}
+
 func runCheckTUI(
 	ctx context.Context,
 	orch *orchestrator.Orchestrator,
 	opts orchestrator.RunOpts,
 	source string,
-	skills []registry.Skill,
+	stream *eventStream,
 ) (*orchestrator.Report, error) {
 	orchCtx, orchCancel := context.WithCancel(ctx)
 	defer orchCancel()
 
-	events := make(chan orchestrator.Event, len(skills)*4)
-	var report *orchestrator.Report
+	bus := orchestrator.NewBus(orchestrator.EventBuffer(len(opts.Skills)))
+	events := bus.Subscribe()
+	stream.subscribe(bus)
+	var rep *orchestrator.Report
 	var runErr error
 	orchDone := make(chan struct{})
 	go func() {
-		report, runErr = orch.Run(orchCtx, opts, events)
-		close(events)
+		rep, runErr = orch.RunWithBus(orchCtx, opts, bus)
 		close(orchDone)
 	}()
 
@@ -164,26 +272,18 @@ func runCheckTUI(
 		return nil, runErr
 	}
 	if tuiReport != nil {
-		report = tuiReport
+		rep = tuiReport
 	}
-	return report, nil
-}
-
-func runCheckPlain(
-	ctx context.Context,
-	orch *orchestrator.Orchestrator,
-	opts orchestrator.RunOpts,
-) (*orchestrator.Report, error) {
-	return orch.RunWithLogger(ctx, opts, nil)
+	return rep, nil
 }
 
-func writeCheckReport(repoRoot string, cfg *config.Config, report *orchestrator.Report) (string, error) {
+func writeCheckReport(repoRoot string, cfg *config.Config, rep *orchestrator.Report) (string, error) {
 	outDir := filepath.Join(repoRoot, cfg.Output.Dir)
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return "", fmt.Errorf("create output dir: %w", err)
 	}
 
-	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	reportJSON, err := json.MarshalIndent(rep, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("marshal report: %w", err)
 	}
@@ -195,31 +295,128 @@ func writeCheckReport(repoRoot string, cfg *config.Config, report *orchestrator.
 	return reportPath, nil
 }
 
-func printCheckSummary(source, reportPath string, report *orchestrator.Report, baseRef string) {
-	fmt.Println()
-	fmt.Println("═══ bonsai check summary ═══")
-	fmt.Printf("Source: %s\n", source)
-	fmt.Printf("Results: %d/%d passed (%d failed, %d skipped, %d blocking)\n",
-		report.Passed, report.Total, report.Failed, report.Skipped, report.BlockingFailed)
-	fmt.Printf("Output: %s\n", reportPath)
+// writeFormattedReport writes the report in the --format requested,
+// to --output or the format's default filename in the output directory.
+func writeFormattedReport(
+	repoRoot string,
+	cfg *config.Config,
+	args checkArgs,
+	rep *orchestrator.Report,
+	meta report.Meta,
+) (string, error) {
+	path := args.output
+	if path == "" {
+		path = filepath.Join(repoRoot, cfg.Output.Dir, args.format.DefaultFilename())
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create output dir: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create %s report: %w", args.format, err)
+	}
+	writeErr := report.Write(f, args.format, rep, meta)
+	closeErr := f.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		return "", fmt.Errorf("write %s report: %w", args.format, err)
+	}
+	return path, nil
+}
+
+// collectSkillInfo gathers registry and SKILL.md metadata for each skill.
+// Skills that fail to load are recorded without a description.
+func collectSkillInfo(resolver *assets.Resolver, skills []registry.Skill) map[string]report.SkillInfo {
+	infos := make(map[string]report.SkillInfo, len(skills))
+	for i := range skills {
+		s := skills[i]
+		info := report.SkillInfo{Skill: s}
+		version := s.Version
+		if version == "" {
+			version = "v1"
+		}
+		if def, err := skill.Load(resolver, s.Name, version); err == nil {
+			info.Description = def.Description
+		}
+		infos[s.Name] = info
+	}
+	return infos
+}
+
+// printCheckPolicy prints the non-default threshold and the findings
+// excluded by the baseline or inline suppressions.
+func printCheckPolicy(w io.Writer, rep *orchestrator.Report) {
+	if rep.FailOn != "" && rep.FailOn != string(registry.FailOnBlocking) {
+		fmt.Fprintf(w, "Fail on: %s and above\n", rep.FailOn)
+	}
+	if rep.Baselined > 0 {
+		fmt.Fprintf(w, "Baselined: %d pre-existing finding(s) ignored\n", rep.Baselined)
+	}
+	if rep.Suppressed > 0 {
+		fmt.Fprintf(w, "Suppressed: %d finding(s) via bonsai:ignore (see suppressed_findings)\n", rep.Suppressed)
+	}
+	if rep.NotApplicable > 0 {
+		fmt.Fprintf(w, "Not applicable: %d skill(s) skipped for this diff (trigger or run_when)\n", rep.NotApplicable)
+	}
+}
+
+// printCheckSpend prints token usage and its priced cost per model,
+// and warns when the budget or a failed cheaper tier stopped skills
+// from being dispatched.
+func printCheckSpend(w io.Writer, rep *orchestrator.Report) {
+	if rep.InputTokens > 0 || rep.OutputTokens > 0 {
+		line := fmt.Sprintf("Tokens: %d in / %d out", rep.InputTokens, rep.OutputTokens)
+		if rep.CostUSD > 0 {
+			line += fmt.Sprintf(" — $%.4f (%s)", rep.CostUSD, formatCostByModel(rep.CostByModel))
+		}
+		fmt.Fprintln(w, line)
+	}
+	if rep.BudgetSkipped > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ Budget of $%.2f reached — %d skill(s) not run\n", rep.MaxCost, rep.BudgetSkipped)
+	}
+	if rep.TierSkipped > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ Mandatory failure in a cheaper tier — %d skill(s) not run\n", rep.TierSkipped)
+	}
+}
+
+// formatCostByModel renders per-model spend as "haiku $0.0012, sonnet $0.0340".
+func formatCostByModel(costs map[string]float64) string {
+	parts := make([]string, 0, len(costs))
+	for _, model := range slices.Sorted(maps.Keys(costs)) {
+		parts = append(parts, fmt.Sprintf("%s $%.4f", model, costs[model]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printCheckSummary(w io.Writer, source string, outputs []string, rep *orchestrator.Report, baseRef string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "═══ bonsai check summary ═══")
+	fmt.Fprintf(w, "Source: %s\n", source)
+	fmt.Fprintf(w, "Results: %d/%d passed (%d failed, %d skipped, %d blocking)\n",
+		rep.Passed, rep.Total, rep.Failed, rep.Skipped, rep.BlockingFailed)
+	printCheckPolicy(w, rep)
+	printCheckSpend(w, rep)
+	for _, path := range outputs {
+		fmt.Fprintf(w, "Output: %s\n", path)
+	}
 
-	if report.SkipWarning != "" {
-		fmt.Fprintf(os.Stderr, "\n⚠ %s\n", report.SkipWarning)
+	if rep.SkipWarning != "" {
+		fmt.Fprintf(os.Stderr, "\n⚠ %s\n", rep.SkipWarning)
 		if baseRef == "" {
 			fmt.Fprintln(os.Stderr, "  hint: pass --base <ref> to provide diff context")
 		}
 	}
 
-	if !report.ShouldFail() {
+	if !rep.ShouldFail() {
 		return
 	}
 
-	if report.Total > 0 && report.Skipped == report.Total {
-		fmt.Fprintf(os.Stderr, "\n✖ All %d skill(s) were skipped — no validation occurred\n", report.Total)
+	if rep.Total > 0 && rep.Skipped == rep.Total {
+		fmt.Fprintf(os.Stderr, "\n✖ All %d skill(s) were skipped — no validation occurred\n", rep.Total)
 		if baseRef == "" {
 			fmt.Fprintln(os.Stderr, "  hint: pass --base <ref> to provide diff context for requires_diff skills")
 		}
 	} else {
-		fmt.Fprintf(os.Stderr, "\n✖ %d skill(s) had blocking findings\n", report.BlockingFailed)
+		fmt.Fprintf(os.Stderr, "\n✖ %d skill(s) had blocking findings\n", rep.BlockingFailed)
 	}
 }
@@ -321,8 +321,83 @@ func TestCheck_InvalidMode(t *testing.T) {
 	}
 }
 
+func TestCheck_InvalidFormat(t *testing.T) {
+	_, err := runApp(t, "check", "--format", "xml")
+	if err == nil {
+		t.Fatal("expected error for invalid format")
+	}
+	if !strings.Contains(err.Error(), "invalid format") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 // --- migrate error paths ---
 
+// --- cache ---
+
+func TestCachePrune_All(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	cacheDir := filepath.Join(dir, "cache")
+	t.Setenv("BONSAI_CACHE_DIR", cacheDir)
+
+	entry := filepath.Join(cacheDir, "ab", "ab01.json")
+	if err := os.MkdirAll(filepath.Dir(entry), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(entry, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out, err := runApp(t, "cache", "prune", "--all")
+	if err != nil {
+		t.Fatalf("cache prune: %v", err)
+	}
+	if !strings.Contains(out, "Pruned 1 cached result(s)") {
+		t.Errorf("output = %q", out)
+	}
+	if _, err := os.Stat(entry); !os.IsNotExist(err) {
+		t.Error("cache entry survived prune --all")
+	}
+}
+
+// --- baseline ---
+
+func TestBaselineCreate_FromReport(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	reportPath := filepath.Join(dir, "ai", "out", "ai-check.json")
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	rep := `{"source": "mode:AUDIT", "results": [{"name": "s1", "status": "fail",
+		"blocking_details": ["bad"], "major_details": ["meh"]}]}`
+	if err := os.WriteFile(reportPath, []byte(rep), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+
+	out, err := runApp(t, "baseline", "create")
+	if err != nil {
+		t.Fatalf("baseline create: %v", err)
+	}
+	if !strings.Contains(out, "Baselined 2 finding(s)") {
+		t.Errorf("output = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ai", "baseline.json")); err != nil {
+		t.Errorf("baseline file not written: %v", err)
+	}
+}
+
+func TestBaselineCreate_NoReport(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := runApp(t, "baseline", "create")
+	if err == nil || !strings.Contains(err.Error(), "run bonsai check first") {
+		t.Errorf("err = %v, want missing-report hint", err)
+	}
+}
+
 func TestMigrate_NonexistentPath(t *testing.T) {
 	_, err := runApp(t, "migrate", "/nonexistent/path/does/not/exist")
 	if err == nil {
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+)
+
+// eventsFlag is the --events flag shared by check, fix, and implement.
+func eventsFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "events",
+		Usage: "Stream run events as NDJSON: \"ndjson\" for stdout, \"ndjson=<path>\" for a file",
+	}
+}
+
+// eventStream is the --events destination for machine-readable run
+// events. A nil stream means events are not streamed.
+type eventStream struct {
+	writer *orchestrator.NDJSONWriter
+	file   *os.File // non-nil when streaming to a file
+	stdout bool     // events own stdout; human output goes to stderr
+}
+
+// openEventStream parses an --events value: "ndjson" streams to stdout,
+// "ndjson=<path>" to a file (truncated). When streaming to stdout,
+// human-readable output belongs on the stream's out writer so the
+// stream stays parseable.
+func openEventStream(spec string) (*eventStream, error) {
+	if spec == "" {
+		return nil, nil //nolint:nilnil // no --events flag means no stream
+	}
+	format, path, _ := strings.Cut(spec, "=")
+	if format != "ndjson" {
+		return nil, fmt.Errorf("invalid --events value %q (valid: ndjson, ndjson=<path>)", spec)
+	}
+	if path == "" || path == "-" {
+		return &eventStream{writer: orchestrator.NewNDJSONWriter(os.Stdout), stdout: true}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("open events stream: %w", err)
+	}
+	return &eventStream{writer: orchestrator.NewNDJSONWriter(f), file: f}, nil
+}
+
+// subscribe registers the stream on bus; a nil stream is a no-op.
+func (s *eventStream) subscribe(bus *orchestrator.Bus) {
+	if h := s.handler(); h != nil {
+		bus.Handle(h)
+	}
+}
+
+// out returns where human-readable output goes: stderr when events
+// stream to stdout, stdout otherwise. Safe to call on a nil stream.
+func (s *eventStream) out() *os.File {
+	if s != nil && s.stdout {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// useTUI reports whether the progress TUI may draw: stdout is a
+// terminal and does not carry the event stream.
+func (s *eventStream) useTUI(noProgress bool) bool {
+	return !noProgress && s.out() == os.Stdout && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// handler returns the stream's event handler, or nil for a nil stream.
+func (s *eventStream) handler() func(orchestrator.Event) {
+	if s == nil {
+		return nil
+	}
+	return s.writer.Handle
+}
+
+// Close closes the file and reports the first write error. Safe to
+// call on a nil stream.
+func (s *eventStream) Close() error {
+	if s == nil {
+		return nil
+	}
+	var closeErr error
+	if s.file != nil {
+		closeErr = s.file.Close()
+	}
+	if err := errors.Join(s.writer.Err(), closeErr); err != nil {
+		return fmt.Errorf("events stream: %w", err)
+	}
+	return nil
+}
+
+// withEventStream opens the stream for an --events value, runs fn with
+// it, and closes it. A close error is reported only when fn succeeded,
+// so fn's error (e.g. a cli.Exit code) is never masked.
+func withEventStream(spec string, fn func(*eventStream) error) error {
+	stream, err := openEventStream(spec)
+	if err != nil {
+		return err
+	}
+	err = fn(stream)
+	if closeErr := stream.Close(); closeErr != nil && err == nil {
+		return closeErr
+	}
+	return err
+}
+
+// runWithEvents runs orch with a log subscriber, writing to the
+// stream's out, plus the event stream.
+func runWithEvents(
+	ctx context.Context,
+	orch *orchestrator.Orchestrator,
+	opts orchestrator.RunOpts,
+	stream *eventStream,
+) (*orchestrator.Report, error) {
+	bus := orchestrator.NewBus(orchestrator.EventBuffer(len(opts.Skills)))
+	bus.Handle(orchestrator.LogHandler(lineLogger(stream.out())))
+	stream.subscribe(bus)
+	return orch.RunWithBus(ctx, opts, bus)
+}
+
+// lineLogger returns a LogHandler logger printing each line to w.
+func lineLogger(w io.Writer) func(string) {
+	return func(msg string) { fmt.Fprintln(w, msg) }
+}
@@ -5,15 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	urfave "github.com/urfave/cli/v2"
-	"golang.org/x/term"
 
 	"github.com/pithecene-io/bonsai/internal/agent"
 	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/cache"
 	"github.com/pithecene-io/bonsai/internal/config"
 	"github.com/pithecene-io/bonsai/internal/orchestrator"
 	"github.com/pithecene-io/bonsai/internal/prompt"
@@ -31,6 +32,9 @@ func fixCommand() *urfave.Command {
 			&urfave.StringFlag{Name: "base", Usage: "Git ref for diff context"},
 			&urfave.IntFlag{Name: "max-iterations", Usage: "Max fix iterations (default: config or 3)"},
 			&urfave.BoolFlag{Name: "no-progress", Usage: "Disable TUI progress display"},
+			&urfave.BoolFlag{Name: "no-cache", Usage: "Re-evaluate every skill, ignoring cached results"},
+			&urfave.StringFlag{Name: "fail-on", Usage: "Lowest severity that fails a skill (blocking, major, warning)"},
+			eventsFlag(),
 		},
 		Action: runFix,
 	}
@@ -79,10 +83,17 @@ func runFix(c *urfave.Context) error {
 		baseRef = repo.DetectMergeBase(env.RepoRoot, env.Config.Routing.MergeBaseCandidates)
 	}
 
-	agentRouter := newAgentRouter(env.Config)
+	bl, err := loadBaseline(env.RepoRoot, env.Config, "", false)
+	if err != nil {
+		return err
+	}
+
+	failOn, err := resolveFailOn(env.Registry, env.Config, c.String("fail-on"), "", bundle)
+	if err != nil {
+		return err
+	}
 
-	// TTY detection: use TUI if stdout is a terminal and --no-progress is not set
-	useTUI := term.IsTerminal(int(os.Stdout.Fd())) && !noProgress
+	agentRouter := newAgentRouter(env.Config)
 
 	fl := &fixLoop{
 		checkAgent:    agentRouter,
@@ -95,9 +106,18 @@ func runFix(c *urfave.Context) error {
 		baseRef:       baseRef,
 		repoRoot:      env.RepoRoot,
 		maxIterations: maxIter,
-		useTUI:        useTUI,
-	}
-	return fl.run(c.Context)
+		cache:         openResultCache(env.Config, c.Bool("no-cache")),
+		baseline:      bl,
+		failOn:        failOn,
+	}
+	return withEventStream(c.String("events"), func(stream *eventStream) error {
+		fl.events = stream
+		fl.out = stream.out()
+		// TUI only when stdout is a terminal and --no-progress is not set
+		fl.useTUI = stream.useTUI(noProgress)
+		agentRouter.SetStdout(fl.out)
+		return fl.run(c.Context)
+	})
 }
 
 // fixLoop encapsulates the check-fix-recheck loop and its dependencies.
@@ -113,6 +133,11 @@ type fixLoop struct {
 	repoRoot      string
 	maxIterations int
 	useTUI        bool
+	cache         *cache.Store // skips re-evaluating skills whose inputs are unchanged between iterations
+	baseline      orchestrator.Baseline
+	failOn        registry.FailOn
+	events        *eventStream // --events destination; nil when not streaming
+	out           io.Writer    // progress and status output; nil = os.Stdout
 
 	// checker overrides the default check implementation.
 	// Used by tests to inject mock check results.
@@ -138,9 +163,17 @@ func (sf skillFindings) UserPrompt() string {
 	return b.String()
 }
 
+// output returns where the loop's progress and status lines go.
+func (fl *fixLoop) output() io.Writer {
+	if fl.out != nil {
+		return fl.out
+	}
+	return os.Stdout
+}
+
 // run implements the check-fix-recheck loop.
 func (fl *fixLoop) run(ctx context.Context) error {
-	fmt.Println("═══ bonsai fix: initial check ═══")
+	fmt.Fprintln(fl.output(), "═══ bonsai fix: initial check ═══")
 	report, err := fl.check(ctx)
 	if err != nil {
 		return fmt.Errorf("initial check: %w", err)
@@ -149,7 +182,7 @@ func (fl *fixLoop) run(ctx context.Context) error {
 		return nil // TUI interrupted
 	}
 	if !report.ShouldFail() {
-		fmt.Println("\n✔ No findings — nothing to fix")
+		fmt.Fprintln(fl.output(), "\n✔ No findings — nothing to fix")
 		return nil
 	}
 
@@ -171,17 +204,17 @@ func (fl *fixLoop) run(ctx context.Context) error {
 func (fl *fixLoop) iterate(ctx context.Context, report *orchestrator.Report, iteration int) (*orchestrator.Report, error) {
 	failedSkills := fl.perSkillFindings(report)
 	if len(failedSkills) == 0 {
-		fmt.Println("\n✔ No findings — nothing to fix")
+		fmt.Fprintln(fl.output(), "\n✔ No findings — nothing to fix")
 		return nil, nil //nolint:nilnil // nil report signals "resolved" to caller
 	}
 
-	fmt.Printf("\n═══ Fix iteration %d/%d — %d skill(s) to fix ═══\n", iteration, fl.maxIterations, len(failedSkills))
+	fmt.Fprintf(fl.output(), "\n═══ Fix iteration %d/%d — %d skill(s) to fix ═══\n", iteration, fl.maxIterations, len(failedSkills))
 
 	if err := fl.fixSessions(ctx, failedSkills); err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("\n═══ Re-check after fix iteration %d/%d ═══\n", iteration, fl.maxIterations)
+	fmt.Fprintf(fl.output(), "\n═══ Re-check after fix iteration %d/%d ═══\n", iteration, fl.maxIterations)
 	report, err := fl.check(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("re-check: %w", err)
@@ -191,7 +224,7 @@ func (fl *fixLoop) iterate(ctx context.Context, report *orchestrator.Report, ite
 	}
 
 	if !report.ShouldFail() {
-		fmt.Printf("\n✔ All findings resolved (%d/%d skills passed)\n", report.Passed, report.Total)
+		fmt.Fprintf(fl.output(), "\n✔ All findings resolved (%d/%d skills passed)\n", report.Passed, report.Total)
 		fl.saveArtifacts(report)
 		return nil, nil //nolint:nilnil // nil report signals "resolved" to caller
 	}
@@ -202,7 +235,7 @@ func (fl *fixLoop) iterate(ctx context.Context, report *orchestrator.Report, ite
 		return nil, fmt.Errorf("findings remain after %d fix iterations", fl.maxIterations)
 	}
 
-	fmt.Printf("\n%d finding(s) remain — continuing to next iteration\n", report.BlockingFailed)
+	fmt.Fprintf(fl.output(), "\n%d finding(s) remain — continuing to next iteration\n", report.BlockingFailed)
 	return report, nil
 }
 
@@ -219,7 +252,7 @@ func (fl *fixLoop) fixSessions(ctx context.Context, failedSkills []skillFindings
 	}
 
 	for i, sf := range failedSkills {
-		fmt.Printf("\n═══ Fixing: %s (%d/%d) ═══\n\n", sf.Name, i+1, len(failedSkills))
+		fmt.Fprintf(fl.output(), "\n═══ Fixing: %s (%d/%d) ═══\n\n", sf.Name, i+1, len(failedSkills))
 
 		model := ""
 		if fl.config != nil {
@@ -251,27 +284,32 @@ func (fl *fixLoop) check(ctx context.Context) (*orchestrator.Report, error) {
 		RepoRoot:            fl.repoRoot,
 		Config:              fl.config,
 		DefaultRequiresDiff: fl.registry.Defaults.EffectiveRequiresDiff(),
+		DefaultTimeout:      fl.registry.Defaults.Timeout,
 		Concurrency:         0, // unlimited
+		Cache:               fl.cache,
+		Baseline:            fl.baseline,
+		FailOn:              fl.failOn,
 	}
 
 	if fl.useTUI {
 		return fl.checkTUI(ctx, orch, runOpts)
 	}
 
-	return orch.RunWithLogger(ctx, runOpts, nil)
+	return runWithEvents(ctx, orch, runOpts, fl.events)
 }
 
 func (fl *fixLoop) checkTUI(ctx context.Context, orch *orchestrator.Orchestrator, runOpts orchestrator.RunOpts) (*orchestrator.Report, error) {
 	orchCtx, orchCancel := context.WithCancel(ctx)
 	defer orchCancel()
 
-	events := make(chan orchestrator.Event, len(fl.skills)*4)
+	bus := orchestrator.NewBus(orchestrator.EventBuffer(len(fl.skills)))
+	events := bus.Subscribe()
+	fl.events.subscribe(bus)
 	var report *orchestrator.Report
 	var runErr error
 	orchDone := make(chan struct{})
 	go func() {
-		report, runErr = orch.Run(orchCtx, runOpts, events)
-		close(events)
+		report, runErr = orch.RunWithBus(orchCtx, runOpts, bus)
 		close(orchDone)
 	}()
 
@@ -333,7 +371,7 @@ func (fl *fixLoop) saveArtifacts(report *orchestrator.Report) {
 	if err := os.WriteFile(reportPath, reportJSON, 0o644); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to save report: %v\n", err)
 	} else {
-		fmt.Printf("Saved: %s\n", reportPath)
+		fmt.Fprintf(fl.output(), "Saved: %s\n", reportPath)
 	}
 }
 
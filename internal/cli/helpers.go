@@ -1,17 +1,24 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/pithecene-io/bonsai/internal/agent"
 	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/cache"
 	"github.com/pithecene-io/bonsai/internal/config"
+	"github.com/pithecene-io/bonsai/internal/diff"
 	"github.com/pithecene-io/bonsai/internal/gitutil"
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
 	"github.com/pithecene-io/bonsai/internal/registry"
 )
 
@@ -79,7 +86,86 @@ func newAgentRouter(cfg *config.Config) *agent.Router {
 	if cfg.Providers.Anthropic.APIKey != "" {
 		apiOpts = append(apiOpts, agent.WithAPIKey(cfg.Providers.Anthropic.APIKey))
 	}
-	return agent.NewRouter(cfg.Agents.Claude.Bin, cfg.Agents.Codex.Bin, apiOpts...)
+	apiOpts = append(apiOpts, agent.WithTools(agent.ToolConfig{
+		AllowedCommands: cfg.Providers.Anthropic.Tools.AllowedCommands,
+		MaxTurns:        cfg.Providers.Anthropic.Tools.MaxTurns,
+		CommandTimeout:  cfg.Providers.Anthropic.Tools.CommandTimeout,
+	}))
+	apiOpts = append(apiOpts,
+		agent.WithModelAliases(cfg.Models.Aliases),
+		agent.WithModelProfiles(modelProfiles(cfg.Models.Profiles)),
+	)
+	r := agent.NewRouter(cfg.Agents.Claude.Bin, cfg.Agents.Codex.Bin, apiOpts...)
+	r.Aliases = cfg.Models.Aliases
+	compat := cfg.Providers.OpenAICompat
+	var apiKey string
+	if compat.APIKeyEnv != "" {
+		apiKey = os.Getenv(compat.APIKeyEnv)
+	}
+	r.UseOpenAICompat(compat.Prefix, agent.NewOpenAICompat(compat.BaseURL, apiKey, compat.Models,
+		agent.WithCompatProfiles(compatProfiles(compat.Prefix, cfg.Models.Profiles))))
+	useExecTemplates(r, cfg.Agents.Templates)
+	// Chains may name templates, so they are set after registration.
+	if err := r.SetChains(cfg.Routing.Backends); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	r.Retries = cfg.Routing.Retries
+	r.BreakerThreshold = max(cfg.Routing.CircuitBreaker, 0)
+	return r
+}
+
+// modelProfiles converts the configured generation profiles to the
+// Anthropic backend's form. The context window is applied by the skill
+// runner, not the backend.
+func modelProfiles(profiles map[string]config.ModelProfile) map[string]agent.ModelProfile {
+	out := make(map[string]agent.ModelProfile, len(profiles))
+	for name, p := range profiles {
+		out[name] = agent.ModelProfile{
+			MaxTokens:      int64(p.MaxTokens),
+			Temperature:    p.Temperature,
+			ThinkingBudget: int64(p.ThinkingBudget),
+		}
+	}
+	return out
+}
+
+// compatProfiles returns the generation profiles of models routed to
+// the OpenAI-compatible backend, keyed by the name after prefix as the
+// backend receives it. Config keys them like any other model, by the
+// prefixed name (local:coder).
+func compatProfiles(prefix string, profiles map[string]config.ModelProfile) map[string]agent.ModelProfile {
+	if prefix == "" {
+		prefix = agent.DefaultCompatPrefix
+	}
+	out := map[string]agent.ModelProfile{}
+	for name, p := range modelProfiles(profiles) {
+		if short, ok := strings.CutPrefix(name, prefix+":"); ok {
+			out[short] = p
+		}
+	}
+	return out
+}
+
+// useExecTemplates registers the configured command templates on r. An
+// invalid template is skipped with a warning rather than failing every
+// command, including those that never route to it.
+func useExecTemplates(r *agent.Router, templates map[string]config.ExecTemplateConfig) {
+	for _, name := range slices.Sorted(maps.Keys(templates)) {
+		tc := templates[name]
+		t, err := agent.NewExecTemplate(name, agent.ExecSpec{
+			Bin:         tc.Bin,
+			Args:        tc.Args,
+			ExecuteArgs: tc.ExecuteArgs,
+			Input:       tc.Input,
+			Output:      tc.Output,
+			ResultField: tc.ResultField,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v (template skipped)\n", err)
+			continue
+		}
+		r.UseExecTemplate(t)
+	}
 }
 
 // skillSet holds a resolved set of skills and their provenance.
@@ -98,6 +184,35 @@ func resolveSkillSet(reg *registry.Registry, mode, bundle string) (skillSet, err
 	return skillSet{Skills: skills, Source: "bundle:" + bundle}, err
 }
 
+// resolveFailOnOverride returns the explicitly requested failure
+// threshold: --fail-on flag > config check.fail_on. Empty means the
+// skills.yaml defaults apply.
+func resolveFailOnOverride(cfg *config.Config, flag string) (registry.FailOn, error) {
+	v := cfg.Check.FailOn
+	if flag != "" {
+		v = flag
+	}
+	if v == "" {
+		return "", nil
+	}
+	return registry.ParseFailOn(v)
+}
+
+// resolveFailOn resolves the failure threshold for a mode- or
+// bundle-selected skill set: --fail-on > config check.fail_on >
+// skills.yaml defaults.fail_on.{modes,bundles} > defaults.bundles_fail_on
+// (bundles only) > blocking.
+func resolveFailOn(reg *registry.Registry, cfg *config.Config, flag, mode, bundle string) (registry.FailOn, error) {
+	override, err := resolveFailOnOverride(cfg, flag)
+	if err != nil || override != "" {
+		return override, err
+	}
+	if mode != "" {
+		return reg.FailOnForMode(registry.GovMode(mode))
+	}
+	return reg.FailOnForBundle(bundle)
+}
+
 // resolveConcurrency resolves concurrency: flag > config > unlimited (0).
 func resolveConcurrency(cfg *config.Config, c *cli.Context) int {
 	concurrency := 0
@@ -110,6 +225,102 @@ func resolveConcurrency(cfg *config.Config, c *cli.Context) int {
 	return concurrency
 }
 
+// resolveTimeout returns the run deadline: --timeout > config
+// check.timeout. Zero means unbounded.
+func resolveTimeout(cfg *config.Config, flag time.Duration) time.Duration {
+	if flag > 0 {
+		return flag
+	}
+	return cfg.Check.Timeout
+}
+
+// resolveMaxCost returns the run budget in USD: --budget > config
+// check.max_cost. Zero means unlimited.
+func resolveMaxCost(cfg *config.Config, flag float64) float64 {
+	if flag > 0 {
+		return flag
+	}
+	return cfg.Check.MaxCost
+}
+
+// resolveStrategy returns the dispatch strategy: --strategy > config
+// check.strategy > parallel.
+func resolveStrategy(cfg *config.Config, flag string) (orchestrator.Strategy, error) {
+	if flag != "" {
+		return orchestrator.ParseStrategy(flag)
+	}
+	return orchestrator.ParseStrategy(cfg.Check.Strategy)
+}
+
+// loadDiffProfile parses a --diff-profile value: inline JSON when it
+// starts with "{", otherwise a path to a JSON file. Empty returns nil,
+// leaving the orchestrator to compute the profile from --base.
+func loadDiffProfile(spec string) (*diff.Profile, error) {
+	if spec == "" {
+		return nil, nil //nolint:nilnil // no profile means "compute from --base"
+	}
+	data := []byte(spec)
+	if !strings.HasPrefix(strings.TrimSpace(spec), "{") {
+		var err error
+		if data, err = os.ReadFile(spec); err != nil {
+			return nil, fmt.Errorf("read diff profile: %w", err)
+		}
+	}
+	var p diff.Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse diff profile: %w", err)
+	}
+	return &p, nil
+}
+
+// resolveScope combines --scope with positional path arguments into one
+// comma-separated scope. A leading "./" is dropped from paths. Flags
+// given after a path are not parsed, so a path that looks like a flag
+// is rejected rather than silently treated as a scope.
+func resolveScope(flag string, paths []string) (string, error) {
+	var scopes []string
+	if flag != "" {
+		scopes = append(scopes, flag)
+	}
+	for _, p := range paths {
+		if strings.HasPrefix(p, "-") {
+			return "", fmt.Errorf("flag %s after path arguments: flags must come before paths", p)
+		}
+		if p = strings.TrimPrefix(filepath.ToSlash(p), "./"); p != "" {
+			scopes = append(scopes, p)
+		}
+	}
+	return strings.Join(scopes, ","), nil
+}
+
+// resolveCacheDir returns the result cache directory: config > per-user default.
+func resolveCacheDir(cfg *config.Config) (string, error) {
+	if cfg.Cache.Dir != "" {
+		return cfg.Cache.Dir, nil
+	}
+	return cache.DefaultDir()
+}
+
+// openResultCache opens the skill result cache unless disabled by
+// config or --no-cache. Failure to open is non-fatal: the run proceeds
+// uncached with a warning.
+func openResultCache(cfg *config.Config, noCache bool) *cache.Store {
+	if noCache || cfg.Cache.Disabled {
+		return nil
+	}
+	dir, err := resolveCacheDir(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: result cache disabled: %v\n", err)
+		return nil
+	}
+	store, err := cache.Open(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: result cache disabled: %v\n", err)
+		return nil
+	}
+	return store
+}
+
 // fileExists checks whether a file exists at the given absolute path.
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
@@ -3,9 +3,12 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/pithecene-io/bonsai/internal/config"
 	"github.com/pithecene-io/bonsai/internal/gitutil"
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
 )
 
 func setupGitRepo(t *testing.T) string {
@@ -109,3 +112,130 @@ func TestEnsureFeatureBranch_CreatesWorktreeOnMain(t *testing.T) {
 	_ = gitutil.RemoveWorktree(dir, wt.WorktreePath)
 	_ = gitutil.DeleteBranch(dir, branch)
 }
+
+func TestOpenEventStream(t *testing.T) {
+	s, err := openEventStream("")
+	if err != nil || s != nil {
+		t.Fatalf("empty spec: got %v, %v; want nil stream", s, err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("nil stream Close: %v", err)
+	}
+
+	if _, err := openEventStream("json"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	s, err = openEventStream("ndjson=" + path)
+	if err != nil {
+		t.Fatalf("file spec: %v", err)
+	}
+	s.handler()(orchestrator.Event{Kind: orchestrator.EventComplete})
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"event":"complete"`) {
+		t.Errorf("unexpected stream contents: %s", data)
+	}
+	if s.out() != os.Stdout {
+		t.Error("file stream: human output should stay on stdout")
+	}
+}
+
+func TestOpenEventStream_StdoutMovesHumanOutput(t *testing.T) {
+	stdout := os.Stdout
+	s, err := openEventStream("ndjson")
+	if err != nil {
+		t.Fatalf("stdout spec: %v", err)
+	}
+	if os.Stdout != stdout {
+		t.Error("opening a stdout stream must not reassign os.Stdout")
+	}
+	if s.out() != os.Stderr {
+		t.Error("stdout stream: human output should go to stderr")
+	}
+	if s.useTUI(false) {
+		t.Error("stdout stream: TUI must not draw over the event stream")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	var nilStream *eventStream
+	if nilStream.out() != os.Stdout {
+		t.Error("nil stream: human output should stay on stdout")
+	}
+}
+
+func TestLoadDiffProfile(t *testing.T) {
+	p, err := loadDiffProfile("")
+	if err != nil || p != nil {
+		t.Fatalf("empty spec: got %v, %v; want nil profile", p, err)
+	}
+
+	p, err = loadDiffProfile(`{"files_changed": 1, "languages": ["go"]}`)
+	if err != nil {
+		t.Fatalf("inline JSON: %v", err)
+	}
+	if p.FilesChanged != 1 || len(p.Languages) != 1 {
+		t.Errorf("inline JSON: got %+v", p)
+	}
+
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(path, []byte(`{"changed_files": ["docs/a.md"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = loadDiffProfile(path)
+	if err != nil {
+		t.Fatalf("file: %v", err)
+	}
+	if len(p.ChangedFiles) != 1 || p.ChangedFiles[0] != "docs/a.md" {
+		t.Errorf("file: got %+v", p)
+	}
+
+	if _, err := loadDiffProfile("{not json"); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+}
+
+func TestResolveScope(t *testing.T) {
+	tests := []struct {
+		flag  string
+		paths []string
+		want  string
+	}{
+		{"", nil, ""},
+		{"internal/gate", nil, "internal/gate"},
+		{"", []string{"internal/gate", "./cmd/"}, "internal/gate,cmd/"},
+		{"docs/", []string{"internal/gate"}, "docs/,internal/gate"},
+	}
+	for _, tt := range tests {
+		got, err := resolveScope(tt.flag, tt.paths)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveScope(%q, %v) = %q, %v; want %q", tt.flag, tt.paths, got, err, tt.want)
+		}
+	}
+
+	if _, err := resolveScope("", []string{"internal/gate", "--base"}); err == nil {
+		t.Error("expected error for a flag after path arguments")
+	}
+}
+
+func TestCompatProfiles(t *testing.T) {
+	profiles := map[string]config.ModelProfile{
+		"local:coder": {MaxTokens: 2048},
+		"vllm:coder":  {MaxTokens: 1024},
+		"sonnet":      {MaxTokens: 64000},
+	}
+	got := compatProfiles("", profiles)
+	if len(got) != 1 || got["coder"].MaxTokens != 2048 {
+		t.Errorf("default prefix: got %+v, want only coder from local:coder", got)
+	}
+	if got := compatProfiles("vllm", profiles); got["coder"].MaxTokens != 1024 {
+		t.Errorf("vllm prefix: got %+v, want coder from vllm:coder", got)
+	}
+}
@@ -11,7 +11,11 @@ func implementCommand() *cli.Command {
 		Name:      "implement",
 		Usage:     "Start an implementation session with governance gating",
 		ArgsUsage: "[-- extra-args...]",
-		Action:    runImplement,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "fail-on", Usage: "Lowest severity that fails the gate (blocking, major, warning)"},
+			eventsFlag(),
+		},
+		Action: runImplement,
 	}
 }
 
@@ -30,17 +34,38 @@ func runImplement(c *cli.Context) error {
 		return err
 	}
 
-	loop := gate.New(gate.Opts{
-		RepoRoot:  repoRoot,
-		Config:    env.Config,
-		Agent:     newAgentRouter(env.Config),
-		Resolver:  env.Resolver,
-		ExtraArgs: c.Args().Slice(),
+	bl, err := loadBaseline(repoRoot, env.Config, "", false)
+	if err != nil {
+		return err
+	}
+
+	failOn, err := resolveFailOnOverride(env.Config, c.String("fail-on"))
+	if err != nil {
+		return err
+	}
+
+	return withEventStream(c.String("events"), func(stream *eventStream) error {
+		router := newAgentRouter(env.Config)
+		router.SetStdout(stream.out())
+		return runGateLoop(c, gate.Opts{
+			RepoRoot:  repoRoot,
+			Config:    env.Config,
+			Agent:     router,
+			Resolver:  env.Resolver,
+			ExtraArgs: c.Args().Slice(),
+			Baseline:  bl,
+			FailOn:    failOn,
+			Events:    stream.handler(),
+			Out:       stream.out(),
+		})
 	})
+}
 
+// runGateLoop preflights and runs the gating loop.
+func runGateLoop(c *cli.Context, opts gate.Opts) error {
+	loop := gate.New(opts)
 	if err := loop.Preflight(); err != nil {
 		return err
 	}
-
 	return loop.Run(c.Context)
 }
@@ -411,6 +411,7 @@ func (m *migration) validate(c *urfave.Context) {
 		RepoRoot:            m.target,
 		Config:              m.config,
 		DefaultRequiresDiff: reg.Defaults.EffectiveRequiresDiff(),
+		DefaultTimeout:      reg.Defaults.Timeout,
 		Concurrency:         1,
 	}, nil)
 	if err != nil {
@@ -190,6 +190,7 @@ func (ps *patchSession) validate(ctx context.Context) error {
 		RepoRoot:            ps.env.RepoRoot,
 		Config:              ps.env.Config,
 		DefaultRequiresDiff: ps.env.Registry.Defaults.EffectiveRequiresDiff(),
+		DefaultTimeout:      ps.env.Registry.Defaults.Timeout,
 		Concurrency:         1,
 	}, nil)
 	if err != nil {
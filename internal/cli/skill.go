@@ -11,6 +11,7 @@ import (
 	"github.com/pithecene-io/bonsai/internal/agent"
 	"github.com/pithecene-io/bonsai/internal/assets"
 	"github.com/pithecene-io/bonsai/internal/config"
+	"github.com/pithecene-io/bonsai/internal/native"
 	"github.com/pithecene-io/bonsai/internal/prompt"
 	"github.com/pithecene-io/bonsai/internal/registry"
 	"github.com/pithecene-io/bonsai/internal/repo"
@@ -21,10 +22,10 @@ func skillCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "skill",
 		Usage:     "Run a single governance skill",
-		ArgsUsage: "<skill-name>",
+		ArgsUsage: "<skill-name> [path...]",
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "version", Usage: "Skill version override"},
-			&cli.StringFlag{Name: "scope", Usage: "Comma-separated path prefixes to filter repo tree"},
+			&cli.StringFlag{Name: "scope", Usage: "Comma-separated path prefixes to filter repo tree and diff (positional paths are added)"},
 			&cli.StringFlag{Name: "base", Usage: "Git ref for diff context"},
 			&cli.StringFlag{Name: "model", Usage: "Model override (e.g. haiku, sonnet, opus)"},
 		},
@@ -35,7 +36,7 @@ func skillCommand() *cli.Command {
 func runSkill(c *cli.Context) error {
 	skillName := c.Args().First()
 	if skillName == "" {
-		return fmt.Errorf("usage: bonsai skill <skill-name> [--version vX] [--scope path1,path2] [--base <ref>]")
+		return fmt.Errorf("usage: bonsai skill <skill-name> [--version vX] [--scope path1,path2] [--base <ref>] [path...]")
 	}
 
 	env, err := bootstrap()
@@ -43,13 +44,11 @@ func runSkill(c *cli.Context) error {
 		return err
 	}
 
-	def, err := loadSkillDef(env.Resolver, env.Registry, skillName, c.String("version"))
+	scope, err := resolveScope(c.String("scope"), c.Args().Tail())
 	if err != nil {
 		return err
 	}
-
-	scope := c.String("scope")
-	repoTree, err := repo.TreeWithScope(env.RepoRoot, scope)
+	repoTree, err := repo.TreeWithScope(env.RepoRoot, scope, env.Config.Exclude)
 	if err != nil {
 		return fmt.Errorf("repo tree: %w", err)
 	}
@@ -59,17 +58,24 @@ func runSkill(c *cli.Context) error {
 
 	baseRef := c.String("base")
 	// Diff payload is best-effort; runs without diff context on error.
-	diffPayload, _ := skill.BuildDiffPayload(env.RepoRoot, baseRef)
-
-	runner := skill.NewRunner(newAgentRouter(env.Config), prompt.NewBuilder(env.Resolver, env.RepoRoot))
-	model := resolveSkillModel(c.String("model"), env.Registry, env.Config, skillName)
+	diffPayload, _ := skill.BuildScopedDiffPayload(env.RepoRoot, baseRef, repo.ScopePrefixes(scope), env.Config.Exclude)
 
-	output, err := runner.Run(c.Context, def, skill.RunOpts{
-		RepoTree:    strings.Join(repoTree, "\n"),
-		DiffPayload: diffPayload,
-		BaseRef:     baseRef,
-		Model:       agent.Model(model),
-	})
+	var output *skill.Output
+	if impl, ok := nativeSkill(env.Registry, skillName, c.String("version")); ok {
+		output, err = impl.Run(c.Context, skill.NativeInput{
+			RepoRoot:    env.RepoRoot,
+			Files:       repoTree,
+			DiffPayload: diffPayload,
+		})
+	} else {
+		output, err = runModelSkill(c, env, skillName, skill.RunOpts{
+			RepoTree:    strings.Join(repoTree, "\n"),
+			DiffPayload: diffPayload,
+			BaseRef:     baseRef,
+			RepoRoot:    env.RepoRoot,
+			Scope:       repo.ScopePrefixes(scope),
+		})
+	}
 	if err != nil {
 		return err
 	}
@@ -86,6 +92,38 @@ func runSkill(c *cli.Context) error {
 	return nil
 }
 
+// nativeSkill returns the built-in implementation of a registry skill
+// declaring engine: native. An explicit --version always selects the
+// model-backed SKILL.md.
+func nativeSkill(reg *registry.Registry, name, version string) (skill.NativeSkill, bool) {
+	s, ok := reg.LookupSkill(name)
+	if !ok || version != "" || !s.IsNative() {
+		return nil, false
+	}
+	return native.Lookup(name)
+}
+
+// runModelSkill evaluates a skill by prompting the configured model,
+// or by running its exec entrypoint when SKILL.md declares one.
+func runModelSkill(c *cli.Context, env cmdEnv, name string, opts skill.RunOpts) (*skill.Output, error) {
+	def, err := loadSkillDef(env.Resolver, env.Registry, name, c.String("version"))
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := env.Registry.LookupSkill(name); ok {
+		opts.Config = s.Config
+	}
+	runner := skill.NewRunner(newAgentRouter(env.Config), prompt.NewBuilder(env.Resolver, env.RepoRoot),
+		skill.WithRepairRetries(env.Config.Check.EffectiveRepairRetries()),
+		skill.WithWithheldEnv(env.Config.Providers.CredentialEnv()...),
+	)
+	opts.Model = agent.Model(resolveSkillModel(c.String("model"), env.Registry, env.Config, name))
+	id, family := env.Config.Models.ResolveAlias(string(opts.Model)), opts.Model.Tier()
+	opts.DiffBudget = env.Config.Models.DiffBudgetFor(id, family)
+	opts.ContextWindow = env.Config.Models.ContextWindowFor(id, family)
+	return runner.Run(c.Context, def, opts)
+}
+
 // loadSkillDef resolves the skill version from the registry (if not overridden)
 // and loads the skill definition.
 func loadSkillDef(resolver *assets.Resolver, reg *registry.Registry, name, version string) (*skill.Definition, error) {
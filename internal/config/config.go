@@ -2,6 +2,13 @@
 // merge chain: embedded defaults → user config → repo config → env → flags.
 package config
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Config is the top-level bonsai configuration.
 type Config struct {
 	Diff      DiffConfig      `yaml:"diff"`
@@ -14,11 +21,35 @@ type Config struct {
 	Models    ModelsConfig    `yaml:"models"`
 	Output    OutputConfig    `yaml:"output"`
 	Skills    SkillsConfig    `yaml:"skills"`
+	Cache     CacheConfig     `yaml:"cache"`
+
+	// Exclude lists gitignore-syntax patterns, applied after
+	// .bonsaiignore, for paths left out of repo trees, diff payloads,
+	// and diff profiles.
+	Exclude []string `yaml:"exclude"`
 }
 
 // CheckConfig controls the check command.
 type CheckConfig struct {
-	Concurrency *int `yaml:"concurrency"`
+	Concurrency   *int          `yaml:"concurrency"`
+	Baseline      string        `yaml:"baseline"`       // repo-relative findings baseline file
+	FailOn        string        `yaml:"fail_on"`        // failure threshold override; empty defers to skills.yaml
+	RepairRetries *int          `yaml:"repair_retries"` // re-prompts after a skill response fails validation
+	Timeout       time.Duration `yaml:"timeout"`        // deadline for the whole run; 0 = unbounded
+	MaxCost       float64       `yaml:"max_cost"`       // USD spend after which no new skills start; 0 = unlimited
+	Strategy      string        `yaml:"strategy"`       // dispatch strategy: parallel (default) or staged by cost tier
+}
+
+// defaultRepairRetries is used when check.repair_retries is unset.
+const defaultRepairRetries = 1
+
+// EffectiveRepairRetries returns the schema-repair retry count,
+// defaulting to 1. Negative values disable repair.
+func (c *CheckConfig) EffectiveRepairRetries() int {
+	if c.RepairRetries == nil {
+		return defaultRepairRetries
+	}
+	return max(*c.RepairRetries, 0)
 }
 
 func intPtr(n int) *int { return &n }
@@ -30,11 +61,25 @@ type DiffConfig struct {
 	PatchMaxFiles     int `yaml:"patch_max_files"`
 }
 
-// RoutingConfig controls mode determination routing.
+// RoutingConfig controls mode determination routing and agent backend
+// fallback.
+//
+//	routing:
+//	  backends:                # ordered fallback chain per model family
+//	    claude: [anthropic, claude-cli]
+//	    codex: [codex-cli]
+//	    default: [claude-cli]  # models of neither family
+//	  retries:                 # extra attempts per backend before falling back
+//	    anthropic: 1
+//	  circuit_breaker: 3       # failures in a row before a backend is skipped for the run; <0 = never
 type RoutingConfig struct {
 	PublicSurfaceGlobs  []string `yaml:"public_surface_globs"`
 	StructuralPatterns  []string `yaml:"structural_patterns"`
 	MergeBaseCandidates []string `yaml:"merge_base_candidates"`
+
+	Backends       map[string][]string `yaml:"backends"`
+	Retries        map[string]int      `yaml:"retries"`
+	CircuitBreaker int                 `yaml:"circuit_breaker"`
 }
 
 // GateConfig controls the gating loop.
@@ -49,19 +94,68 @@ type FixConfig struct {
 
 // ProvidersConfig holds upstream API credentials.
 type ProvidersConfig struct {
-	Anthropic AnthropicConfig `yaml:"anthropic"`
+	Anthropic    AnthropicConfig    `yaml:"anthropic"`
+	OpenAICompat OpenAICompatConfig `yaml:"openai_compat"`
+}
+
+// CredentialEnv returns the configured environment variables that hold
+// provider credentials, beyond the Anthropic ones bonsai always reads.
+func (p ProvidersConfig) CredentialEnv() []string {
+	if p.OpenAICompat.APIKeyEnv == "" {
+		return nil
+	}
+	return []string{p.OpenAICompat.APIKeyEnv}
 }
 
 // AnthropicConfig holds direct Anthropic API settings.
 // When APIKey is empty, the agent falls back to ANTHROPIC_API_KEY env.
 type AnthropicConfig struct {
-	APIKey string `yaml:"api_key"`
+	APIKey string               `yaml:"api_key"`
+	Tools  AnthropicToolsConfig `yaml:"tools"`
+}
+
+// AnthropicToolsConfig configures the tool-use loop that runs fix,
+// implement, and interactive sessions over the API when the claude
+// binary is not installed. Tools are confined to the repository root.
+//
+//	providers:
+//	  anthropic:
+//	    tools:
+//	      allowed_commands: ["go test", "go vet", "make lint"]  # argv prefixes; no shell
+//	      max_turns: 50
+//	      command_timeout: 5m                                  # per command
+type AnthropicToolsConfig struct {
+	AllowedCommands []string      `yaml:"allowed_commands"`
+	MaxTurns        int           `yaml:"max_turns"`
+	CommandTimeout  time.Duration `yaml:"command_timeout"`
 }
 
-// AgentsConfig holds agent binary paths.
+// OpenAICompatConfig configures an OpenAI-compatible chat completions
+// endpoint (Ollama, vLLM, llama.cpp, LM Studio, OpenRouter, ...).
+// Models named "<prefix>:<name>" are routed to it; the backend is
+// disabled while BaseURL is empty.
+//
+// YAML path: providers.openai_compat
+//
+//	providers:
+//	  openai_compat:
+//	    base_url: http://localhost:11434/v1
+//	    api_key_env: OPENROUTER_API_KEY   # env var holding the bearer token
+//	    prefix: local                     # models named local:<name>
+//	    models:                           # <name> → served model id
+//	      coder: qwen2.5-coder:32b
+type OpenAICompatConfig struct {
+	BaseURL   string            `yaml:"base_url"`
+	APIKeyEnv string            `yaml:"api_key_env"`
+	Prefix    string            `yaml:"prefix"`
+	Models    map[string]string `yaml:"models"`
+}
+
+// AgentsConfig holds agent binary paths and command templates.
 type AgentsConfig struct {
-	Claude AgentBinConfig `yaml:"claude"`
-	Codex  AgentBinConfig `yaml:"codex"`
+	Claude    AgentBinConfig                `yaml:"claude"`
+	Codex     AgentBinConfig                `yaml:"codex"`
+	Templates map[string]ExecTemplateConfig `yaml:"templates"`
 }
 
 // AgentBinConfig holds the path to an agent binary.
@@ -69,6 +163,30 @@ type AgentBinConfig struct {
 	Bin string `yaml:"bin"`
 }
 
+// ExecTemplateConfig describes an arbitrary AI CLI as a command
+// template. Models named "<name>" or "<name>:<model>" are routed to the
+// template registered under <name>. Args are Go templates over
+// {{.Model}}, {{.SystemPrompt}}, and {{.PromptFile}}.
+//
+// YAML path: agents.templates.<name>
+//
+//	agents:
+//	  templates:
+//	    gemini:
+//	      bin: gemini
+//	      args: ["--model", "{{.Model}}", "--output-format", "json"]
+//	      input: stdin          # stdin | file ({{.PromptFile}})
+//	      output: json          # text | json
+//	      result_field: response
+type ExecTemplateConfig struct {
+	Bin         string   `yaml:"bin"`
+	Args        []string `yaml:"args"`
+	ExecuteArgs []string `yaml:"execute_args"` // argv for tool-enabled runs; empty = unsupported
+	Input       string   `yaml:"input"`
+	Output      string   `yaml:"output"`
+	ResultField string   `yaml:"result_field"` // dotted path to the text in JSON output
+}
+
 // ModelsConfig controls model selection per skill cost tier and role.
 //
 // YAML path: models
@@ -84,9 +202,108 @@ type AgentBinConfig struct {
 //	    reviewer: codex        # code review (uses codex agent)
 //	    patcher: sonnet        # patch surgery
 //	    chat: sonnet           # interactive chat
+//	  pricing:                 # USD per million tokens, by model or family
+//	    sonnet: {input: 3, output: 15}
+//	  diff_budget:             # max diff bytes per skill prompt, by model or family
+//	    haiku: 200000
+//	  aliases:                 # short name → concrete model id
+//	    sonnet: claude-sonnet-4-6
+//	  profiles:                # generation settings, by model or family
+//	    opus: {max_tokens: 8192, thinking_budget: 4096, context_window: 200000}
 type ModelsConfig struct {
-	Skills SkillModels `yaml:"skills"`
-	Roles  RoleModels  `yaml:"roles"`
+	Skills     SkillModels             `yaml:"skills"`
+	Roles      RoleModels              `yaml:"roles"`
+	Pricing    map[string]ModelRate    `yaml:"pricing"`
+	DiffBudget map[string]int          `yaml:"diff_budget"`
+	Aliases    map[string]string       `yaml:"aliases"`
+	Profiles   map[string]ModelProfile `yaml:"profiles"`
+}
+
+// ModelProfile holds a model's generation settings. Zero fields defer
+// to the backend's defaults.
+type ModelProfile struct {
+	MaxTokens      int      `yaml:"max_tokens"`      // response token cap
+	Temperature    *float64 `yaml:"temperature"`     // nil = provider default; ignored with thinking
+	ThinkingBudget int      `yaml:"thinking_budget"` // extended-thinking tokens; 0 = off
+	ContextWindow  int      `yaml:"context_window"`  // tokens the prompt budget is sized against
+}
+
+// ModelRate is a model's price in USD per million tokens.
+type ModelRate struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// Cost prices a token count at this rate.
+func (r ModelRate) Cost(inputTokens, outputTokens int64) float64 {
+	return (float64(inputTokens)*r.Input + float64(outputTokens)*r.Output) / 1e6
+}
+
+// RateFor returns the rate for the first of names present in the
+// pricing table, e.g. a full model name and then its family.
+func (m ModelsConfig) RateFor(names ...string) (ModelRate, bool) {
+	for _, n := range names {
+		if r, ok := m.Pricing[n]; ok {
+			return r, true
+		}
+	}
+	return ModelRate{}, false
+}
+
+// DiffBudgetFor returns the diff budget in bytes for the first of names
+// present in the diff_budget table. Zero means unlimited: the diff is
+// sent in one prompt.
+func (m ModelsConfig) DiffBudgetFor(names ...string) int {
+	for _, n := range names {
+		if b, ok := m.DiffBudget[n]; ok {
+			return b
+		}
+	}
+	return 0
+}
+
+// ProfileFor returns the profile for the first of names present in the
+// profiles table, e.g. a full model name and then its family.
+func (m ModelsConfig) ProfileFor(names ...string) (ModelProfile, bool) {
+	for _, n := range names {
+		if p, ok := m.Profiles[n]; ok {
+			return p, true
+		}
+	}
+	return ModelProfile{}, false
+}
+
+// ResolveAlias returns the model identifier name maps to in the aliases
+// table, matched case-insensitively like the router does, or name
+// unchanged.
+func (m ModelsConfig) ResolveAlias(name string) string {
+	if id, ok := m.Aliases[name]; ok {
+		return id
+	}
+	if id, ok := m.Aliases[strings.ToLower(name)]; ok {
+		return id
+	}
+	return name
+}
+
+// ModelIdentity describes the model an alias-resolved id is evaluated
+// with — the id and its generation settings — so result cache keys
+// change when an alias is re-pinned or a profile edited.
+func (m ModelsConfig) ModelIdentity(id, family string) string {
+	p, _ := m.ProfileFor(id, family)
+	temp := "default"
+	if p.Temperature != nil {
+		temp = strconv.FormatFloat(*p.Temperature, 'g', -1, 64)
+	}
+	return fmt.Sprintf("%s max_tokens=%d temperature=%s thinking_budget=%d", id, p.MaxTokens, temp, p.ThinkingBudget)
+}
+
+// ContextWindowFor returns the context window in tokens from the profile
+// of the first of names present in the profiles table. Zero means the
+// model's built-in window.
+func (m ModelsConfig) ContextWindowFor(names ...string) int {
+	p, _ := m.ProfileFor(names...)
+	return p.ContextWindow
 }
 
 // SkillModels maps cost tiers to model names for skill invocations.
@@ -137,6 +354,18 @@ type SkillsConfig struct {
 	ExtraDirs []string `yaml:"extra_dirs"`
 }
 
+// CacheConfig controls the skill result cache.
+//
+// YAML path: cache
+//
+//	cache:
+//	  dir: ""          # empty = per-user cache dir (e.g. ~/.cache/bonsai/results)
+//	  disabled: false  # true = always re-evaluate (same as --no-cache)
+type CacheConfig struct {
+	Dir      string `yaml:"dir"`
+	Disabled bool   `yaml:"disabled"`
+}
+
 // Default returns the default configuration, matching the values
 // from the original shell scripts.
 func Default() *Config {
@@ -167,16 +396,28 @@ func Default() *Config {
 				"origin/main",
 				"origin/master",
 			},
+			Backends: map[string][]string{
+				"claude":  {"anthropic", "claude-cli"},
+				"codex":   {"codex-cli"},
+				"default": {"claude-cli"},
+			},
+			CircuitBreaker: 3,
 		},
 		Gate: GateConfig{
 			MaxIterations: 3,
 		},
 		Check: CheckConfig{
-			Concurrency: intPtr(0), // 0 = unlimited (all skills in parallel)
+			Concurrency:   intPtr(0), // 0 = unlimited (all skills in parallel)
+			Baseline:      "ai/baseline.json",
+			RepairRetries: intPtr(defaultRepairRetries),
 		},
 		Fix: FixConfig{
 			MaxIterations: 3,
 		},
+		Providers: ProvidersConfig{
+			Anthropic:    AnthropicConfig{Tools: AnthropicToolsConfig{MaxTurns: 50, CommandTimeout: 5 * time.Minute}},
+			OpenAICompat: OpenAICompatConfig{Prefix: "local"},
+		},
 		Agents: AgentsConfig{
 			Claude: AgentBinConfig{Bin: "claude"},
 			Codex:  AgentBinConfig{Bin: "codex"},
@@ -194,6 +435,26 @@ func Default() *Config {
 				Patcher:     "sonnet",
 				Chat:        "sonnet",
 			},
+			Pricing: map[string]ModelRate{
+				"haiku":  {Input: 1, Output: 5},
+				"sonnet": {Input: 3, Output: 15},
+				"opus":   {Input: 5, Output: 25},
+			},
+			DiffBudget: map[string]int{
+				"haiku":  200_000,
+				"sonnet": 400_000,
+				"opus":   400_000,
+			},
+			Aliases: map[string]string{
+				"haiku":  "claude-haiku-4-5-20251001",
+				"sonnet": "claude-sonnet-4-6",
+				"opus":   "claude-opus-4-6",
+			},
+			Profiles: map[string]ModelProfile{
+				"haiku":  {MaxTokens: 4096, ContextWindow: 200_000},
+				"sonnet": {MaxTokens: 8192, ContextWindow: 200_000},
+				"opus":   {MaxTokens: 8192, ContextWindow: 200_000},
+			},
 		},
 		Output: OutputConfig{
 			Dir: "ai/out",
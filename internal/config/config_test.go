@@ -3,7 +3,9 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pithecene-io/bonsai/internal/config"
 )
@@ -437,3 +439,385 @@ func TestLoadRepoConfig_ConcurrencyZeroOverridesNonZero(t *testing.T) {
 	}
 }
 
+
+func TestLoadCacheConfig(t *testing.T) {
+	dir := t.TempDir()
+	repoConfig := filepath.Join(dir, ".bonsai.yaml")
+	if err := os.WriteFile(repoConfig, []byte("cache:\n  dir: /tmp/bonsai-cache\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("BONSAI_NO_CACHE", "true")
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Cache.Dir != "/tmp/bonsai-cache" {
+		t.Errorf("Cache.Dir = %q, want /tmp/bonsai-cache", cfg.Cache.Dir)
+	}
+	if !cfg.Cache.Disabled {
+		t.Error("Cache.Disabled = false, want true from BONSAI_NO_CACHE")
+	}
+}
+
+func TestLoadCheckFailOn(t *testing.T) {
+	dir := t.TempDir()
+	repoConfig := filepath.Join(dir, ".bonsai.yaml")
+	if err := os.WriteFile(repoConfig, []byte("check:\n  fail_on: major\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.FailOn != "major" {
+		t.Errorf("Check.FailOn = %q, want major", cfg.Check.FailOn)
+	}
+
+	t.Setenv("BONSAI_CHECK_FAIL_ON", "warning")
+	cfg, err = config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.FailOn != "warning" {
+		t.Errorf("Check.FailOn = %q, want warning from env", cfg.Check.FailOn)
+	}
+}
+
+func TestCheckRepairRetries(t *testing.T) {
+	if got := config.Default().Check.EffectiveRepairRetries(); got != 1 {
+		t.Errorf("default EffectiveRepairRetries = %d, want 1", got)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte("check:\n  repair_retries: 0\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Check.EffectiveRepairRetries(); got != 0 {
+		t.Errorf("EffectiveRepairRetries = %d, want explicit 0", got)
+	}
+
+	t.Setenv("BONSAI_CHECK_REPAIR_RETRIES", "3")
+	cfg, err = config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Check.EffectiveRepairRetries(); got != 3 {
+		t.Errorf("EffectiveRepairRetries = %d, want 3 from env", got)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte("check:\n  timeout: 15m\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.Timeout != 15*time.Minute {
+		t.Errorf("Timeout = %v, want 15m", cfg.Check.Timeout)
+	}
+
+	t.Setenv("BONSAI_CHECK_TIMEOUT", "90s")
+	cfg, err = config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.Timeout != 90*time.Second {
+		t.Errorf("Timeout = %v, want 90s from env", cfg.Check.Timeout)
+	}
+}
+
+func TestPricingAndMaxCost(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "check:\n  max_cost: 2.5\nmodels:\n  pricing:\n    sonnet: {input: 2, output: 10}\n    local: {input: 0.1, output: 0.1}\n"
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.MaxCost != 2.5 {
+		t.Errorf("MaxCost = %v, want 2.5", cfg.Check.MaxCost)
+	}
+
+	tests := []struct {
+		names []string
+		want  config.ModelRate
+		ok    bool
+	}{
+		{[]string{"sonnet"}, config.ModelRate{Input: 2, Output: 10}, true},
+		{[]string{"haiku"}, config.ModelRate{Input: 1, Output: 5}, true}, // default kept
+		{[]string{"claude-opus-4-6", "opus"}, config.ModelRate{Input: 5, Output: 25}, true},
+		{[]string{"local"}, config.ModelRate{Input: 0.1, Output: 0.1}, true},
+		{[]string{"codex"}, config.ModelRate{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.Models.RateFor(tt.names...)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RateFor(%v) = %+v, %v; want %+v, %v", tt.names, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got := (config.ModelRate{Input: 3, Output: 15}).Cost(1_000_000, 100_000); got != 4.5 {
+		t.Errorf("Cost = %v, want 4.5", got)
+	}
+}
+
+func TestLoadCheckStrategy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte("check:\n  strategy: staged\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.Strategy != "staged" {
+		t.Errorf("Check.Strategy = %q, want staged", cfg.Check.Strategy)
+	}
+
+	t.Setenv("BONSAI_CHECK_STRATEGY", "parallel")
+	cfg, err = config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Check.Strategy != "parallel" {
+		t.Errorf("Check.Strategy = %q, want parallel from env", cfg.Check.Strategy)
+	}
+}
+
+func TestDiffBudget(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "models:\n  diff_budget:\n    haiku: 50000\n    local: 8000\n"
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		names []string
+		want  int
+	}{
+		{[]string{"haiku"}, 50_000},
+		{[]string{"claude-sonnet-4-6", "sonnet"}, 400_000}, // default kept
+		{[]string{"local"}, 8000},
+		{[]string{"codex"}, 0},
+	}
+	for _, tt := range tests {
+		if got := cfg.Models.DiffBudgetFor(tt.names...); got != tt.want {
+			t.Errorf("DiffBudgetFor(%v) = %d, want %d", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestLoadExclude(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "exclude:\n  - vendor/\n  - \"*.pb.go\"\n"
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Exclude) != 2 || cfg.Exclude[0] != "vendor/" || cfg.Exclude[1] != "*.pb.go" {
+		t.Errorf("Exclude = %v, want [vendor/ *.pb.go]", cfg.Exclude)
+	}
+}
+
+func TestLoadOpenAICompat(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "providers:\n  openai_compat:\n    base_url: http://localhost:11434/v1\n    api_key_env: LOCAL_KEY\n    models:\n      coder: qwen2.5-coder:32b\n"
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	c := cfg.Providers.OpenAICompat
+	if c.BaseURL != "http://localhost:11434/v1" || c.APIKeyEnv != "LOCAL_KEY" {
+		t.Errorf("OpenAICompat = %+v", c)
+	}
+	if c.Prefix != "local" {
+		t.Errorf("Prefix = %q, want default local", c.Prefix)
+	}
+	if c.Models["coder"] != "qwen2.5-coder:32b" {
+		t.Errorf("Models[coder] = %q, want qwen2.5-coder:32b", c.Models["coder"])
+	}
+	if got := cfg.Providers.CredentialEnv(); len(got) != 1 || got[0] != "LOCAL_KEY" {
+		t.Errorf("CredentialEnv() = %v, want [LOCAL_KEY]", got)
+	}
+
+	t.Setenv("BONSAI_PROVIDER_OPENAI_COMPAT_BASE_URL", "http://gpu-box:8000/v1")
+	cfg, err = config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Providers.OpenAICompat.BaseURL; got != "http://gpu-box:8000/v1" {
+		t.Errorf("BaseURL = %q, want env override", got)
+	}
+}
+
+func TestLoadExecTemplates(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `agents:
+  templates:
+    gemini:
+      bin: gemini
+      args: ["--model", "{{.Model}}"]
+      output: json
+      result_field: response
+`
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tc, ok := cfg.Agents.Templates["gemini"]
+	if !ok {
+		t.Fatalf("Templates = %v, want gemini", cfg.Agents.Templates)
+	}
+	if tc.Bin != "gemini" || len(tc.Args) != 2 || tc.Args[1] != "{{.Model}}" || tc.Output != "json" || tc.ResultField != "response" {
+		t.Errorf("gemini template = %+v", tc)
+	}
+	if cfg.Agents.Claude.Bin != "claude" {
+		t.Errorf("Claude.Bin = %q, want default kept", cfg.Agents.Claude.Bin)
+	}
+}
+
+func TestLoadRoutingBackends(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `routing:
+  backends:
+    codex: [codex-cli, claude-cli]
+  retries:
+    anthropic: 2
+  circuit_breaker: -1
+`
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	r := cfg.Routing
+	if got := r.Backends["codex"]; len(got) != 2 || got[1] != "claude-cli" {
+		t.Errorf("Backends[codex] = %v, want override", got)
+	}
+	if got := r.Backends["claude"]; len(got) != 2 || got[0] != "anthropic" {
+		t.Errorf("Backends[claude] = %v, want default kept", got)
+	}
+	if r.Retries["anthropic"] != 2 {
+		t.Errorf("Retries[anthropic] = %d, want 2", r.Retries["anthropic"])
+	}
+	if r.CircuitBreaker != -1 {
+		t.Errorf("CircuitBreaker = %d, want -1", r.CircuitBreaker)
+	}
+}
+
+func TestLoadModelTables(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `models:
+  aliases:
+    sonnet: claude-sonnet-4-5
+    fast: local:qwen
+  profiles:
+    opus:
+      thinking_budget: 4096
+      temperature: 0.3
+`
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	m := cfg.Models
+	if m.Aliases["sonnet"] != "claude-sonnet-4-5" || m.Aliases["fast"] != "local:qwen" {
+		t.Errorf("Aliases = %v, want overrides applied", m.Aliases)
+	}
+	if m.Aliases["haiku"] == "" {
+		t.Error("default haiku alias should be kept")
+	}
+	p, ok := m.ProfileFor("claude-opus-4-6", "opus")
+	if !ok {
+		t.Fatal("opus profile missing")
+	}
+	// Fields merge individually: the default cap and window are kept.
+	if p.ThinkingBudget != 4096 || p.Temperature == nil || *p.Temperature != 0.3 || p.MaxTokens != 8192 {
+		t.Errorf("opus profile = %+v, want thinking and temperature over defaults", p)
+	}
+	if got := m.ContextWindowFor("unknown", "opus"); got != 200_000 {
+		t.Errorf("ContextWindowFor(opus) = %d, want 200000", got)
+	}
+}
+
+func TestLoadAnthropicTools(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `providers:
+  anthropic:
+    tools:
+      allowed_commands: ["go test", "make lint"]
+`
+	if err := os.WriteFile(filepath.Join(dir, ".bonsai.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tools := cfg.Providers.Anthropic.Tools
+	if len(tools.AllowedCommands) != 2 || tools.AllowedCommands[1] != "make lint" {
+		t.Errorf("AllowedCommands = %v, want override", tools.AllowedCommands)
+	}
+	if tools.MaxTurns != 50 {
+		t.Errorf("MaxTurns = %d, want default 50", tools.MaxTurns)
+	}
+}
+
+func TestModelIdentity(t *testing.T) {
+	temp := 0.2
+	m := config.ModelsConfig{
+		Aliases:  map[string]string{"sonnet": "claude-sonnet-4-5"},
+		Profiles: map[string]config.ModelProfile{"sonnet": {MaxTokens: 8192}},
+	}
+	base := m.ModelIdentity(m.ResolveAlias("Sonnet"), "sonnet")
+	if !strings.HasPrefix(base, "claude-sonnet-4-5 ") {
+		t.Errorf("ModelIdentity = %q, want the resolved alias", base)
+	}
+
+	m.Aliases = map[string]string{"sonnet": "claude-sonnet-4-6"}
+	if got := m.ModelIdentity(m.ResolveAlias("sonnet"), "sonnet"); got == base {
+		t.Error("re-pinning the alias should change the identity")
+	}
+	m.Aliases = map[string]string{"sonnet": "claude-sonnet-4-5"}
+	m.Profiles = map[string]config.ModelProfile{"sonnet": {MaxTokens: 8192, Temperature: &temp}}
+	if got := m.ModelIdentity(m.ResolveAlias("sonnet"), "sonnet"); got == base {
+		t.Error("editing the profile should change the identity")
+	}
+}
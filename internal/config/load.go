@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -106,6 +107,7 @@ func mergeStringEnvs(cfg *Config) {
 		{"BONSAI_CLAUDE_BIN", &cfg.Agents.Claude.Bin},
 		{"BONSAI_CODEX_BIN", &cfg.Agents.Codex.Bin},
 		{"BONSAI_PROVIDER_ANTHROPIC_API_KEY", &cfg.Providers.Anthropic.APIKey},
+		{"BONSAI_PROVIDER_OPENAI_COMPAT_BASE_URL", &cfg.Providers.OpenAICompat.BaseURL},
 		{"BONSAI_MODEL_SKILL_CHEAP", &cfg.Models.Skills.Cheap},
 		{"BONSAI_MODEL_SKILL_MODERATE", &cfg.Models.Skills.Moderate},
 		{"BONSAI_MODEL_SKILL_HEAVY", &cfg.Models.Skills.Heavy},
@@ -115,6 +117,10 @@ func mergeStringEnvs(cfg *Config) {
 		{"BONSAI_MODEL_ROLE_PATCHER", &cfg.Models.Roles.Patcher},
 		{"BONSAI_MODEL_ROLE_CHAT", &cfg.Models.Roles.Chat},
 		{"BONSAI_OUTPUT_DIR", &cfg.Output.Dir},
+		{"BONSAI_CACHE_DIR", &cfg.Cache.Dir},
+		{"BONSAI_CHECK_BASELINE", &cfg.Check.Baseline},
+		{"BONSAI_CHECK_FAIL_ON", &cfg.Check.FailOn},
+		{"BONSAI_CHECK_STRATEGY", &cfg.Check.Strategy},
 	}
 	for _, b := range stringBindings {
 		if v := os.Getenv(b.env); v != "" {
@@ -123,16 +129,42 @@ func mergeStringEnvs(cfg *Config) {
 	}
 }
 
-// mergeMiscEnvs applies env overrides with non-trivial logic.
-func mergeMiscEnvs(cfg *Config) {
+// mergeCheckEnvs applies parsed (non-string) check env overrides.
+// Unparseable values are ignored.
+func mergeCheckEnvs(c *CheckConfig) {
 	if v := os.Getenv("BONSAI_CHECK_JOBS"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
-			cfg.Check.Concurrency = intPtr(n)
+			c.Concurrency = intPtr(n)
+		}
+	}
+	if v := os.Getenv("BONSAI_CHECK_REPAIR_RETRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.RepairRetries = intPtr(n)
 		}
 	}
+	if v := os.Getenv("BONSAI_CHECK_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.Timeout = d
+		}
+	}
+	if v := os.Getenv("BONSAI_CHECK_MAX_COST"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			c.MaxCost = f
+		}
+	}
+}
+
+// mergeMiscEnvs applies env overrides with non-trivial logic.
+func mergeMiscEnvs(cfg *Config) {
+	mergeCheckEnvs(&cfg.Check)
 	if v := os.Getenv("BONSAI_SKILLS_EXTRA_DIRS"); v != "" {
 		cfg.Skills.ExtraDirs = strings.Split(v, ":")
 	}
+	if v := os.Getenv("BONSAI_NO_CACHE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Cache.Disabled = b
+		}
+	}
 }
 
 // mergeConfig merges non-zero values from src into dst.
@@ -140,7 +172,11 @@ func mergeConfig(dst, src *Config) {
 	mergeDiffConfig(dst, src)
 	mergeRoutingConfig(dst, src)
 	mergeScalarConfig(dst, src)
+	mergeCheckConfig(&dst.Check, &src.Check)
+	mergeProvidersConfig(&dst.Providers, &src.Providers)
+	mergeAgentsConfig(&dst.Agents, &src.Agents)
 	mergeModelsConfig(&dst.Models, &src.Models)
+	mergeCacheConfig(&dst.Cache, &src.Cache)
 }
 
 // mergeDiffConfig merges diff threshold overrides.
@@ -167,6 +203,28 @@ func mergeRoutingConfig(dst, src *Config) {
 	if len(src.Routing.MergeBaseCandidates) > 0 {
 		dst.Routing.MergeBaseCandidates = src.Routing.MergeBaseCandidates
 	}
+	mergeRoutingBackends(&dst.Routing, &src.Routing)
+}
+
+// mergeRoutingBackends merges backend fallback settings. Chains merge
+// per family (a family's chain is replaced whole) and retries per
+// backend.
+func mergeRoutingBackends(dst, src *RoutingConfig) {
+	for family, chain := range src.Backends {
+		if dst.Backends == nil {
+			dst.Backends = make(map[string][]string)
+		}
+		dst.Backends[family] = chain
+	}
+	for name, n := range src.Retries {
+		if dst.Retries == nil {
+			dst.Retries = make(map[string]int)
+		}
+		dst.Retries[name] = n
+	}
+	if src.CircuitBreaker != 0 {
+		dst.CircuitBreaker = src.CircuitBreaker
+	}
 }
 
 // mergeScalarConfig merges remaining scalar config fields.
@@ -174,27 +232,130 @@ func mergeScalarConfig(dst, src *Config) {
 	if src.Gate.MaxIterations > 0 {
 		dst.Gate.MaxIterations = src.Gate.MaxIterations
 	}
-	if src.Check.Concurrency != nil {
-		dst.Check.Concurrency = src.Check.Concurrency
-	}
 	if src.Fix.MaxIterations > 0 {
 		dst.Fix.MaxIterations = src.Fix.MaxIterations
 	}
-	if src.Providers.Anthropic.APIKey != "" {
-		dst.Providers.Anthropic.APIKey = src.Providers.Anthropic.APIKey
-	}
-	if src.Agents.Claude.Bin != "" {
-		dst.Agents.Claude.Bin = src.Agents.Claude.Bin
-	}
-	if src.Agents.Codex.Bin != "" {
-		dst.Agents.Codex.Bin = src.Agents.Codex.Bin
-	}
 	if src.Output.Dir != "" {
 		dst.Output.Dir = src.Output.Dir
 	}
 	if len(src.Skills.ExtraDirs) > 0 {
 		dst.Skills.ExtraDirs = src.Skills.ExtraDirs
 	}
+	if len(src.Exclude) > 0 {
+		dst.Exclude = src.Exclude
+	}
+}
+
+// mergeProvidersConfig merges provider settings. The openai_compat
+// model map merges per key.
+func mergeProvidersConfig(dst, src *ProvidersConfig) {
+	if src.Anthropic.APIKey != "" {
+		dst.Anthropic.APIKey = src.Anthropic.APIKey
+	}
+	if src.Anthropic.Tools.AllowedCommands != nil {
+		dst.Anthropic.Tools.AllowedCommands = src.Anthropic.Tools.AllowedCommands
+	}
+	if src.Anthropic.Tools.MaxTurns > 0 {
+		dst.Anthropic.Tools.MaxTurns = src.Anthropic.Tools.MaxTurns
+	}
+	if src.Anthropic.Tools.CommandTimeout > 0 {
+		dst.Anthropic.Tools.CommandTimeout = src.Anthropic.Tools.CommandTimeout
+	}
+	compat := []struct {
+		src string
+		dst *string
+	}{
+		{src.OpenAICompat.BaseURL, &dst.OpenAICompat.BaseURL},
+		{src.OpenAICompat.APIKeyEnv, &dst.OpenAICompat.APIKeyEnv},
+		{src.OpenAICompat.Prefix, &dst.OpenAICompat.Prefix},
+	}
+	for _, c := range compat {
+		if c.src != "" {
+			*c.dst = c.src
+		}
+	}
+	for name, id := range src.OpenAICompat.Models {
+		if dst.OpenAICompat.Models == nil {
+			dst.OpenAICompat.Models = make(map[string]string)
+		}
+		dst.OpenAICompat.Models[name] = id
+	}
+}
+
+// mergeAgentsConfig merges agent binaries. Templates merge per name; a
+// template redefined by a later source replaces the earlier one whole.
+func mergeAgentsConfig(dst, src *AgentsConfig) {
+	if src.Claude.Bin != "" {
+		dst.Claude.Bin = src.Claude.Bin
+	}
+	if src.Codex.Bin != "" {
+		dst.Codex.Bin = src.Codex.Bin
+	}
+	for name, t := range src.Templates {
+		if dst.Templates == nil {
+			dst.Templates = make(map[string]ExecTemplateConfig)
+		}
+		dst.Templates[name] = t
+	}
+}
+
+// mergeModelTables merges aliases per name and profiles per name and
+// field, so overriding one setting keeps the model's other defaults.
+func mergeModelTables(dst, src *ModelsConfig) {
+	for name, id := range src.Aliases {
+		if dst.Aliases == nil {
+			dst.Aliases = make(map[string]string)
+		}
+		dst.Aliases[name] = id
+	}
+	for name, p := range src.Profiles {
+		if dst.Profiles == nil {
+			dst.Profiles = make(map[string]ModelProfile)
+		}
+		dst.Profiles[name] = mergeProfile(dst.Profiles[name], p)
+	}
+}
+
+// mergeProfile overlays the set fields of src on dst.
+func mergeProfile(dst, src ModelProfile) ModelProfile {
+	if src.MaxTokens > 0 {
+		dst.MaxTokens = src.MaxTokens
+	}
+	if src.Temperature != nil {
+		dst.Temperature = src.Temperature
+	}
+	if src.ThinkingBudget > 0 {
+		dst.ThinkingBudget = src.ThinkingBudget
+	}
+	if src.ContextWindow > 0 {
+		dst.ContextWindow = src.ContextWindow
+	}
+	return dst
+}
+
+// mergeCheckConfig merges check command overrides.
+func mergeCheckConfig(dst, src *CheckConfig) {
+	if src.Concurrency != nil {
+		dst.Concurrency = src.Concurrency
+	}
+	if src.Baseline != "" {
+		dst.Baseline = src.Baseline
+	}
+	if src.FailOn != "" {
+		dst.FailOn = src.FailOn
+	}
+	if src.RepairRetries != nil {
+		dst.RepairRetries = src.RepairRetries
+	}
+	if src.Timeout > 0 {
+		dst.Timeout = src.Timeout
+	}
+	if src.MaxCost > 0 {
+		dst.MaxCost = src.MaxCost
+	}
+	if src.Strategy != "" {
+		dst.Strategy = src.Strategy
+	}
 }
 
 // mergeModelsConfig merges non-empty model config fields from src into dst.
@@ -228,4 +389,30 @@ func mergeModelsConfig(dst, src *ModelsConfig) {
 			*r.dst = r.src
 		}
 	}
+
+	// Pricing merges per model: a repo config overriding one rate keeps
+	// the defaults for the rest.
+	for name, rate := range src.Pricing {
+		if dst.Pricing == nil {
+			dst.Pricing = make(map[string]ModelRate)
+		}
+		dst.Pricing[name] = rate
+	}
+	for name, budget := range src.DiffBudget {
+		if dst.DiffBudget == nil {
+			dst.DiffBudget = make(map[string]int)
+		}
+		dst.DiffBudget[name] = budget
+	}
+	mergeModelTables(dst, src)
+}
+
+// mergeCacheConfig merges result cache overrides.
+func mergeCacheConfig(dst, src *CacheConfig) {
+	if src.Dir != "" {
+		dst.Dir = src.Dir
+	}
+	if src.Disabled {
+		dst.Disabled = true
+	}
 }
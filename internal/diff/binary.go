@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/gitutil"
+)
+
+// sniffLen is how much of a file is inspected for NUL bytes, matching
+// git's own binary heuristic.
+const sniffLen = 8000
+
+// IsBinary reports whether data looks binary: a NUL byte within its
+// first sniffLen bytes, as git decides.
+func IsBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), sniffLen)], 0) >= 0
+}
+
+// BinaryAttrFiles returns the files .gitattributes marks binary, either
+// with the "binary" macro or "-diff". Attribute lookup failures yield
+// an empty set, leaving detection to content sniffing.
+func BinaryAttrFiles(repoRoot string, files []string) map[string]bool {
+	binary := make(map[string]bool)
+	if len(files) == 0 {
+		return binary
+	}
+	lines, _ := gitutil.CheckAttr(repoRoot, []string{"binary", "diff"}, files)
+	for _, line := range lines {
+		rest, value, ok := cutLast(line, ": ")
+		if !ok {
+			continue
+		}
+		path, attr, ok := cutLast(rest, ": ")
+		if !ok {
+			continue
+		}
+		if (attr == "binary" && value == "set") || (attr == "diff" && value == "unset") {
+			binary[path] = true
+		}
+	}
+	return binary
+}
+
+// BinaryFiles returns the files that are binary by .gitattributes or by
+// content sniffing. Unreadable files are skipped.
+func BinaryFiles(repoRoot string, files []string) map[string]bool {
+	binary := BinaryAttrFiles(repoRoot, files)
+	for _, f := range files {
+		if !binary[f] && SniffBinary(filepath.Join(repoRoot, f)) {
+			binary[f] = true
+		}
+	}
+	return binary
+}
+
+// SniffBinary reads the first sniffLen bytes of a file and reports
+// whether it is binary. Unreadable files are reported as text.
+func SniffBinary(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+	head := make([]byte, sniffLen)
+	n, _ := io.ReadFull(f, head)
+	return IsBinary(head[:n])
+}
+
+// countBinaryDiffs counts "Binary files ... differ" stanzas in a diff.
+func countBinaryDiffs(diffOutput string) int {
+	n := 0
+	for _, line := range strings.Split(diffOutput, "\n") {
+		if strings.HasPrefix(line, "Binary files ") && strings.HasSuffix(line, " differ") {
+			n++
+		}
+	}
+	return n
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
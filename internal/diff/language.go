@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// extLanguages maps source file extensions to language names, as used
+// by run_when.languages in skills.yaml. Only programming languages are
+// listed: documentation and data files have no language, so a docs-only
+// diff touches no code.
+var extLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".kts":   "kotlin",
+	".scala": "scala",
+	".rb":    "ruby",
+	".php":   "php",
+	".cs":    "csharp",
+	".swift": "swift",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".cxx":   "cpp",
+	".hpp":   "cpp",
+	".m":     "objc",
+	".sh":    "shell",
+	".bash":  "shell",
+	".zsh":   "shell",
+	".sql":   "sql",
+	".lua":   "lua",
+	".ex":    "elixir",
+	".exs":   "elixir",
+	".erl":   "erlang",
+	".hs":    "haskell",
+	".dart":  "dart",
+	".zig":   "zig",
+	".proto": "protobuf",
+}
+
+// Language returns the programming language of a file by extension, or
+// "" for documentation, data, and unknown files.
+func Language(file string) string {
+	return extLanguages[strings.ToLower(path.Ext(file))]
+}
+
+// collectLanguages returns the sorted set of languages among files.
+func collectLanguages(files []string) []string {
+	var langs []string
+	for _, f := range files {
+		if lang := Language(f); lang != "" && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	slices.Sort(langs)
+	return langs
+}
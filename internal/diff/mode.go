@@ -13,10 +13,7 @@ import "github.com/pithecene-io/bonsai/internal/config"
 //  5. NORMAL (default)
 func DetermineMode(profile *Profile, cfg *config.Config, planIntent string) string {
 	// 1. HEAVY
-	if profile.DiffLines > cfg.Diff.HeavyDiffLines || profile.FilesChanged > cfg.Diff.HeavyFilesChanged {
-		return "HEAVY"
-	}
-	if profile.HasStructural && len(profile.PublicSurfacePaths) > 0 {
+	if IsHeavy(profile, cfg.Diff) {
 		return "HEAVY"
 	}
 
@@ -41,3 +38,13 @@ func DetermineMode(profile *Profile, cfg *config.Config, planIntent string) stri
 	// 5. NORMAL (default)
 	return "NORMAL"
 }
+
+// IsHeavy reports whether the diff is large or broad enough to review
+// as HEAVY: more lines or files than the thresholds allow, or a
+// structural change that also touches the public surface.
+func IsHeavy(profile *Profile, d config.DiffConfig) bool {
+	if profile.DiffLines > d.HeavyDiffLines || profile.FilesChanged > d.HeavyFilesChanged {
+		return true
+	}
+	return profile.HasStructural && len(profile.PublicSurfacePaths) > 0
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pithecene-io/bonsai/internal/config"
 	"github.com/pithecene-io/bonsai/internal/gitutil"
+	"github.com/pithecene-io/bonsai/internal/repo"
 )
 
 // Profile holds the diff profile computed from repository changes.
@@ -15,13 +16,17 @@ import (
 type Profile struct {
 	FilesChanged       int      `json:"files_changed"`
 	NewFiles           int      `json:"new_files"`
+	DeletedFiles       int      `json:"deleted_files"`
 	Renames            int      `json:"renames"`
+	BinaryFiles        int      `json:"binary_files"` // tracked and untracked binary files (counted in files_changed too)
 	LinesAdded         int      `json:"lines_added"`
 	LinesRemoved       int      `json:"lines_removed"`
 	DiffLines          int      `json:"diff_lines"`
 	TopLevelDirs       []string `json:"top_level_dirs"`
 	PublicSurfacePaths []string `json:"public_surface_paths"`
 	HasStructural      bool     `json:"has_structural"`
+	ChangedFiles       []string `json:"changed_files"` // tracked and untracked paths, for run_when.paths
+	Languages          []string `json:"languages"`     // programming languages of changed files, for run_when.languages
 }
 
 // ComputeProfile computes a diff profile from the given base ref.
@@ -30,6 +35,14 @@ type Profile struct {
 // Key fidelity point: includes untracked files in both files_changed
 // and new_files counts. Untracked files get synthetic A\t<name> entries.
 func ComputeProfile(repoRoot, base string, cfg *config.Config) (*Profile, error) {
+	return ComputeScopedProfile(repoRoot, base, cfg, nil)
+}
+
+// ComputeScopedProfile computes a diff profile counting only changes to
+// paths under the scope prefixes (see repo.InScope). Nil prefixes
+// profile the whole diff. Paths matched by .bonsaiignore or
+// cfg.Exclude are never counted.
+func ComputeScopedProfile(repoRoot, base string, cfg *config.Config, prefixes []string) (*Profile, error) {
 	p := &Profile{}
 
 	// Git commands may fail (e.g. no commits yet, detached HEAD);
@@ -39,14 +52,25 @@ func ComputeProfile(repoRoot, base string, cfg *config.Config) (*Profile, error)
 	diffNames, _ := gitutil.DiffNameOnly(repoRoot, base)
 	untracked, _ := gitutil.UntrackedFiles(repoRoot)
 
+	// An unreadable .bonsaiignore degrades to the config patterns.
+	ig, _ := repo.LoadIgnore(repoRoot, cfg.Exclude)
+	keep := repo.Selector(prefixes, ig)
+	diffOutput = SelectDiff(diffOutput, keep)
+	nameStatus = filterNameStatus(nameStatus, keep)
+	diffNames = repo.FilterPaths(diffNames, keep)
+	untracked = repo.FilterPaths(untracked, keep)
+
 	diffNames, nameStatus = mergeUntracked(diffNames, nameStatus, untracked)
 	p.FilesChanged = len(diffNames)
 
 	p.countNameStatus(nameStatus)
 	p.countDiffLines(diffOutput)
+	p.BinaryFiles = countBinaryDiffs(diffOutput) + len(BinaryFiles(repoRoot, untracked))
 	p.TopLevelDirs = collectTopDirs(diffNames)
 	p.PublicSurfacePaths = matchPublicSurface(diffNames, cfg.Routing.PublicSurfaceGlobs)
 	p.HasStructural = detectStructural(diffNames, cfg.Routing.StructuralPatterns)
+	p.ChangedFiles = diffNames
+	p.Languages = collectLanguages(diffNames)
 
 	return p, nil
 }
@@ -72,13 +96,16 @@ func mergeUntracked(diffNames, nameStatus, untracked []string) (merged, status [
 	return diffNames, nameStatus
 }
 
-// countNameStatus counts new files and renames from name-status output.
+// countNameStatus counts new, deleted, and renamed files from
+// name-status output.
 func (p *Profile) countNameStatus(nameStatus []string) {
 	for _, entry := range nameStatus {
-		if strings.HasPrefix(entry, "A") {
+		switch {
+		case strings.HasPrefix(entry, "A"):
 			p.NewFiles++
-		}
-		if strings.HasPrefix(entry, "R") {
+		case strings.HasPrefix(entry, "D"):
+			p.DeletedFiles++
+		case strings.HasPrefix(entry, "R"):
 			p.Renames++
 		}
 	}
@@ -3,6 +3,7 @@ package diff_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pithecene-io/bonsai/internal/config"
@@ -248,3 +249,135 @@ func TestComputeProfile_TopLevelDirs(t *testing.T) {
 		t.Errorf("TopLevelDirs missing \"docs\"; got %v", p.TopLevelDirs)
 	}
 }
+
+func TestComputeProfile_ChangedFilesAndLanguages(t *testing.T) {
+	dir, base := setupTestRepo(t)
+	cfg := config.Default()
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "NOTES.md"), []byte("# Notes\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	p, err := diff.ComputeProfile(dir, base, cfg)
+	if err != nil {
+		t.Fatalf("ComputeProfile: %v", err)
+	}
+
+	if len(p.ChangedFiles) != 2 {
+		t.Errorf("ChangedFiles = %v, want main.go and NOTES.md", p.ChangedFiles)
+	}
+	if len(p.Languages) != 1 || p.Languages[0] != "go" {
+		t.Errorf("Languages = %v, want [go]", p.Languages)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := map[string]string{
+		"cmd/main.go":     "go",
+		"web/App.TSX":     "typescript",
+		"scripts/ci.sh":   "shell",
+		"README.md":       "",
+		"config.yaml":     "",
+		"Makefile":        "",
+		"src/lib/util.rs": "rust",
+	}
+	for file, want := range tests {
+		if got := diff.Language(file); got != want {
+			t.Errorf("Language(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestComputeProfile_BinaryFiles(t *testing.T) {
+	dir, base := setupTestRepo(t)
+	cfg := config.Default()
+
+	// One tracked binary, one untracked binary, one untracked text file.
+	if err := os.WriteFile(filepath.Join(dir, "tracked.bin"), []byte("a\x00b"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := gitutil.Run(dir, "add", "tracked.bin"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if _, err := gitutil.Run(dir, "commit", "-m", "add binary"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "image.png"), []byte("\x89PNG\x00\x00"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("text\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	p, err := diff.ComputeProfile(dir, base, cfg)
+	if err != nil {
+		t.Fatalf("ComputeProfile: %v", err)
+	}
+
+	if p.BinaryFiles != 2 {
+		t.Errorf("BinaryFiles = %d, want 2", p.BinaryFiles)
+	}
+	if p.FilesChanged != 3 {
+		t.Errorf("FilesChanged = %d, want 3", p.FilesChanged)
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	if diff.IsBinary([]byte("package main\n")) {
+		t.Error("text reported binary")
+	}
+	if !diff.IsBinary([]byte("GIF89a\x00\x01")) {
+		t.Error("NUL byte not reported binary")
+	}
+	late := append([]byte(strings.Repeat("x", 9000)), 0)
+	if diff.IsBinary(late) {
+		t.Error("NUL past the sniff window should not count, as in git")
+	}
+}
+
+func TestComputeProfile_HonoursIgnore(t *testing.T) {
+	dir, base := setupTestRepo(t)
+	cfg := config.Default()
+	cfg.Exclude = []string{"*.snap"}
+
+	if err := os.WriteFile(filepath.Join(dir, ".bonsaiignore"), []byte("vendor/\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "vendor"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vendor", "lib.go"), []byte(strings.Repeat("x\n", 5000)), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ui.snap"), []byte("snapshot\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := gitutil.Run(dir, "add", "vendor", "ui.snap", "main.go"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if _, err := gitutil.Run(dir, "commit", "-m", "add files"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+
+	p, err := diff.ComputeProfile(dir, base, cfg)
+	if err != nil {
+		t.Fatalf("ComputeProfile: %v", err)
+	}
+
+	// Only main.go and the untracked .bonsaiignore itself remain.
+	if p.FilesChanged != 2 {
+		t.Errorf("FilesChanged = %d (%v), want 2", p.FilesChanged, p.ChangedFiles)
+	}
+	if p.LinesAdded != 1 {
+		t.Errorf("LinesAdded = %d, want 1: ignored vendor/ lines must not count", p.LinesAdded)
+	}
+	if mode := diff.DetermineMode(p, cfg, ""); mode == "HEAVY" {
+		t.Error("ignored vendored lines pushed the diff into HEAVY mode")
+	}
+}
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/repo"
+)
+
+// FilterDiff keeps the file sections of a unified diff whose old or new
+// path is in scope (see repo.InScope), so a rename across the scope
+// boundary is kept. Text before the first file section is dropped. An
+// empty prefixes returns the diff unchanged.
+func FilterDiff(unified string, prefixes []string) string {
+	if len(prefixes) == 0 {
+		return unified
+	}
+	return SelectDiff(unified, func(f string) bool { return repo.InScope(f, prefixes) })
+}
+
+// SelectDiff keeps the file sections of a unified diff for which keep
+// reports true of the old or new path. Text before the first file
+// section is dropped.
+func SelectDiff(unified string, keep func(string) bool) string {
+	if unified == "" {
+		return unified
+	}
+	var b strings.Builder
+	for _, section := range fileSections(unified) {
+		oldPath, newPath, ok := sectionPaths(section)
+		if ok && (keep(oldPath) || keep(newPath)) {
+			b.WriteString(section)
+		}
+	}
+	return b.String()
+}
+
+// fileSections splits a unified diff before each "diff --git" line.
+func fileSections(unified string) []string {
+	var sections []string
+	start := 0
+	for i := 0; i < len(unified); {
+		next := strings.IndexByte(unified[i:], '\n')
+		if strings.HasPrefix(unified[i:], "diff --git ") && i > start {
+			sections = append(sections, unified[start:i])
+			start = i
+		}
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return append(sections, unified[start:])
+}
+
+// sectionPaths parses the a/ and b/ paths from a section's
+// "diff --git a/<old> b/<new>" header.
+func sectionPaths(section string) (oldPath, newPath string, ok bool) {
+	header, _, _ := strings.Cut(section, "\n")
+	paths, found := strings.CutPrefix(header, "diff --git a/")
+	if !found {
+		return "", "", false
+	}
+	oldPath, newPath, ok = strings.Cut(paths, " b/")
+	return oldPath, newPath, ok
+}
+
+// filterNameStatus keeps name-status entries ("M\tpath",
+// "R100\told\tnew") with any path kept.
+func filterNameStatus(entries []string, keep func(string) bool) []string {
+	var kept []string
+	for _, e := range entries {
+		fields := strings.Split(e, "\t")
+		for _, path := range fields[1:] {
+			if keep(path) {
+				kept = append(kept, e)
+				break
+			}
+		}
+	}
+	return kept
+}
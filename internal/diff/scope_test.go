@@ -0,0 +1,82 @@
+package diff_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/config"
+	"github.com/pithecene-io/bonsai/internal/diff"
+	"github.com/pithecene-io/bonsai/internal/gitutil"
+)
+
+const scopeDiff = `diff --git a/internal/gate/loop.go b/internal/gate/loop.go
+--- a/internal/gate/loop.go
++++ b/internal/gate/loop.go
+@@ -1 +1 @@
+-old
++new
+diff --git a/cmd/main.go b/cmd/main.go
+--- a/cmd/main.go
++++ b/cmd/main.go
+@@ -1 +1 @@
+-old
++new
+diff --git a/internal/gate/old.go b/pkg/moved.go
+similarity index 100%
+rename from internal/gate/old.go
+rename to pkg/moved.go
+`
+
+func TestFilterDiff(t *testing.T) {
+	got := diff.FilterDiff(scopeDiff, []string{"internal/gate"})
+
+	if !strings.Contains(got, "b/internal/gate/loop.go") {
+		t.Error("in-scope file section should be kept")
+	}
+	if strings.Contains(got, "cmd/main.go") {
+		t.Error("out-of-scope file section should be dropped")
+	}
+	if !strings.Contains(got, "rename to pkg/moved.go") {
+		t.Error("a rename out of the scope should be kept")
+	}
+	if diff.FilterDiff(scopeDiff, nil) != scopeDiff {
+		t.Error("no prefixes should leave the diff unchanged")
+	}
+}
+
+func TestComputeScopedProfile(t *testing.T) {
+	dir, base := setupTestRepo(t)
+	cfg := config.Default()
+
+	for _, f := range []string{"internal/gate/loop.go", "internal/gate/new.go", "cmd/main.go"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("package x\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if _, err := gitutil.Run(dir, "add", "internal/gate/loop.go", "cmd/main.go"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if _, err := gitutil.Run(dir, "commit", "-m", "add files"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+
+	p, err := diff.ComputeScopedProfile(dir, base, cfg, []string{"internal/gate"})
+	if err != nil {
+		t.Fatalf("ComputeScopedProfile: %v", err)
+	}
+
+	if p.FilesChanged != 2 || p.NewFiles != 2 {
+		t.Errorf("FilesChanged = %d, NewFiles = %d; want 2, 2", p.FilesChanged, p.NewFiles)
+	}
+	if len(p.TopLevelDirs) != 1 || p.TopLevelDirs[0] != "internal" {
+		t.Errorf("TopLevelDirs = %v, want [internal]", p.TopLevelDirs)
+	}
+	if p.LinesAdded != 1 {
+		t.Errorf("LinesAdded = %d, want 1 (tracked in-scope diff only)", p.LinesAdded)
+	}
+}
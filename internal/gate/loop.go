@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,7 +33,11 @@ type Opts struct {
 	Config    *config.Config
 	Agent     agent.Agent
 	Resolver  *assets.Resolver
-	ExtraArgs []string // Passthrough args to claude
+	ExtraArgs []string                 // Passthrough args to claude
+	Baseline  orchestrator.Baseline    // Accepted pre-existing findings; nil disables baselining
+	FailOn    registry.FailOn          // Failure threshold override; empty uses the skills.yaml per-mode threshold
+	Events    func(orchestrator.Event) // Optional extra subscriber for governance run events (e.g. --events)
+	Out       io.Writer                // Progress and status output; nil = os.Stdout
 }
 
 // PlanInfo holds consumed plan metadata.
@@ -56,6 +61,14 @@ func New(opts Opts) *Loop {
 	return &Loop{opts: opts}
 }
 
+// out returns where the loop's progress and status lines go.
+func (l *Loop) out() io.Writer {
+	if l.opts.Out != nil {
+		return l.opts.Out
+	}
+	return os.Stdout
+}
+
 // Preflight runs the pre-loop checks:
 //   - Warn if not in a worktree
 //   - Detect merge base
@@ -80,7 +93,7 @@ func (l *Loop) Preflight() error {
 		if len(short) > 12 {
 			short = short[:12]
 		}
-		fmt.Printf("Merge base: %s\n", short)
+		fmt.Fprintf(l.out(), "Merge base: %s\n", short)
 	} else {
 		fmt.Fprintln(os.Stderr, "warning: could not detect merge base — gating may be limited")
 	}
@@ -107,7 +120,7 @@ func (l *Loop) Run(ctx context.Context) error {
 	state := iterState{}
 
 	for iteration := 1; iteration <= maxIter; iteration++ {
-		fmt.Printf("\n═══ Implementation session %d/%d ═══\n\n", iteration, maxIter)
+		fmt.Fprintf(l.out(), "\n═══ Implementation session %d/%d ═══\n\n", iteration, maxIter)
 
 		if err := l.runSession(ctx, state.findings); err != nil {
 			return err
@@ -138,10 +151,10 @@ func (l *Loop) runGateIteration(ctx context.Context, iteration, maxIter int) (*i
 	}
 
 	report := outcome.report
-	fmt.Printf("\nGovernance mode: %s\n", outcome.mode)
+	fmt.Fprintf(l.out(), "\nGovernance mode: %s\n", outcome.mode)
 
 	if !report.ShouldFail() {
-		fmt.Printf("\n✔ Governance gate passed (%d/%d skills passed)\n",
+		fmt.Fprintf(l.out(), "\n✔ Governance gate passed (%d/%d skills passed)\n",
 			report.Passed, report.Total)
 		l.saveArtifacts(report)
 		return nil, nil //nolint:nilnil // nil signals "gate passed" to caller
@@ -154,7 +167,8 @@ func (l *Loop) runGateIteration(ctx context.Context, iteration, maxIter int) (*i
 	}
 
 	report.PrintFindings(os.Stderr)
-	fmt.Printf("\nGovernance gate failed — %d blocking finding(s)\n", report.BlockingFailed)
+	fmt.Fprintf(l.out(), "\nGovernance gate failed — %d failing mandatory skill(s) (fail on: %s)\n",
+		report.BlockingFailed, report.FailOn)
 
 	if !l.promptReenter() {
 		return nil, fmt.Errorf("user declined to re-enter")
@@ -193,7 +207,7 @@ func (l *Loop) runSession(ctx context.Context, findingsContext string) error {
 	// When a plan is present, run one-shot (Execute) — the agent gets
 	// the plan as its user prompt and autonomously implements it.
 	if userPrompt := l.buildPlanPrompt(findingsContext); userPrompt != "" {
-		fmt.Println("Executing plan (one-shot mode)…")
+		fmt.Fprintln(l.out(), "Executing plan (one-shot mode)…")
 		// Execute errors are not fatal — match shell `claude ... || true`.
 		_ = l.opts.Agent.Execute(ctx, systemPrompt, userPrompt, implModel)
 		return nil
@@ -249,12 +263,12 @@ type gateOutcome struct {
 // Returns nil when gating should be skipped (no merge base or no changes).
 func (l *Loop) captureAndGate(ctx context.Context) (*gateOutcome, error) {
 	if l.mergeBase == "" {
-		fmt.Println("\nNo merge base — skipping governance gate")
+		fmt.Fprintln(l.out(), "\nNo merge base — skipping governance gate")
 		return nil, nil //nolint:nilnil // nil outcome signals "skip gating" to caller
 	}
 
 	if !l.hasChanges() {
-		fmt.Println("\nNo changes detected — skipping governance gate")
+		fmt.Fprintln(l.out(), "\nNo changes detected — skipping governance gate")
 		return nil, nil //nolint:nilnil // nil outcome signals "skip gating" to caller
 	}
 
@@ -269,7 +283,7 @@ func (l *Loop) captureAndGate(ctx context.Context) (*gateOutcome, error) {
 	}
 	mode := diff.DetermineMode(profile, l.opts.Config, planIntent)
 
-	report, err := l.runGate(ctx, mode)
+	report, err := l.runGate(ctx, mode, profile)
 	if err != nil {
 		return nil, fmt.Errorf("governance gate: %w", err)
 	}
@@ -296,7 +310,7 @@ func (l *Loop) consumePlan() {
 
 	l.planInfo = &plan
 	if plan.Intent != "" {
-		fmt.Printf("Consuming plan.json (intent: %s)\n", plan.Intent)
+		fmt.Fprintf(l.out(), "Consuming plan.json (intent: %s)\n", plan.Intent)
 	}
 
 	// Rename to .consumed to prevent re-consumption
@@ -323,7 +337,7 @@ func (l *Loop) hasChanges() bool {
 
 // runGate invokes the orchestrator with mode-based skill selection.
 // Matches ai-implement.sh: ai-check --mode $MODE --fail-fast --base $MERGE_BASE
-func (l *Loop) runGate(ctx context.Context, mode string) (*orchestrator.Report, error) {
+func (l *Loop) runGate(ctx context.Context, mode string, profile *diff.Profile) (*orchestrator.Report, error) {
 	reg, err := registry.Load(l.opts.Resolver)
 	if err != nil {
 		return nil, fmt.Errorf("load registry: %w", err)
@@ -334,11 +348,28 @@ func (l *Loop) runGate(ctx context.Context, mode string) (*orchestrator.Report,
 		return nil, err
 	}
 
-	// Use agent router for non-interactive skill runs (supports both claude and codex)
-	agentRouter := agent.NewRouter(l.opts.Config.Agents.Claude.Bin, l.opts.Config.Agents.Codex.Bin)
+	failOn := l.opts.FailOn
+	if failOn == "" {
+		if failOn, err = reg.FailOnForMode(registry.GovMode(mode)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Use agent router for non-interactive skill runs (supports both claude and codex).
+	// Reuse the caller's router when it is one, so its configured
+	// backends (e.g. providers.openai_compat) serve governance too.
+	agentRouter, ok := l.opts.Agent.(*agent.Router)
+	if !ok {
+		agentRouter = agent.NewRouter(l.opts.Config.Agents.Claude.Bin, l.opts.Config.Agents.Codex.Bin)
+	}
 	orch := orchestrator.New(agentRouter, l.opts.Resolver)
 
-	return orch.RunWithLogger(ctx, orchestrator.RunOpts{
+	bus := orchestrator.NewBus(orchestrator.EventBuffer(len(skills)))
+	bus.Handle(orchestrator.LogHandler(func(msg string) { fmt.Fprintln(l.out(), msg) }))
+	if l.opts.Events != nil {
+		bus.Handle(l.opts.Events)
+	}
+	return orch.RunWithBus(ctx, orchestrator.RunOpts{
 		Skills:              skills,
 		Source:              "mode:" + mode,
 		BaseRef:             l.mergeBase,
@@ -346,8 +377,13 @@ func (l *Loop) runGate(ctx context.Context, mode string) (*orchestrator.Report,
 		RepoRoot:            l.opts.RepoRoot,
 		Config:              l.opts.Config,
 		DefaultRequiresDiff: reg.Defaults.EffectiveRequiresDiff(),
+		DefaultTimeout:      reg.Defaults.Timeout,
 		Concurrency:         1,
-	}, nil)
+		Baseline:            l.opts.Baseline,
+		FailOn:              failOn,
+		Profile:             profile,
+		IgnoreTriggers:      registry.GovMode(mode) == registry.GovModeAudit,
+	}, bus)
 }
 
 // saveArtifacts saves the patch and report on governance pass.
@@ -366,7 +402,7 @@ func (l *Loop) saveArtifacts(report *orchestrator.Report) {
 		if err := os.WriteFile(patchPath, []byte(patchData), 0o644); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to save patch: %v\n", err)
 		} else {
-			fmt.Printf("Saved: %s\n", patchPath)
+			fmt.Fprintf(l.out(), "Saved: %s\n", patchPath)
 		}
 	}
 
@@ -377,14 +413,14 @@ func (l *Loop) saveArtifacts(report *orchestrator.Report) {
 		if err := os.WriteFile(reportPath, reportJSON, 0o644); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to save report: %v\n", err)
 		} else {
-			fmt.Printf("Saved: %s\n", reportPath)
+			fmt.Fprintf(l.out(), "Saved: %s\n", reportPath)
 		}
 	}
 }
 
 // promptReenter asks the user if they want to re-enter the session.
 func (l *Loop) promptReenter() bool {
-	fmt.Print("\nRe-enter session to fix findings? [Y/n] ")
+	fmt.Fprint(l.out(), "\nRe-enter session to fix findings? [Y/n] ")
 	reader := bufio.NewReader(os.Stdin)
 	line, err := reader.ReadString('\n')
 	if err != nil {
@@ -80,6 +80,15 @@ func UntrackedFiles(dir string) ([]string, error) {
 	return LsFiles(dir, "--others", "--exclude-standard")
 }
 
+// CheckAttr returns git check-attr output ("<path>: <attr>: <value>")
+// for the given attributes and files.
+func CheckAttr(dir string, attrs, files []string) ([]string, error) {
+	full := append([]string{"check-attr"}, attrs...)
+	full = append(full, "--")
+	full = append(full, files...)
+	return RunLines(dir, full...)
+}
+
 // IsInsideWorkTree returns true if the directory is inside a git work tree.
 func IsInsideWorkTree(dir string) bool {
 	inside, _ := CheckInsideWorkTree(dir)
@@ -250,3 +250,20 @@ func TestIsDirty(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckAttr(t *testing.T) {
+	dir := setupTestRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.dat binary\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	lines, err := gitutil.CheckAttr(dir, []string{"binary"}, []string{"a.dat", "b.go"})
+	if err != nil {
+		t.Fatalf("CheckAttr: %v", err)
+	}
+
+	want := []string{"a.dat: binary: set", "b.go: binary: unspecified"}
+	if len(lines) != 2 || lines[0] != want[0] || lines[1] != want[1] {
+		t.Errorf("CheckAttr = %q, want %q", lines, want)
+	}
+}
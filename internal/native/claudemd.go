@@ -0,0 +1,99 @@
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// codeSpan matches a backquoted span in markdown.
+var codeSpan = regexp.MustCompile("`([^`]+)`")
+
+// declaredPaths returns the backquoted paths listed in CLAUDE.md bullet
+// items under headings accepted by match, e.g.
+//
+//	## Required directories
+//	- `docs/`
+//	- `internal/`
+//
+// A section ends at the next heading of the same or higher level. Paths
+// are returned without leading "./" or trailing "/", deduplicated, in
+// document order. A missing CLAUDE.md declares nothing.
+func declaredPaths(repoRoot string, match func(heading string) bool) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, "CLAUDE.md"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	sectionLevel := 0 // heading level of the matched section; 0 = outside
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if level, title := heading(line); level > 0 {
+			sectionLevel = nextSection(sectionLevel, level, match(strings.ToLower(title)))
+			continue
+		}
+		if sectionLevel > 0 && isBullet(line) {
+			paths = appendCodePaths(paths, line)
+		}
+	}
+	return paths, sc.Err()
+}
+
+// appendCodePaths appends the new paths in line's code spans.
+func appendCodePaths(paths []string, line string) []string {
+	for _, m := range codeSpan.FindAllStringSubmatch(line, -1) {
+		if p := normalizePath(m[1]); p != "" && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// nextSection returns the matched-section level after a heading: a
+// matching heading opens a section, and a heading at the same or a
+// higher level closes the current one.
+func nextSection(current, level int, matches bool) int {
+	switch {
+	case matches:
+		return level
+	case level <= current:
+		return 0
+	}
+	return current
+}
+
+// heading returns the level and title of a markdown ATX heading, or
+// level 0 when line is not a heading.
+func heading(line string) (level int, title string) {
+	level = len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || !strings.HasPrefix(line[level:], " ") {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
+
+// isBullet reports whether line is a markdown list item.
+func isBullet(line string) bool {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+		return true
+	}
+	digits := strings.TrimLeft(line, "0123456789")
+	return len(digits) < len(line) && strings.HasPrefix(digits, ". ")
+}
+
+// normalizePath strips "./" and trailing slashes from a declared path.
+func normalizePath(p string) string {
+	p = strings.TrimPrefix(strings.TrimSpace(p), "./")
+	return strings.TrimRight(p, "/")
+}
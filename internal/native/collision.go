@@ -0,0 +1,154 @@
+package native
+
+import (
+	"context"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// nameAliases folds abbreviations and long forms of common directory
+// names onto one spelling, so util/ and utilities/ collide.
+var nameAliases = map[string]string{
+	"utility":       "util",
+	"utilities":     "util",
+	"utils":         "util",
+	"library":       "lib",
+	"libraries":     "lib",
+	"conf":          "config",
+	"cfg":           "config",
+	"configuration": "config",
+	"package":       "pkg",
+	"documentation": "doc",
+	"src":           "source",
+	"testing":       "test",
+	"impl":          "implementation",
+}
+
+// moduleNameCollision implements module-name-collision-detector:
+// sibling directories whose names differ only by case, separators,
+// plural suffix, or a common abbreviation are major; a second-level
+// directory that collides with a top-level one is a warning. Hidden
+// directories are exempt.
+type moduleNameCollision struct{}
+
+// Run compares directory names derived from the repository tree.
+func (moduleNameCollision) Run(_ context.Context, in skill.NativeInput) (*skill.Output, error) {
+	out := newOutput("module-name-collision-detector")
+	children := directoryTree(in.Files)
+
+	for _, parent := range slices.Sorted(maps.Keys(children)) {
+		out.Major = append(out.Major, siblingCollisions(parent, children[parent])...)
+	}
+
+	for _, top := range children[""] {
+		for _, nested := range crossLevelCollisions(children, top) {
+			out.Warning = append(out.Warning, skill.Finding{
+				Message: "directory " + nested + "/ has a name confusingly similar to top-level " + top + "/",
+				Path:    nested,
+				Rule:    "cross-level-collision",
+			})
+		}
+	}
+	return finish(out), nil
+}
+
+// siblingCollisions reports each pair of colliding directories under parent.
+func siblingCollisions(parent string, names []string) []skill.Finding {
+	var findings []skill.Finding
+	for i, a := range names {
+		for _, b := range names[i+1:] {
+			if namesCollide(a, b) {
+				findings = append(findings, skill.Finding{
+					Message: "sibling directories " + path.Join(parent, a) + "/ and " + path.Join(parent, b) + "/ have confusingly similar names",
+					Path:    path.Join(parent, b),
+					Rule:    "sibling-collision",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// crossLevelCollisions returns the second-level directories, outside
+// top itself, whose names collide with the top-level directory top.
+func crossLevelCollisions(children map[string][]string, top string) []string {
+	var matches []string
+	for _, parent := range children[""] {
+		if parent == top {
+			continue
+		}
+		for _, name := range children[parent] {
+			if name == top || namesCollide(name, top) {
+				matches = append(matches, parent+"/"+name)
+			}
+		}
+	}
+	return matches
+}
+
+// directoryTree maps each directory ("" for the root) to its sorted,
+// non-hidden subdirectory names, derived from the file paths.
+func directoryTree(files []string) map[string][]string {
+	seen := make(map[string]bool)
+	children := make(map[string][]string)
+	for _, f := range files {
+		parts := strings.Split(f, "/")
+		for i := range len(parts) - 1 {
+			if isHidden(parts[i]) {
+				break
+			}
+			dir := strings.Join(parts[:i+1], "/")
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			parent := strings.Join(parts[:i], "/")
+			children[parent] = append(children[parent], parts[i])
+		}
+	}
+	for _, names := range children {
+		slices.Sort(names)
+	}
+	return children
+}
+
+// namesCollide reports whether two distinct directory names normalize
+// to a common spelling.
+func namesCollide(a, b string) bool {
+	if a == b {
+		return false
+	}
+	va := nameVariants(a)
+	for _, v := range nameVariants(b) {
+		if slices.Contains(va, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameVariants returns the normalized spellings of a directory name:
+// lowercased without separators, with plural suffixes stripped and
+// common abbreviations folded.
+func nameVariants(name string) []string {
+	n := strings.ToLower(strings.NewReplacer("-", "", "_", "", ".", "").Replace(name))
+	variants := []string{n}
+	switch {
+	case strings.HasSuffix(n, "ies"):
+		variants = append(variants, n[:len(n)-3]+"y")
+	case strings.HasSuffix(n, "es"):
+		variants = append(variants, n[:len(n)-2], n[:len(n)-1])
+	case strings.HasSuffix(n, "s") && !strings.HasSuffix(n, "ss"):
+		variants = append(variants, n[:len(n)-1])
+	}
+	for _, v := range variants {
+		if alias, ok := nameAliases[v]; ok {
+			variants = append(variants, alias)
+		}
+	}
+	return slices.DeleteFunc(variants, func(v string) bool { return len(v) < 2 })
+}
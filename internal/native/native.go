@@ -0,0 +1,73 @@
+// Package native provides built-in Go implementations of deterministic
+// skills. A registry entry with engine: native is evaluated here instead
+// of by a model, so the check is free, instant, and reproducible.
+package native
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// skillVersion is the output version reported by native skills; it
+// matches the SKILL.md version each implementation mirrors.
+const skillVersion = "v1"
+
+// skills maps registry skill names to their native implementations.
+var skills = map[string]skill.NativeSkill{
+	"forbidden-top-level-detector":      forbiddenTopLevel{},
+	"required-directory-detector":       requiredDirectory{},
+	"module-name-collision-detector":    moduleNameCollision{},
+	"hardcoded-secret-pattern-detector": hardcodedSecret{},
+}
+
+// Lookup returns the native implementation of the named skill.
+func Lookup(name string) (skill.NativeSkill, bool) {
+	s, ok := skills[name]
+	return s, ok
+}
+
+// Names returns the names of all native skills, sorted.
+func Names() []string {
+	return slices.Sorted(maps.Keys(skills))
+}
+
+// newOutput returns an empty passing output for the named skill.
+func newOutput(name string) *skill.Output {
+	return &skill.Output{
+		Skill:    name,
+		Version:  skillVersion,
+		Status:   "pass",
+		Blocking: []skill.Finding{},
+		Major:    []skill.Finding{},
+		Warning:  []skill.Finding{},
+		Info:     []skill.Finding{},
+	}
+}
+
+// finish sets the status from the findings, matching the SKILL.md
+// contract: "fail" if any blocking findings exist, otherwise "pass".
+func finish(out *skill.Output) *skill.Output {
+	if len(out.Blocking) > 0 {
+		out.Status = "fail"
+	}
+	return out
+}
+
+// topLevelEntries maps each top-level entry in files to whether it is a
+// directory.
+func topLevelEntries(files []string) map[string]bool {
+	entries := make(map[string]bool)
+	for _, f := range files {
+		head, _, isDir := strings.Cut(f, "/")
+		entries[head] = entries[head] || isDir
+	}
+	return entries
+}
+
+// isHidden reports whether a path component is a dotfile or dotdir.
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
@@ -0,0 +1,166 @@
+package native
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// antiPatternDirs are build, dependency, and scratch directories that
+// should never be committed at the repository root.
+var antiPatternDirs = map[string]bool{
+	"node_modules": true,
+	"dist":         true,
+	"build":        true,
+	".cache":       true,
+	"tmp":          true,
+	"temp":         true,
+	"coverage":     true,
+	"__pycache__":  true,
+	".venv":        true,
+	"venv":         true,
+}
+
+// conventionalFiles are glob patterns for top-level files with a
+// well-known role: documentation, licensing, build and tool manifests.
+var conventionalFiles = []string{
+	"*.md", "*.txt", "*.toml", "*.yaml", "*.yml", "*.json", "*.lock",
+	"*.mod", "*.sum", "*.work", "*.cfg", "*.ini", "*.nix",
+	"LICENSE*", "LICENCE*", "NOTICE*", "AUTHORS*", "COPYING*", "CODEOWNERS", "VERSION",
+	"Makefile", "GNUmakefile", "Taskfile*", "justfile", "Justfile",
+	"Dockerfile*", "Containerfile*", "Procfile", "Gemfile*", "Rakefile",
+	"Vagrantfile", "Jenkinsfile", "setup.py", "manage.py", "main.go", "doc.go",
+}
+
+// forbiddenTopLevel implements forbidden-top-level-detector: entries
+// forbidden by CLAUDE.md are blocking, committed build/dependency
+// directories are major, and unconventional top-level files are
+// warnings. Hidden entries are only checked when CLAUDE.md names them.
+type forbiddenTopLevel struct{}
+
+// Run checks the top-level entries of the repository tree.
+func (forbiddenTopLevel) Run(_ context.Context, in skill.NativeInput) (*skill.Output, error) {
+	out := newOutput("forbidden-top-level-detector")
+	forbidden, err := declaredPaths(in.RepoRoot, isForbiddenHeading)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := topLevelEntries(in.Files)
+	for _, name := range forbidden {
+		if _, ok := entries[name]; ok {
+			out.Blocking = append(out.Blocking, skill.Finding{
+				Message: "top-level entry " + display(name, entries[name]) + " is forbidden by CLAUDE.md",
+				Path:    name,
+				Rule:    "forbidden-entry",
+			})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		isDir := entries[name]
+		switch {
+		case slices.Contains(forbidden, name):
+			// Already reported as blocking.
+		case isDir && antiPatternDirs[name]:
+			out.Major = append(out.Major, skill.Finding{
+				Message: "build or dependency directory " + display(name, true) + " is committed at the repository root",
+				Path:    name,
+				Rule:    "anti-pattern-dir",
+			})
+		case !isDir && !isHidden(name) && !isConventionalFile(name):
+			out.Warning = append(out.Warning, skill.Finding{
+				Message: "unexpected top-level file " + name + " matches no repository convention",
+				Path:    name,
+				Rule:    "unexpected-file",
+			})
+		}
+	}
+	return finish(out), nil
+}
+
+// isForbiddenHeading matches CLAUDE.md sections listing forbidden
+// top-level entries, e.g. "Forbidden top-level directories".
+func isForbiddenHeading(title string) bool {
+	return strings.Contains(title, "forbidden") &&
+		(strings.Contains(title, "top-level") || strings.Contains(title, "top level") || strings.Contains(title, "root"))
+}
+
+// isConventionalFile reports whether a top-level file name matches a
+// conventional pattern.
+func isConventionalFile(name string) bool {
+	for _, pattern := range conventionalFiles {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredDirectory implements required-directory-detector: every
+// directory CLAUDE.md declares as required must exist (blocking) and
+// should not be empty (warning).
+type requiredDirectory struct{}
+
+// Run checks each required directory against the working tree.
+func (requiredDirectory) Run(_ context.Context, in skill.NativeInput) (*skill.Output, error) {
+	out := newOutput("required-directory-detector")
+	required, err := declaredPaths(in.RepoRoot, isRequiredHeading)
+	if err != nil {
+		return nil, err
+	}
+	if len(required) == 0 {
+		out.Notes = append(out.Notes, "CLAUDE.md declares no required directories")
+		return out, nil
+	}
+
+	for _, dir := range required {
+		entries, err := os.ReadDir(filepath.Join(in.RepoRoot, filepath.FromSlash(dir)))
+		switch {
+		case err != nil:
+			out.Blocking = append(out.Blocking, skill.Finding{
+				Message: "required directory " + dir + "/ is missing",
+				Rule:    "missing-required-dir",
+			})
+		case isEmptyDir(entries):
+			out.Warning = append(out.Warning, skill.Finding{
+				Message: "required directory " + dir + "/ is empty",
+				Path:    dir,
+				Rule:    "empty-required-dir",
+			})
+		}
+	}
+	return finish(out), nil
+}
+
+// isRequiredHeading matches CLAUDE.md sections listing required
+// directories, e.g. "Required directories".
+func isRequiredHeading(title string) bool {
+	return (strings.Contains(title, "required") || strings.Contains(title, "mandatory")) &&
+		strings.Contains(title, "director")
+}
+
+// isEmptyDir reports whether a directory holds nothing but placeholder
+// files used to commit empty directories.
+func isEmptyDir(entries []os.DirEntry) bool {
+	for _, e := range entries {
+		if e.Name() != ".gitkeep" && e.Name() != ".keep" {
+			return false
+		}
+	}
+	return true
+}
+
+// display renders an entry name with a trailing slash for directories.
+func display(name string, isDir bool) string {
+	if isDir {
+		return name + "/"
+	}
+	return name
+}
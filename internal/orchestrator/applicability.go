@@ -0,0 +1,79 @@
+package orchestrator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/config"
+	"github.com/pithecene-io/bonsai/internal/diff"
+	"github.com/pithecene-io/bonsai/internal/registry"
+	"github.com/pithecene-io/bonsai/internal/repo"
+)
+
+// triggerMet maps each registry trigger to the diff profile predicate
+// that makes it applicable, given the configured diff thresholds.
+// Triggers not listed always apply.
+var triggerMet = map[string]func(*diff.Profile, config.DiffConfig) bool{
+	registry.TriggerPatch: func(p *diff.Profile, _ config.DiffConfig) bool { return p.FilesChanged > 0 },
+	registry.TriggerStructural: func(p *diff.Profile, _ config.DiffConfig) bool {
+		return p.NewFiles > 0 || p.DeletedFiles > 0 || p.Renames > 0 ||
+			len(p.TopLevelDirs) > 1 || p.HasStructural
+	},
+	registry.TriggerAPI:          func(p *diff.Profile, _ config.DiffConfig) bool { return len(p.PublicSurfacePaths) > 0 },
+	registry.TriggerArchitecture: func(p *diff.Profile, _ config.DiffConfig) bool { return len(p.Languages) > 0 },
+	registry.TriggerHeavy:        diff.IsHeavy,
+}
+
+// inapplicableReason returns why s does not apply to the diff profile
+// under the diff thresholds d, or "" when it does. A nil profile (no
+// --base) applies every skill.
+func inapplicableReason(s *registry.Skill, p *diff.Profile, d config.DiffConfig) string {
+	if p == nil {
+		return ""
+	}
+	if met, ok := triggerMet[s.Trigger]; ok && !met(p, d) {
+		return fmt.Sprintf("%s: trigger %s not met by diff", skipReasonNotApplicable, s.Trigger)
+	}
+	if len(s.RunWhen.Paths) > 0 && !anyPathMatches(s.RunWhen.Paths, p.ChangedFiles) {
+		return fmt.Sprintf("%s: no changed file matches run_when.paths", skipReasonNotApplicable)
+	}
+	if len(s.RunWhen.Languages) > 0 && !anyLanguage(s.RunWhen.Languages, p.Languages) {
+		return fmt.Sprintf("%s: no changed file in %s", skipReasonNotApplicable, strings.Join(s.RunWhen.Languages, ", "))
+	}
+	return ""
+}
+
+// anyPathMatches reports whether any changed file matches a glob.
+func anyPathMatches(globs, files []string) bool {
+	return slices.ContainsFunc(files, func(f string) bool { return repo.MatchAnyGlob(globs, f) })
+}
+
+// anyLanguage reports whether the wanted and changed language sets overlap.
+func anyLanguage(want, changed []string) bool {
+	return slices.ContainsFunc(want, func(lang string) bool {
+		return slices.Contains(changed, strings.ToLower(lang))
+	})
+}
+
+// resolveProfile returns the diff profile used for applicability, computing
+// it from BaseRef, restricted to Scope, when the caller did not supply
+// one. Triggers are ignored (nil profile) when IgnoreTriggers is set or
+// there is no diff to profile.
+func (opts *RunOpts) resolveProfile() *diff.Profile {
+	if opts.IgnoreTriggers {
+		return nil
+	}
+	if opts.Profile != nil || opts.BaseRef == "" {
+		return opts.Profile
+	}
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	p, err := diff.ComputeScopedProfile(opts.RepoRoot, opts.BaseRef, cfg, repo.ScopePrefixes(opts.Scope))
+	if err != nil {
+		return nil
+	}
+	return p
+}
@@ -0,0 +1,131 @@
+package orchestrator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+	"github.com/pithecene-io/bonsai/internal/diff"
+	"github.com/pithecene-io/bonsai/internal/registry"
+)
+
+// passMock returns a mock agent whose every evaluation passes.
+func passMock(t *testing.T) *agent.MockAgent {
+	t.Helper()
+	return &agent.MockAgent{
+		NameVal: "test",
+		EvaluateResponse: mustJSON(t, skillOutput{
+			Skill: "repo-convention-enforcer", Version: "v1", Status: "pass",
+			Blocking: []string{}, Major: []string{}, Warning: []string{}, Info: []string{},
+		}),
+	}
+}
+
+// docsOnlyProfile is the profile of a diff that edits one markdown file.
+var docsOnlyProfile = &diff.Profile{
+	FilesChanged: 1,
+	TopLevelDirs: []string{"docs"},
+	ChangedFiles: []string{"docs/guide.md"},
+}
+
+func TestRun_Applicability(t *testing.T) {
+	goProfile := &diff.Profile{
+		FilesChanged: 2,
+		NewFiles:     1,
+		TopLevelDirs: []string{"internal"},
+		ChangedFiles: []string{"internal/cli/check.go", "internal/cli/new.go"},
+		Languages:    []string{"go"},
+	}
+
+	tests := []struct {
+		name    string
+		trigger string
+		runWhen registry.RunWhen
+		profile *diff.Profile
+		wantRun bool
+	}{
+		{"always on docs", registry.TriggerAlways, registry.RunWhen{}, docsOnlyProfile, true},
+		{"empty trigger", "", registry.RunWhen{}, docsOnlyProfile, true},
+		{"patch on docs", registry.TriggerPatch, registry.RunWhen{}, docsOnlyProfile, true},
+		{"architecture on docs", registry.TriggerArchitecture, registry.RunWhen{}, docsOnlyProfile, false},
+		{"architecture on go", registry.TriggerArchitecture, registry.RunWhen{}, goProfile, true},
+		{"heavy on docs", registry.TriggerHeavy, registry.RunWhen{}, docsOnlyProfile, false},
+		{"heavy on small go edit", registry.TriggerHeavy, registry.RunWhen{}, goProfile, false},
+		{"heavy on many lines", registry.TriggerHeavy, registry.RunWhen{}, &diff.Profile{FilesChanged: 1, DiffLines: 501}, true},
+		{"heavy on many files", registry.TriggerHeavy, registry.RunWhen{}, &diff.Profile{FilesChanged: 16}, true},
+		{"heavy on structural api", registry.TriggerHeavy, registry.RunWhen{}, &diff.Profile{
+			FilesChanged: 1, HasStructural: true, PublicSurfacePaths: []string{"api/v1.proto"},
+		}, true},
+		{"structural on edit", registry.TriggerStructural, registry.RunWhen{}, docsOnlyProfile, false},
+		{"structural on new file", registry.TriggerStructural, registry.RunWhen{}, goProfile, true},
+		{"api without public surface", registry.TriggerAPI, registry.RunWhen{}, goProfile, false},
+		{"paths match", "", registry.RunWhen{Paths: []string{"internal/cli/**"}}, goProfile, true},
+		{"paths miss", "", registry.RunWhen{Paths: []string{"*.proto"}}, goProfile, false},
+		{"languages match", "", registry.RunWhen{Languages: []string{"Go"}}, goProfile, true},
+		{"languages miss", "", registry.RunWhen{Languages: []string{"python"}}, goProfile, false},
+		{"no profile", registry.TriggerHeavy, registry.RunWhen{Paths: []string{"*.proto"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := passSkill("repo-convention-enforcer", false)
+			s.Trigger = tt.trigger
+			s.RunWhen = tt.runWhen
+
+			opts := defaultOpts([]registry.Skill{s}, t.TempDir())
+			opts.Profile = tt.profile
+			report, err := newTestOrch(t, passMock(t)).Run(context.Background(), opts, nil)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			r := report.Results[0]
+			if ran := r.Status != "skipped"; ran != tt.wantRun {
+				t.Fatalf("status = %q (%s), want run = %v", r.Status, r.SkippedReason, tt.wantRun)
+			}
+			if !tt.wantRun && !strings.HasPrefix(r.SkippedReason, "not applicable") {
+				t.Errorf("SkippedReason = %q, want not applicable prefix", r.SkippedReason)
+			}
+		})
+	}
+}
+
+func TestRun_AllNotApplicable_ShouldNotFail(t *testing.T) {
+	skills := []registry.Skill{
+		passSkill("circular-dependency-detector", true),
+		passSkill("god-module-detector", false),
+	}
+	skills[0].Trigger = registry.TriggerArchitecture
+	skills[1].Trigger = registry.TriggerHeavy
+
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Profile = docsOnlyProfile
+	report, err := newTestOrch(t, passMock(t)).Run(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if report.Skipped != 2 || report.NotApplicable != 2 {
+		t.Errorf("Skipped = %d, NotApplicable = %d, want 2, 2", report.Skipped, report.NotApplicable)
+	}
+	if report.ShouldFail() {
+		t.Error("expected ShouldFail() = false when every skill is not applicable")
+	}
+}
+
+func TestRun_IgnoreTriggers(t *testing.T) {
+	s := passSkill("repo-convention-enforcer", false)
+	s.Trigger = registry.TriggerArchitecture
+
+	opts := defaultOpts([]registry.Skill{s}, t.TempDir())
+	opts.Profile = docsOnlyProfile
+	opts.IgnoreTriggers = true
+	report, err := newTestOrch(t, passMock(t)).Run(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if report.Passed != 1 || report.NotApplicable != 0 {
+		t.Errorf("Passed = %d, NotApplicable = %d, want 1, 0", report.Passed, report.NotApplicable)
+	}
+}
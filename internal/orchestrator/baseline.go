@@ -0,0 +1,61 @@
+package orchestrator
+
+import "github.com/pithecene-io/bonsai/internal/skill"
+
+// Baseline matches findings against a snapshot of accepted, pre-existing
+// findings. Baselined findings are reported but do not count toward
+// severity totals, exit codes, or BlockingFailed.
+type Baseline interface {
+	Contains(skillName, severity string, f skill.Finding) bool
+}
+
+// BaselinedFinding is a finding matched by the Baseline, tagged with the
+// severity it was reported at.
+type BaselinedFinding struct {
+	Severity string `json:"severity"`
+	skill.Finding
+}
+
+// applyBaseline moves findings matched by b out of o and returns them.
+func applyBaseline(b Baseline, skillName string, o *skill.Output) []BaselinedFinding {
+	if b == nil {
+		return nil
+	}
+	var matched []BaselinedFinding
+	removeFindings(o, func(severity string, f skill.Finding) bool {
+		if !b.Contains(skillName, severity, f) {
+			return false
+		}
+		matched = append(matched, BaselinedFinding{Severity: severity, Finding: f})
+		return true
+	})
+	return matched
+}
+
+// removeFindings drops every finding for which drop returns true. When
+// every blocking finding is dropped the output's status is downgraded to
+// "pass", preserving the invariant that status is "fail" iff blocking is
+// non-empty.
+func removeFindings(o *skill.Output, drop func(severity string, f skill.Finding) bool) {
+	hadBlocking := len(o.Blocking) > 0
+	for _, sev := range []struct {
+		label    string
+		findings *[]skill.Finding
+	}{
+		{"blocking", &o.Blocking},
+		{"major", &o.Major},
+		{"warning", &o.Warning},
+		{"info", &o.Info},
+	} {
+		var kept []skill.Finding
+		for _, f := range *sev.findings {
+			if !drop(sev.label, f) {
+				kept = append(kept, f)
+			}
+		}
+		*sev.findings = kept
+	}
+	if hadBlocking && len(o.Blocking) == 0 {
+		o.Status = "pass"
+	}
+}
@@ -0,0 +1,94 @@
+package orchestrator
+
+import "sync"
+
+// Bus fans the events of one run out to any number of subscribers, so
+// the TUI, the log sink, and machine-readable writers can all observe
+// the same run. Every subscriber sees every event, in publish order.
+//
+// Subscribers must be registered before the first Publish. A subscriber
+// that stops reading stalls the run once its buffer fills, so buffers
+// should be sized to hold a whole run when a consumer may quit early.
+type Bus struct {
+	buffer   int
+	in       chan Event
+	subs     []chan Event
+	handlers sync.WaitGroup
+	start    sync.Once
+	pumped   chan struct{}
+}
+
+// EventBuffer sizes the bus of a run over n skills so a subscriber that
+// stops reading (e.g. an interrupted TUI) never stalls it: each skill
+// emits at most queued, start/skipped, and done, plus fail-fast and
+// complete.
+func EventBuffer(n int) int {
+	return n*4 + 2
+}
+
+// NewBus creates a bus whose publish and subscriber channels each hold
+// buffer events.
+func NewBus(buffer int) *Bus {
+	return &Bus{
+		buffer: buffer,
+		in:     make(chan Event, buffer),
+		pumped: make(chan struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives every published event. It
+// is closed after the last event once the publish side is closed.
+func (b *Bus) Subscribe() <-chan Event {
+	ch := make(chan Event, b.buffer)
+	b.subs = append(b.subs, ch)
+	return ch
+}
+
+// Handle subscribes fn, calling it for each event on a dedicated
+// goroutine. Wait and Close return only after fn has seen every event.
+func (b *Bus) Handle(fn func(Event)) {
+	ch := b.Subscribe()
+	b.handlers.Add(1)
+	go func() {
+		defer b.handlers.Done()
+		for ev := range ch {
+			fn(ev)
+		}
+	}()
+}
+
+// Publish returns the channel to pass to Orchestrator.Run. The caller
+// closes it (or calls Close) when the run is over.
+func (b *Bus) Publish() chan<- Event {
+	b.start.Do(func() { go b.pump() })
+	return b.in
+}
+
+// Wait blocks until the publish channel has been closed and every
+// handler has processed the final event.
+func (b *Bus) Wait() {
+	b.start.Do(func() { go b.pump() })
+	<-b.pumped
+	b.handlers.Wait()
+}
+
+// Close closes the publish channel and waits for delivery to finish.
+func (b *Bus) Close() {
+	b.Publish()
+	close(b.in)
+	b.Wait()
+}
+
+// pump copies each published event to every subscriber, then closes
+// the subscriber channels.
+func (b *Bus) pump() {
+	defer close(b.pumped)
+	for ev := range b.in {
+		for _, ch := range b.subs {
+			ch <- ev
+		}
+	}
+	for _, ch := range b.subs {
+		close(ch)
+	}
+}
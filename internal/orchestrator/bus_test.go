@@ -0,0 +1,54 @@
+package orchestrator_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+)
+
+func TestBus_FansOutToEverySubscriber(t *testing.T) {
+	bus := orchestrator.NewBus(8)
+	sub := bus.Subscribe()
+
+	var mu sync.Mutex
+	var handled []string
+	bus.Handle(func(ev orchestrator.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, ev.SkillName)
+	})
+
+	ch := bus.Publish()
+	for _, name := range []string{"a", "b", "c"} {
+		ch <- orchestrator.Event{Kind: orchestrator.EventStart, SkillName: name}
+	}
+	bus.Close()
+
+	var received []string
+	for ev := range sub {
+		received = append(received, ev.SkillName)
+	}
+
+	want := []string{"a", "b", "c"}
+	for _, got := range [][]string{received, handled} {
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("event %d = %q, want %q", i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestBus_CloseWithoutEvents(t *testing.T) {
+	bus := orchestrator.NewBus(0)
+	sub := bus.Subscribe()
+	bus.Close()
+
+	if _, ok := <-sub; ok {
+		t.Error("expected subscriber channel to be closed")
+	}
+}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/pithecene-io/bonsai/internal/registry"
@@ -26,6 +27,25 @@ const (
 	EventComplete
 )
 
+// eventKindNames are the wire names of event kinds in the NDJSON stream.
+var eventKindNames = map[EventKind]string{
+	EventQueued:   "queued",
+	EventStart:    "start",
+	EventDone:     "done",
+	EventSkipped:  "skipped",
+	EventError:    "error",
+	EventFailFast: "fail_fast",
+	EventComplete: "complete",
+}
+
+// String returns the event kind's wire name.
+func (k EventKind) String() string {
+	if name, ok := eventKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 // Event represents a lifecycle event during orchestrator execution.
 type Event struct {
 	Kind      EventKind
@@ -39,4 +59,45 @@ type Event struct {
 	Reason    string        // skip/error/fail-fast reason
 	Elapsed   time.Duration // elapsed time (EventDone/EventError)
 	Err       error         // underlying error (EventError)
+	Time      time.Time     // when the event was emitted
+}
+
+// eventJSON is the serialized form of an Event, one per NDJSON line.
+type eventJSON struct {
+	Event     string  `json:"event"`
+	Time      string  `json:"time,omitempty"`
+	Index     int     `json:"index"`
+	Total     int     `json:"total"`
+	Skill     string  `json:"skill,omitempty"`
+	Cost      string  `json:"cost,omitempty"`
+	Mandatory bool    `json:"mandatory,omitempty"`
+	ElapsedMS float64 `json:"elapsed_ms,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	Result    *Result `json:"result,omitempty"`
+	Report    *Report `json:"report,omitempty"`
+}
+
+// MarshalJSON encodes the event with its kind by name, elapsed time in
+// milliseconds, and the error as a string.
+func (e Event) MarshalJSON() ([]byte, error) {
+	out := eventJSON{
+		Event:     e.Kind.String(),
+		Index:     e.Index,
+		Total:     e.Total,
+		Skill:     e.SkillName,
+		Cost:      string(e.Cost),
+		Mandatory: e.Mandatory,
+		ElapsedMS: float64(e.Elapsed.Milliseconds()),
+		Reason:    e.Reason,
+		Result:    e.Result,
+		Report:    e.Report,
+	}
+	if !e.Time.IsZero() {
+		out.Time = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if e.Err != nil {
+		out.Error = e.Err.Error()
+	}
+	return json.Marshal(out)
 }
@@ -0,0 +1,32 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// NDJSONWriter serializes events as newline-delimited JSON, one object
+// per event, for dashboards and editor integrations. Use Handle as a
+// Bus handler. After the first write error, later events are dropped
+// and the error is reported by Err.
+type NDJSONWriter struct {
+	enc *json.Encoder
+	err error
+}
+
+// NewNDJSONWriter creates a writer that encodes events to w.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{enc: json.NewEncoder(w)}
+}
+
+// Handle writes one event. It is not safe for concurrent use; a Bus
+// delivers each handler's events sequentially.
+func (w *NDJSONWriter) Handle(ev Event) {
+	if w.err != nil {
+		return
+	}
+	w.err = w.enc.Encode(ev)
+}
+
+// Err returns the first write error, if any.
+func (w *NDJSONWriter) Err() error { return w.err }
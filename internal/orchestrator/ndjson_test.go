@@ -0,0 +1,78 @@
+package orchestrator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+)
+
+func TestNDJSONWriter_OneObjectPerEvent(t *testing.T) {
+	var buf bytes.Buffer
+	w := orchestrator.NewNDJSONWriter(&buf)
+
+	w.Handle(orchestrator.Event{Kind: orchestrator.EventQueued, SkillName: "lint", Cost: "cheap", Index: 1, Total: 2})
+	w.Handle(orchestrator.Event{
+		Kind:      orchestrator.EventDone,
+		SkillName: "lint",
+		Elapsed:   1500 * time.Millisecond,
+		Result:    &orchestrator.Result{Name: "lint", Status: "fail", Blocking: 1},
+	})
+	w.Handle(orchestrator.Event{Kind: orchestrator.EventError, SkillName: "arch", Err: errors.New("boom")})
+	if err := w.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var queued map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &queued); err != nil {
+		t.Fatalf("line 1 is not JSON: %v", err)
+	}
+	if queued["event"] != "queued" || queued["skill"] != "lint" || queued["cost"] != "cheap" {
+		t.Errorf("unexpected queued event: %v", queued)
+	}
+
+	var done struct {
+		Event     string               `json:"event"`
+		ElapsedMS float64              `json:"elapsed_ms"`
+		Result    *orchestrator.Result `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &done); err != nil {
+		t.Fatalf("line 2 is not JSON: %v", err)
+	}
+	if done.Event != "done" || done.ElapsedMS != 1500 {
+		t.Errorf("unexpected done event: %+v", done)
+	}
+	if done.Result == nil || done.Result.Status != "fail" || done.Result.Blocking != 1 {
+		t.Errorf("expected embedded result, got %+v", done.Result)
+	}
+
+	if !strings.Contains(lines[2], `"event":"error"`) || !strings.Contains(lines[2], `"error":"boom"`) {
+		t.Errorf("unexpected error event: %s", lines[2])
+	}
+}
+
+func TestEventKind_String(t *testing.T) {
+	tests := map[orchestrator.EventKind]string{
+		orchestrator.EventQueued:   "queued",
+		orchestrator.EventStart:    "start",
+		orchestrator.EventDone:     "done",
+		orchestrator.EventSkipped:  "skipped",
+		orchestrator.EventError:    "error",
+		orchestrator.EventFailFast: "fail_fast",
+		orchestrator.EventComplete: "complete",
+	}
+	for kind, want := range tests {
+		if got := kind.String(); got != want {
+			t.Errorf("EventKind(%d).String() = %q, want %q", kind, got, want)
+		}
+	}
+}
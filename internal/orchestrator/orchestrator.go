@@ -5,6 +5,7 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -13,11 +14,15 @@ import (
 
 	"github.com/pithecene-io/bonsai/internal/agent"
 	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/cache"
 	"github.com/pithecene-io/bonsai/internal/config"
+	"github.com/pithecene-io/bonsai/internal/diff"
+	"github.com/pithecene-io/bonsai/internal/native"
 	"github.com/pithecene-io/bonsai/internal/prompt"
 	"github.com/pithecene-io/bonsai/internal/registry"
 	"github.com/pithecene-io/bonsai/internal/repo"
 	"github.com/pithecene-io/bonsai/internal/skill"
+	"github.com/pithecene-io/bonsai/internal/suppress"
 )
 
 // RunOpts configures an orchestrator run.
@@ -25,31 +30,61 @@ type RunOpts struct {
 	Skills              []registry.Skill // Ordered list of skills to run
 	Source              string           // "mode:NORMAL" or "bundle:default"
 	BaseRef             string           // Git ref for diff context
-	Scope               string           // Comma-separated path prefixes
+	Scope               string           // Comma-separated path prefixes; filters the tree, diff payload, and diff profile
 	FailFast            bool             // Stop on first mandatory failure
 	RepoRoot            string           // Repository root
 	Config              *config.Config
-	DefaultRequiresDiff bool   // Registry defaults.requires_diff value
-	Concurrency         int    // Max parallel skills; <= 0 means unlimited (sized to skill count)
-	ModelOverride       string // When non-empty, overrides config-based model routing for all skills
+	DefaultRequiresDiff bool            // Registry defaults.requires_diff value
+	Concurrency         int             // Max parallel skills; <= 0 means unlimited (sized to skill count)
+	ModelOverride       string          // When non-empty, overrides config-based model routing for all skills
+	Cache               *cache.Store    // Result cache for skill evaluations; nil disables caching
+	Baseline            Baseline        // Accepted pre-existing findings; nil disables baselining
+	FailOn              registry.FailOn // Lowest severity that fails a skill; empty means blocking
+	Timeout             time.Duration   // Deadline for the whole run; 0 = unbounded
+	DefaultTimeout      time.Duration   // Per-skill timeout for skills without their own (registry defaults.timeout); 0 = unbounded
+	MaxCost             float64         // USD spend after which no new skills are dispatched; 0 = unlimited. Without Concurrency, skills run serially
+	Profile             *diff.Profile   // Diff profile for trigger/run_when applicability; nil computes it from BaseRef
+	IgnoreTriggers      bool            // Run every skill regardless of trigger and run_when filters (e.g. AUDIT)
+	Strategy            Strategy        // Dispatch strategy; empty means StrategyParallel
 }
 
 // Result holds the outcome of a single skill invocation.
+//
+// The *Details fields hold one rendered display line per finding
+// ("path:line: message" when a location is known). The *Findings fields
+// carry the same findings in structured form for consumers that need
+// locations or rule ids (SARIF, IDE integrations).
 type Result struct {
-	Name            string   `json:"name"`
-	Status          string   `json:"status"`
-	SkippedReason   string   `json:"skipped_reason,omitempty"`
-	Blocking        int      `json:"blocking"`
-	Major           int      `json:"major"`
-	Warning         int      `json:"warning"`
-	ExitCode        int      `json:"exit_code"`
-	Mandatory       bool     `json:"mandatory"`
-	Elapsed         float64  `json:"elapsed_ms"`
-	ErrorDetail     string   `json:"error_detail,omitempty"`
-	BlockingDetails []string `json:"blocking_details,omitempty"`
-	MajorDetails    []string `json:"major_details,omitempty"`
-	WarningDetails  []string `json:"warning_details,omitempty"`
-	InfoDetails     []string `json:"info_details,omitempty"`
+	Name             string          `json:"name"`
+	Status           string          `json:"status"`
+	SkippedReason    string          `json:"skipped_reason,omitempty"`
+	Blocking         int             `json:"blocking"`
+	Major            int             `json:"major"`
+	Warning          int             `json:"warning"`
+	ExitCode         int             `json:"exit_code"`
+	Mandatory        bool            `json:"mandatory"`
+	Elapsed          float64         `json:"elapsed_ms"`
+	Cached           bool            `json:"cached,omitempty"`
+	Repairs          int             `json:"repairs,omitempty"`
+	Elided           []string        `json:"elided,omitempty"`
+	Model            string          `json:"model,omitempty"`
+	Backend          string          `json:"backend,omitempty"`
+	Fallbacks        []string        `json:"fallbacks,omitempty"`
+	InputTokens      int64           `json:"input_tokens,omitempty"`
+	OutputTokens     int64           `json:"output_tokens,omitempty"`
+	CostUSD          float64         `json:"cost_usd,omitempty"`
+	ErrorDetail      string          `json:"error_detail,omitempty"`
+	BlockingDetails  []string        `json:"blocking_details,omitempty"`
+	MajorDetails     []string        `json:"major_details,omitempty"`
+	WarningDetails   []string        `json:"warning_details,omitempty"`
+	InfoDetails      []string        `json:"info_details,omitempty"`
+	BlockingFindings []skill.Finding `json:"blocking_findings,omitempty"`
+	MajorFindings    []skill.Finding `json:"major_findings,omitempty"`
+	WarningFindings  []skill.Finding `json:"warning_findings,omitempty"`
+	InfoFindings     []skill.Finding `json:"info_findings,omitempty"`
+
+	BaselinedFindings  []BaselinedFinding  `json:"baselined_findings,omitempty"`
+	SuppressedFindings []SuppressedFinding `json:"suppressed_findings,omitempty"`
 }
 
 // severityPairs maps severity labels to detail slices for table-driven iteration.
@@ -62,6 +97,37 @@ var severityPairs = []struct {
 	{"warning", func(r *Result) []string { return r.WarningDetails }},
 }
 
+// Severities lists every finding severity, most severe first.
+var Severities = []string{"blocking", "major", "warning", "info"}
+
+// severityFields maps a severity label to its structured findings and
+// rendered detail lines.
+var severityFields = map[string]func(*Result) ([]skill.Finding, []string){
+	"blocking": func(r *Result) ([]skill.Finding, []string) { return r.BlockingFindings, r.BlockingDetails },
+	"major":    func(r *Result) ([]skill.Finding, []string) { return r.MajorFindings, r.MajorDetails },
+	"warning":  func(r *Result) ([]skill.Finding, []string) { return r.WarningFindings, r.WarningDetails },
+	"info":     func(r *Result) ([]skill.Finding, []string) { return r.InfoFindings, r.InfoDetails },
+}
+
+// FindingsFor returns the structured findings for a severity. Results
+// that only carry detail lines (e.g. decoded from reports written before
+// structured findings existed) yield message-only findings.
+func (r *Result) FindingsFor(severity string) []skill.Finding {
+	fields, ok := severityFields[severity]
+	if !ok {
+		return nil
+	}
+	findings, details := fields(r)
+	if len(findings) > 0 || len(details) == 0 {
+		return findings
+	}
+	out := make([]skill.Finding, len(details))
+	for i, d := range details {
+		out[i] = skill.Finding{Message: d}
+	}
+	return out
+}
+
 // Failed returns true when this result represents a non-passing skill.
 func (r *Result) Failed() bool { return r.ExitCode != 0 }
 
@@ -83,15 +149,30 @@ func (r *Result) SummaryLine() string {
 
 // Report holds the aggregate orchestrator output.
 type Report struct {
-	Source         string   `json:"source"`
-	Timestamp      string   `json:"timestamp"`
-	Total          int      `json:"total"`
-	Passed         int      `json:"passed"`
-	Failed         int      `json:"failed"`
-	Skipped        int      `json:"skipped"`
-	BlockingFailed int      `json:"blocking_failed"`
-	SkipWarning    string   `json:"skip_warning,omitempty"`
-	Results        []Result `json:"results"`
+	Source         string `json:"source"`
+	Timestamp      string `json:"timestamp"`
+	Total          int    `json:"total"`
+	Passed         int    `json:"passed"`
+	Failed         int    `json:"failed"`
+	Skipped        int    `json:"skipped"`
+	BlockingFailed int    `json:"blocking_failed"`
+	Baselined      int    `json:"baselined,omitempty"`
+	BaselineUsed   bool   `json:"baseline_used,omitempty"`
+	Suppressed     int    `json:"suppressed,omitempty"`
+	TimedOut       int    `json:"timed_out,omitempty"`
+	FailOn         string `json:"fail_on,omitempty"`
+	SkipWarning    string `json:"skip_warning,omitempty"`
+
+	InputTokens   int64              `json:"input_tokens,omitempty"`
+	OutputTokens  int64              `json:"output_tokens,omitempty"`
+	CostUSD       float64            `json:"cost_usd,omitempty"`
+	CostByModel   map[string]float64 `json:"cost_by_model,omitempty"`
+	MaxCost       float64            `json:"max_cost,omitempty"`
+	BudgetSkipped int                `json:"budget_skipped,omitempty"`
+	NotApplicable int                `json:"not_applicable,omitempty"`
+	TierSkipped   int                `json:"tier_skipped,omitempty"`
+
+	Results []Result `json:"results"`
 }
 
 // FailedResults returns pointers to all results with non-zero exit codes.
@@ -158,17 +239,41 @@ type indexedSkill struct {
 type runScope struct {
 	opts        RunOpts
 	runner      *skill.Runner
+	suppress    *suppress.Index
 	resolver    *assets.Resolver
+	files       []string      // repo tree entries, handed to native skills
+	profile     *diff.Profile // nil disables trigger/run_when filtering
 	repoTree    string
 	diffPayload string
 	events      chan<- Event
 	results     []Result
 	total       int
+	spend       spendTracker
+}
+
+// spendTracker totals the priced cost of completed skills so dispatch
+// can stop once the run's budget is exhausted.
+type spendTracker struct {
+	mu    sync.Mutex
+	spent float64
+}
+
+func (t *spendTracker) add(cost float64) {
+	t.mu.Lock()
+	t.spent += cost
+	t.mu.Unlock()
+}
+
+func (t *spendTracker) total() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.spent
 }
 
-// emit sends an event if the channel is non-nil.
+// emit timestamps and sends an event if the channel is non-nil.
 func (rs *runScope) emit(ev Event) {
 	if rs.events != nil {
+		ev.Time = time.Now()
 		rs.events <- ev
 	}
 }
@@ -177,22 +282,35 @@ func (rs *runScope) emit(ev Event) {
 // events may be nil; when non-nil, lifecycle events are sent for each skill.
 // The caller must not close the events channel; Run does not close it either.
 func (o *Orchestrator) Run(ctx context.Context, opts RunOpts, events chan<- Event) (*Report, error) {
-	repoTree, err := repo.TreeWithScope(opts.RepoRoot, opts.Scope)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	repoTree, err := repo.TreeWithScope(opts.RepoRoot, opts.Scope, opts.exclude())
 	if err != nil {
 		return nil, fmt.Errorf("repo tree: %w", err)
 	}
 
 	var diffPayload string
 	if opts.BaseRef != "" {
-		diffPayload, _ = skill.BuildDiffPayload(opts.RepoRoot, opts.BaseRef)
+		diffPayload, _ = skill.BuildScopedDiffPayload(opts.RepoRoot, opts.BaseRef, repo.ScopePrefixes(opts.Scope), opts.exclude())
 	}
 
 	rs := &runScope{
-		opts:        opts,
-		runner:      skill.NewRunner(o.agent, prompt.NewBuilder(o.resolver, opts.RepoRoot)),
+		opts: opts,
+		runner: skill.NewRunner(o.agent, prompt.NewBuilder(o.resolver, opts.RepoRoot),
+			skill.WithCache(opts.Cache),
+			skill.WithRepairRetries(opts.repairRetries()),
+			skill.WithWithheldEnv(opts.credentialEnv()...),
+		),
+		suppress:    suppress.NewIndex(opts.RepoRoot),
 		resolver:    o.resolver,
+		files:       repoTree,
 		repoTree:    strings.Join(repoTree, "\n"),
 		diffPayload: diffPayload,
+		profile:     opts.resolveProfile(),
 		events:      events,
 		results:     make([]Result, len(opts.Skills)),
 		total:       len(opts.Skills),
@@ -211,45 +329,69 @@ func (o *Orchestrator) Run(ctx context.Context, opts RunOpts, events chan<- Even
 // It manages the event channel lifecycle internally, eliminating the
 // create-close-wait boilerplate that callers of Run would otherwise repeat.
 func (o *Orchestrator) RunWithLogger(ctx context.Context, opts RunOpts, logger func(string)) (*Report, error) {
-	if logger == nil {
-		logger = func(msg string) { fmt.Println(msg) }
-	}
-	sink, done := LoggerSink(logger)
-	report, err := o.Run(ctx, opts, sink)
-	close(sink)
-	<-done
+	bus := NewBus(sinkBuffer)
+	bus.Handle(LogHandler(logger))
+	return o.RunWithBus(ctx, opts, bus)
+}
+
+// RunWithBus executes the skill set, publishing its events to bus, and
+// closes the bus once every subscriber has seen the final event.
+// Subscribers must be registered before the call.
+func (o *Orchestrator) RunWithBus(ctx context.Context, opts RunOpts, bus *Bus) (*Report, error) {
+	report, err := o.Run(ctx, opts, bus.Publish())
+	bus.Close()
 	return report, err
 }
 
+// Skip reasons recorded in Result.SkippedReason.
+const (
+	skipReasonNoBase        = "requires_diff without --base"
+	skipReasonBudget        = "budget exhausted"
+	skipReasonNotApplicable = "not applicable"
+	skipReasonEarlierTier   = "earlier tier failed"
+)
+
 // partition separates skippable skills from runnable ones,
 // emitting skip/queue events and populating pre-filled results.
 func (rs *runScope) partition() []indexedSkill {
 	var runnable []indexedSkill
 	for i := range rs.opts.Skills {
 		s := &rs.opts.Skills[i]
+		is := indexedSkill{index: i, skill: *s}
 		if s.EffectiveRequiresDiff(rs.opts.DefaultRequiresDiff) && rs.opts.BaseRef == "" {
-			rs.results[i] = Result{
-				Name:          s.Name,
-				Status:        "skipped",
-				SkippedReason: "requires_diff without --base",
-				Mandatory:     s.Mandatory,
-			}
-			rs.emit(Event{
-				Kind: EventSkipped, Index: i, Total: rs.total,
-				SkillName: s.Name, Cost: s.Cost, Mandatory: s.Mandatory,
-				Reason: "requires --base for diff context",
-			})
-		} else {
-			runnable = append(runnable, indexedSkill{index: i, skill: *s})
-			rs.emit(Event{
-				Kind: EventQueued, Index: i, Total: rs.total,
-				SkillName: s.Name, Cost: s.Cost, Mandatory: s.Mandatory,
-			})
+			rs.recordSkip(is, skipReasonNoBase, "requires --base for diff context")
+			continue
 		}
+		if reason := inapplicableReason(s, rs.profile, rs.opts.diffThresholds()); reason != "" {
+			rs.recordSkip(is, reason, reason)
+			continue
+		}
+		runnable = append(runnable, is)
+		rs.emit(Event{
+			Kind: EventQueued, Index: i, Total: rs.total,
+			SkillName: s.Name, Cost: s.Cost, Mandatory: s.Mandatory,
+		})
 	}
 	return runnable
 }
 
+// recordSkip records a skill that will not be evaluated, with the reason
+// stored in its result and the (possibly friendlier) one sent in its event.
+func (rs *runScope) recordSkip(is indexedSkill, reason, eventReason string) {
+	s := is.skill
+	rs.results[is.index] = Result{
+		Name:          s.Name,
+		Status:        "skipped",
+		SkippedReason: reason,
+		Mandatory:     s.Mandatory,
+	}
+	rs.emit(Event{
+		Kind: EventSkipped, Index: is.index, Total: rs.total,
+		SkillName: s.Name, Cost: s.Cost, Mandatory: s.Mandatory,
+		Reason: eventReason,
+	})
+}
+
 // workerState holds shared mutable state for worker goroutines.
 type workerState struct {
 	mu        sync.Mutex
@@ -278,15 +420,18 @@ func (ws *workerState) triggerFailFast(rs *runScope, idx int, s registry.Skill)
 	})
 }
 
-// dispatch launches concurrent skill workers with semaphore and fail-fast.
+// dispatch runs the runnable skills according to the run's strategy.
 func (rs *runScope) dispatch(ctx context.Context, runnable []indexedSkill) {
-	concurrency := rs.opts.Concurrency
-	if concurrency <= 0 {
-		concurrency = len(runnable)
-	}
-	if concurrency == 0 {
-		concurrency = 1
+	if rs.opts.Strategy == StrategyStaged {
+		rs.dispatchStaged(ctx, runnable)
+		return
 	}
+	rs.dispatchWave(ctx, runnable)
+}
+
+// dispatchWave launches concurrent skill workers with semaphore and fail-fast.
+func (rs *runScope) dispatchWave(ctx context.Context, runnable []indexedSkill) {
+	concurrency := rs.waveConcurrency(len(runnable))
 
 	runCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -303,6 +448,14 @@ func (rs *runScope) dispatch(ctx context.Context, runnable []indexedSkill) {
 		select {
 		case sem <- struct{}{}:
 		case <-runCtx.Done():
+			rs.recordUnstarted(runCtx, runnable[idx])
+			continue
+		}
+		// Checked after acquiring a slot so the cost of every skill
+		// that held it has been counted.
+		if rs.overBudget() {
+			<-sem
+			rs.recordBudgetSkip(runnable[idx])
 			continue
 		}
 
@@ -313,6 +466,19 @@ func (rs *runScope) dispatch(ctx context.Context, runnable []indexedSkill) {
 	wg.Wait()
 }
 
+// waveConcurrency returns how many of n skills may run at once. An
+// unset concurrency means all of them — except under a budget, where
+// skills run one at a time so each one's cost is counted before the
+// next is dispatched; otherwise the whole wave would start before any
+// spend is known.
+func (rs *runScope) waveConcurrency(n int) int {
+	concurrency := rs.opts.Concurrency
+	if concurrency <= 0 && rs.opts.MaxCost <= 0 {
+		concurrency = n
+	}
+	return max(concurrency, 1)
+}
+
 func (rs *runScope) runWorker(
 	ctx context.Context,
 	is indexedSkill,
@@ -324,6 +490,7 @@ func (rs *runScope) runWorker(
 	defer func() { <-sem }()
 
 	if ctx.Err() != nil {
+		rs.recordUnstarted(ctx, is)
 		return
 	}
 
@@ -336,6 +503,7 @@ func (rs *runScope) runWorker(
 
 	result := rs.runSkill(ctx, s)
 	rs.results[idx] = result
+	rs.spend.add(result.CostUSD)
 
 	rs.emit(Event{
 		Kind: EventDone, Index: idx, Total: rs.total,
@@ -349,86 +517,306 @@ func (rs *runScope) runWorker(
 	}
 }
 
+// recordUnstarted records a timeout result for a skill that never
+// started because the run deadline passed. Skills cancelled by fail-fast
+// are left out of the report.
+func (rs *runScope) recordUnstarted(ctx context.Context, is indexedSkill) {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return
+	}
+	s := is.skill
+	rs.results[is.index] = timeoutResult(s, time.Now(), "run deadline exceeded before skill started")
+	rs.emit(Event{
+		Kind: EventDone, Index: is.index, Total: rs.total,
+		SkillName: s.Name, Cost: s.Cost, Mandatory: s.Mandatory,
+		Result: &rs.results[is.index],
+	})
+}
+
+// overBudget reports whether the run's spend has reached MaxCost.
+func (rs *runScope) overBudget() bool {
+	return rs.opts.MaxCost > 0 && rs.spend.total() >= rs.opts.MaxCost
+}
+
+// recordBudgetSkip records a skill that was not dispatched because the
+// run's budget was exhausted.
+func (rs *runScope) recordBudgetSkip(is indexedSkill) {
+	reason := fmt.Sprintf("%s ($%.2f of $%.2f spent)", skipReasonBudget, rs.spend.total(), rs.opts.MaxCost)
+	rs.recordSkip(is, reason, reason)
+}
+
 // aggregate tallies results into a Report in original skill order.
 func (rs *runScope) aggregate() *Report {
 	report := &Report{
 		Source:    rs.opts.Source,
 		Timestamp: time.Now().Format("20060102-150405"),
+		FailOn:    string(rs.opts.effectiveFailOn()),
+		MaxCost:   rs.opts.MaxCost,
+
+		BaselineUsed: rs.opts.Baseline != nil,
 	}
 	for i := range rs.opts.Skills {
 		r := rs.results[i]
 		if r.Name == "" {
 			continue
 		}
-		report.Total++
-		switch {
-		case r.Status == "skipped":
-			report.Skipped++
-		case r.Status == "error" || r.Failed():
-			report.Failed++
-			if r.Mandatory && r.Status != "skipped" {
-				report.BlockingFailed++
-			}
-		default:
-			report.Passed++
-		}
+		report.tally(r)
 		report.Results = append(report.Results, r)
 	}
 
-	if report.Skipped > 0 && report.Total > 0 && report.Skipped > report.Total/2 {
+	if noBase := report.countSkipped(skipReasonNoBase); noBase > 0 && noBase > report.Total/2 {
 		report.SkipWarning = fmt.Sprintf(
 			"%d/%d checks skipped (requires_diff without --base) — report is structurally incomplete",
-			report.Skipped, report.Total,
+			noBase, report.Total,
 		)
 	}
+	report.BudgetSkipped = report.countSkipped(skipReasonBudget)
+	report.NotApplicable = report.countSkipped(skipReasonNotApplicable)
+	report.TierSkipped = report.countSkipped(skipReasonEarlierTier)
 
 	return report
 }
 
-// runSkill executes one skill and returns its Result.
+// tally counts one result into the report totals.
+func (r *Report) tally(res Result) {
+	r.Total++
+	r.Baselined += len(res.BaselinedFindings)
+	r.Suppressed += len(res.SuppressedFindings)
+	if res.Status == "timeout" {
+		r.TimedOut++
+	}
+	r.addUsage(res)
+	switch {
+	case res.Status == "skipped":
+		r.Skipped++
+	case res.Status == "error" || res.Failed():
+		r.Failed++
+		if res.Mandatory && res.Status != "skipped" {
+			r.BlockingFailed++
+		}
+	default:
+		r.Passed++
+	}
+}
+
+// addUsage adds a result's token usage and cost to the report totals.
+func (r *Report) addUsage(res Result) {
+	r.InputTokens += res.InputTokens
+	r.OutputTokens += res.OutputTokens
+	if res.CostUSD == 0 {
+		return
+	}
+	r.CostUSD += res.CostUSD
+	if r.CostByModel == nil {
+		r.CostByModel = make(map[string]float64)
+	}
+	r.CostByModel[res.Model] += res.CostUSD
+}
+
+// countSkipped counts skipped results whose reason starts with reason.
+func (r *Report) countSkipped(reason string) int {
+	n := 0
+	for i := range r.Results {
+		if r.Results[i].Status == "skipped" && strings.HasPrefix(r.Results[i].SkippedReason, reason) {
+			n++
+		}
+	}
+	return n
+}
+
+// runSkill executes one skill and returns its Result, annotated with
+// the tokens its evaluation consumed (including repair re-prompts) and
+// the router backends that served or failed it.
 // It does not mutate any shared state and is safe for concurrent use.
 func (rs *runScope) runSkill(ctx context.Context, s registry.Skill) Result {
+	model := rs.resolveModel(s)
+	traceCtx, trace := agent.WithBackendTrace(ctx)
+	meterCtx, meter := agent.WithUsageMeter(traceCtx)
+	result := rs.evaluateSkill(meterCtx, s, model)
+	rs.priceUsage(&result, model, meter.Total())
+	result.Backend, result.Fallbacks = trace.Backend(), trace.Fallbacks()
+	return result
+}
+
+// priceUsage records usage on r and prices it from the configured rate
+// table, matching the model name first and then its family. Unpriced
+// models report tokens only.
+func (rs *runScope) priceUsage(r *Result, model agent.Model, u agent.Usage) {
+	if u == (agent.Usage{}) {
+		return
+	}
+	r.Model = string(model)
+	r.InputTokens, r.OutputTokens = u.InputTokens, u.OutputTokens
+	if rs.opts.Config == nil {
+		return
+	}
+	if rate, ok := rs.opts.Config.Models.RateFor(string(model), model.Tier()); ok {
+		r.CostUSD = rate.Cost(u.InputTokens, u.OutputTokens)
+	}
+}
+
+// evaluateSkill loads and runs one skill against the run's context.
+func (rs *runScope) evaluateSkill(ctx context.Context, s registry.Skill, model agent.Model) Result {
 	start := time.Now()
 
+	timeout := s.EffectiveTimeout(rs.opts.DefaultTimeout)
+	skillCtx, cancel := withOptionalTimeout(ctx, timeout)
+	defer cancel()
+
+	output, err := rs.execute(skillCtx, s, model)
+	if err != nil {
+		if errors.Is(skillCtx.Err(), context.DeadlineExceeded) {
+			return timeoutResult(s, start, timeoutReason(ctx, timeout))
+		}
+		return errorResult(s, start, err)
+	}
+
+	suppressed := applySuppressions(rs.suppress, s.Name, output)
+	baselined := applyBaseline(rs.opts.Baseline, s.Name, output)
+
+	exitCode := 0
+	if output.ShouldFailAt(string(rs.opts.effectiveFailOn())) {
+		exitCode = 1
+		output.Status = "fail"
+	}
+
+	return Result{
+		Name:             s.Name,
+		Status:           output.Status,
+		Blocking:         len(output.Blocking),
+		Major:            len(output.Major),
+		Warning:          len(output.Warning),
+		ExitCode:         exitCode,
+		Mandatory:        s.Mandatory,
+		Elapsed:          float64(time.Since(start).Milliseconds()),
+		Cached:           output.Cached,
+		Repairs:          output.Repairs,
+		Elided:           output.Elided,
+		BlockingDetails:  skill.Lines(output.Blocking),
+		MajorDetails:     skill.Lines(output.Major),
+		WarningDetails:   skill.Lines(output.Warning),
+		InfoDetails:      skill.Lines(output.Info),
+		BlockingFindings: output.Blocking,
+		MajorFindings:    output.Major,
+		WarningFindings:  output.Warning,
+		InfoFindings:     output.Info,
+
+		BaselinedFindings:  baselined,
+		SuppressedFindings: suppressed,
+	}
+}
+
+// execute produces a skill's validated output, dispatching to the
+// built-in implementation for engine: native skills and to the skill
+// runner (model or exec entrypoint) otherwise.
+func (rs *runScope) execute(ctx context.Context, s registry.Skill, model agent.Model) (*skill.Output, error) {
+	if s.IsNative() {
+		impl, ok := native.Lookup(s.Name)
+		if !ok {
+			return nil, fmt.Errorf("skill %q declares engine: native but has no native implementation", s.Name)
+		}
+		return impl.Run(ctx, skill.NativeInput{
+			RepoRoot:    rs.opts.RepoRoot,
+			Files:       rs.files,
+			DiffPayload: rs.diffPayload,
+		})
+	}
+
 	version := s.Version
 	if version == "" {
 		version = "v1"
 	}
 	def, err := skill.Load(rs.resolver, s.Name, version)
 	if err != nil {
-		return errorResult(s, start, err)
+		return nil, err
 	}
-
-	model := rs.resolveModel(s)
-	output, err := rs.runner.Run(ctx, def, skill.RunOpts{
-		RepoTree:    rs.repoTree,
-		DiffPayload: rs.diffPayload,
-		BaseRef:     rs.opts.BaseRef,
-		Model:       model,
+	return rs.runner.Run(ctx, def, skill.RunOpts{
+		RepoTree:      rs.repoTree,
+		DiffPayload:   rs.diffPayload,
+		BaseRef:       rs.opts.BaseRef,
+		Model:         model,
+		DiffBudget:    rs.diffBudget(model),
+		ContextWindow: rs.contextWindow(model),
+		ModelIdentity: rs.modelIdentity(model),
+		RepoRoot:      rs.opts.RepoRoot,
+		Scope:         repo.ScopePrefixes(rs.opts.Scope),
+		Config:        s.Config,
 	})
-	if err != nil {
-		return errorResult(s, start, err)
+}
+
+// modelKeys returns the names model's settings are looked up by, in
+// order: its alias-resolved identifier, then its family — the same
+// keys the Anthropic backend picks its profile by.
+func (rs *runScope) modelKeys(model agent.Model) (id, family string) {
+	return rs.opts.Config.Models.ResolveAlias(string(model)), model.Tier()
+}
+
+// diffBudget returns the configured per-prompt diff budget for model.
+func (rs *runScope) diffBudget(model agent.Model) int {
+	if rs.opts.Config == nil {
+		return 0
 	}
+	return rs.opts.Config.Models.DiffBudgetFor(rs.modelKeys(model))
+}
 
-	exitCode := 0
-	if output.ShouldFail() {
-		exitCode = 1
+// contextWindow returns the configured context window for model from
+// its profile.
+func (rs *runScope) contextWindow(model agent.Model) int {
+	if rs.opts.Config == nil {
+		return 0
 	}
+	return rs.opts.Config.Models.ContextWindowFor(rs.modelKeys(model))
+}
 
-	return Result{
-		Name:            s.Name,
-		Status:          output.Status,
-		Blocking:        len(output.Blocking),
-		Major:           len(output.Major),
-		Warning:         len(output.Warning),
-		ExitCode:        exitCode,
-		Mandatory:       s.Mandatory,
-		Elapsed:         float64(time.Since(start).Milliseconds()),
-		BlockingDetails: output.Blocking,
-		MajorDetails:    output.Major,
-		WarningDetails:  output.Warning,
-		InfoDetails:     output.Info,
+// modelIdentity returns the resolved model and profile a skill's cached
+// result is keyed on, or "" (the bare model name) without a config.
+func (rs *runScope) modelIdentity(model agent.Model) string {
+	if rs.opts.Config == nil {
+		return ""
+	}
+	return rs.opts.Config.Models.ModelIdentity(rs.modelKeys(model))
+}
+
+// credentialEnv returns the configured provider credential variables
+// withheld from exec skills.
+func (o *RunOpts) credentialEnv() []string {
+	if o.Config == nil {
+		return nil
+	}
+	return o.Config.Providers.CredentialEnv()
+}
+
+// diffThresholds returns the configured diff size thresholds, or the
+// defaults without a config, as resolveProfile computes the profile.
+func (o *RunOpts) diffThresholds() config.DiffConfig {
+	if o.Config == nil {
+		return config.Default().Diff
 	}
+	return o.Config.Diff
+}
+
+// repairRetries returns the configured schema-repair retry count.
+func (o *RunOpts) repairRetries() int {
+	if o.Config == nil {
+		return 0
+	}
+	return o.Config.Check.EffectiveRepairRetries()
+}
+
+// exclude returns the config exclude patterns applied with .bonsaiignore.
+func (o *RunOpts) exclude() []string {
+	if o.Config == nil {
+		return nil
+	}
+	return o.Config.Exclude
+}
+
+// effectiveFailOn returns the failure threshold, defaulting to blocking.
+func (o *RunOpts) effectiveFailOn() registry.FailOn {
+	if o.FailOn == "" {
+		return registry.FailOnBlocking
+	}
+	return o.FailOn
 }
 
 // resolveModel picks the model: explicit override > config routing by cost tier.
@@ -454,12 +842,43 @@ func errorResult(s registry.Skill, start time.Time, err error) Result {
 	}
 }
 
+// withOptionalTimeout derives a context bounded by timeout, or a plain
+// cancelable context when timeout is zero.
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// timeoutReason explains which deadline ended a skill evaluation.
+func timeoutReason(runCtx context.Context, skillTimeout time.Duration) string {
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return "run deadline exceeded"
+	}
+	return fmt.Sprintf("exceeded skill timeout (%s)", skillTimeout)
+}
+
+// timeoutResult builds a Result for a skill whose evaluation was cut
+// short by a deadline. Like errors, timeouts fail the skill.
+func timeoutResult(s registry.Skill, start time.Time, reason string) Result {
+	r := errorResult(s, start, errors.New(reason))
+	r.Status = "timeout"
+	return r
+}
+
 // ShouldFail returns true if the report indicates a blocking failure.
 // Matches ai-check.sh exit logic:
 //   - exit 1 if all skills were skipped (no validation occurred)
 //   - exit 1 if blocking_failed > 0
+//
+// Skills skipped as not applicable to the diff were deliberately ruled
+// out, so a run where every skill was inapplicable does not fail.
+//
+// Whether a skill failed is decided per result against the run's FailOn
+// threshold, so BlockingFailed already reflects it.
 func (r *Report) ShouldFail() bool {
-	if r.Total > 0 && r.Skipped == r.Total {
+	if r.Total > 0 && r.Skipped == r.Total && r.NotApplicable < r.Total {
 		return true // All skipped = false pass
 	}
 	return r.BlockingFailed > 0
@@ -3,6 +3,9 @@ package orchestrator_test
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pithecene-io/bonsai/internal/agent"
@@ -443,3 +446,45 @@ func TestRun_SkipWarning_MinoritySkipped(t *testing.T) {
 		t.Errorf("expected empty SkipWarning for minority skip, got %q", report.SkipWarning)
 	}
 }
+
+func TestRun_NativeSkill_SkipsAgent(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "node_modules"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "node_modules", "x.js"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &agent.MockAgent{NameVal: "test"}
+	orch := newTestOrch(t, mock)
+	s := passSkill("forbidden-top-level-detector", true)
+	s.Engine = registry.EngineNative
+
+	report, err := orch.Run(context.Background(), defaultOpts([]registry.Skill{s}, root), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(mock.EvaluateCalls) != 0 {
+		t.Errorf("native skill invoked the agent %d times", len(mock.EvaluateCalls))
+	}
+	r := report.Results[0]
+	if r.Status != "pass" || r.Major != 1 || r.Model != "" || r.CostUSD != 0 {
+		t.Errorf("unexpected native result: %+v", r)
+	}
+}
+
+func TestRun_NativeSkill_MissingImplementation(t *testing.T) {
+	orch := newTestOrch(t, &agent.MockAgent{NameVal: "test"})
+	s := passSkill("no-such-native-skill", false)
+	s.Engine = registry.EngineNative
+
+	report, err := orch.Run(context.Background(), defaultOpts([]registry.Skill{s}, t.TempDir()), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r := report.Results[0]; r.Status != "error" || !strings.Contains(r.ErrorDetail, "no native implementation") {
+		t.Errorf("unexpected result: %+v", r)
+	}
+}
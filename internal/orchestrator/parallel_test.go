@@ -3,13 +3,19 @@ package orchestrator_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/pithecene-io/bonsai/internal/agent"
+	"github.com/pithecene-io/bonsai/internal/assets"
 	"github.com/pithecene-io/bonsai/internal/config"
 	"github.com/pithecene-io/bonsai/internal/orchestrator"
 	"github.com/pithecene-io/bonsai/internal/registry"
+	"github.com/pithecene-io/bonsai/internal/skill"
 )
 
 func passJSON() string {
@@ -285,6 +291,289 @@ func TestRun_FindingDetails(t *testing.T) {
 	}
 }
 
+func TestRun_StructuredFindings(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateResponse: `{"skill": "repo-convention-enforcer", "version": "v1", "status": "fail",
+			"blocking": [{"message": "forbidden dir", "path": "tmp/x.go", "start_line": 4}],
+			"major": ["naming drift"], "warning": [], "info": []}`,
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{passSkill("repo-convention-enforcer", false)}
+
+	report, err := orch.Run(t.Context(), defaultOpts(skills, t.TempDir()), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	r := report.Results[0]
+	if len(r.BlockingFindings) != 1 || r.BlockingFindings[0].Path != "tmp/x.go" || r.BlockingFindings[0].StartLine != 4 {
+		t.Errorf("BlockingFindings = %+v", r.BlockingFindings)
+	}
+	if len(r.BlockingDetails) != 1 || r.BlockingDetails[0] != "tmp/x.go:4: forbidden dir" {
+		t.Errorf("BlockingDetails = %v, want [tmp/x.go:4: forbidden dir]", r.BlockingDetails)
+	}
+	if len(r.MajorFindings) != 1 || r.MajorFindings[0].Message != "naming drift" {
+		t.Errorf("MajorFindings = %+v", r.MajorFindings)
+	}
+}
+
+// messageBaseline baselines findings by message.
+type messageBaseline map[string]bool
+
+func (b messageBaseline) Contains(_, _ string, f skill.Finding) bool { return b[f.Message] }
+
+func TestRun_BaselinedFindingsDoNotFail(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateResponse: `{"skill": "repo-convention-enforcer", "version": "v1", "status": "fail",
+			"blocking": ["legacy violation"], "major": ["legacy drift", "new drift"],
+			"warning": [], "info": []}`,
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{passSkill("repo-convention-enforcer", true)}
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Baseline = messageBaseline{"legacy violation": true, "legacy drift": true}
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if report.ShouldFail() || report.BlockingFailed != 0 {
+		t.Errorf("ShouldFail = %v, BlockingFailed = %d; baselined blocking must not fail", report.ShouldFail(), report.BlockingFailed)
+	}
+	if report.Baselined != 2 || !report.BaselineUsed {
+		t.Errorf("Baselined = %d, BaselineUsed = %v; want 2, true", report.Baselined, report.BaselineUsed)
+	}
+	r := report.Results[0]
+	if r.Status != "pass" || r.Blocking != 0 || r.Major != 1 || r.MajorDetails[0] != "new drift" {
+		t.Errorf("result = %+v", r)
+	}
+	if len(r.BaselinedFindings) != 2 || r.BaselinedFindings[0].Severity != "blocking" {
+		t.Errorf("BaselinedFindings = %+v", r.BaselinedFindings)
+	}
+}
+
+func TestRun_SuppressedFindingsDoNotFail(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateResponse: `{"skill": "hardcoded-secret-pattern-detector", "version": "v1", "status": "fail",
+			"blocking": [{"message": "api key literal", "path": "testdata/keys.go", "start_line": 3}],
+			"major": [{"message": "token literal", "path": "testdata/keys.go", "start_line": 5}],
+			"warning": [], "info": []}`,
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "testdata"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := "package testdata\n" +
+		"// bonsai:ignore hardcoded-secret-pattern-detector reason=\"test fixture\"\n" +
+		"const key = \"sk-test\"\n" +
+		"\n" +
+		"const token = \"tok\" // bonsai:ignore other-skill reason=\"not this skill\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "testdata", "keys.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{passSkill("hardcoded-secret-pattern-detector", true)}
+
+	report, err := orch.Run(t.Context(), defaultOpts(skills, dir), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if report.ShouldFail() || report.Suppressed != 1 {
+		t.Errorf("ShouldFail = %v, Suppressed = %d; want false, 1", report.ShouldFail(), report.Suppressed)
+	}
+	r := report.Results[0]
+	if r.Status != "pass" || r.Blocking != 0 || r.Major != 1 {
+		t.Errorf("result = %+v", r)
+	}
+	if len(r.SuppressedFindings) != 1 {
+		t.Fatalf("SuppressedFindings = %+v, want 1", r.SuppressedFindings)
+	}
+	sf := r.SuppressedFindings[0]
+	if sf.Severity != "blocking" || sf.Reason != "test fixture" || sf.Directive != "testdata/keys.go:2" {
+		t.Errorf("suppressed = %+v", sf)
+	}
+}
+
+func TestRun_FailOnMajor(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateResponse: `{"skill": "repo-convention-enforcer", "version": "v1", "status": "pass",
+			"blocking": [], "major": ["naming drift"], "warning": [], "info": []}`,
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{passSkill("repo-convention-enforcer", true)}
+
+	opts := defaultOpts(skills, t.TempDir())
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.ShouldFail() || report.FailOn != "blocking" {
+		t.Errorf("default threshold: ShouldFail = %v, FailOn = %q", report.ShouldFail(), report.FailOn)
+	}
+
+	opts.FailOn = registry.FailOnMajor
+	report, err = orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.ShouldFail() || report.BlockingFailed != 1 || report.FailOn != "major" {
+		t.Errorf("major threshold: ShouldFail = %v, BlockingFailed = %d, FailOn = %q",
+			report.ShouldFail(), report.BlockingFailed, report.FailOn)
+	}
+	if r := report.Results[0]; r.Status != "fail" || r.ExitCode != 1 {
+		t.Errorf("result = %+v, want status fail, exit 1", r)
+	}
+}
+
+func TestRun_SkillTimeout(t *testing.T) {
+	// Evaluation takes 200ms; the skill with a 20ms timeout is cut short
+	// while the skill without one completes.
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateFunc: func(ctx context.Context, _, _ string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				return passJSON(), nil
+			}
+		},
+	}
+
+	orch := newTestOrch(t, mock)
+	slow := passSkill("repo-convention-enforcer", true)
+	slow.Timeout = 20 * time.Millisecond
+	skills := []registry.Skill{slow, passSkill("arch-index-alignment", true)}
+
+	report, err := orch.Run(t.Context(), defaultOpts(skills, t.TempDir()), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.TimedOut != 1 || report.Passed != 1 || report.BlockingFailed != 1 {
+		t.Errorf("TimedOut = %d, Passed = %d, BlockingFailed = %d, want 1, 1, 1",
+			report.TimedOut, report.Passed, report.BlockingFailed)
+	}
+	r := report.Results[0]
+	if r.Status != "timeout" || r.ExitCode != 1 || r.ErrorDetail != "exceeded skill timeout (20ms)" {
+		t.Errorf("result = status %q, exit %d, detail %q", r.Status, r.ExitCode, r.ErrorDetail)
+	}
+}
+
+func TestRun_RunDeadline(t *testing.T) {
+	// With concurrency=1 and a hung agent, the run deadline ends the
+	// first skill and the second never starts; both are recorded.
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateFunc: func(ctx context.Context, _, _ string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{
+		passSkill("repo-convention-enforcer", true),
+		passSkill("arch-index-alignment", false),
+	}
+
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Concurrency = 1
+	opts.Timeout = 30 * time.Millisecond
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Results) != 2 || report.TimedOut != 2 {
+		t.Fatalf("Results = %d, TimedOut = %d, want 2, 2", len(report.Results), report.TimedOut)
+	}
+	if d := report.Results[0].ErrorDetail; d != "run deadline exceeded" {
+		t.Errorf("first detail = %q", d)
+	}
+	if d := report.Results[1].ErrorDetail; d != "run deadline exceeded before skill started" {
+		t.Errorf("second detail = %q", d)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("mock calls = %d, want 1", mock.CallCount())
+	}
+}
+
+func TestRun_UsageAndCost(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal:          "test",
+		EvaluateResponse: passJSON(),
+		EvaluateUsage:    agent.Usage{InputTokens: 100_000, OutputTokens: 10_000},
+	}
+
+	orch := newTestOrch(t, mock)
+	cheap := passSkill("repo-convention-enforcer", true)
+	moderate := passSkill("arch-index-alignment", true)
+	moderate.Cost = "moderate"
+
+	report, err := orch.Run(t.Context(), defaultOpts([]registry.Skill{cheap, moderate}, t.TempDir()), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// Default pricing: haiku $1/$5, sonnet $3/$15 per million tokens.
+	if r := report.Results[0]; r.Model != "haiku" || r.InputTokens != 100_000 || r.CostUSD != 0.15 {
+		t.Errorf("cheap result: model %q, input %d, cost %v", r.Model, r.InputTokens, r.CostUSD)
+	}
+	if report.InputTokens != 200_000 || report.OutputTokens != 20_000 {
+		t.Errorf("report tokens = %d/%d, want 200000/20000", report.InputTokens, report.OutputTokens)
+	}
+	if report.CostByModel["sonnet"] != 0.45 || report.CostUSD != 0.6 {
+		t.Errorf("cost = %v by model %v, want 0.6 (sonnet 0.45)", report.CostUSD, report.CostByModel)
+	}
+}
+
+func TestRun_BudgetStopsDispatch(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal:          "test",
+		EvaluateResponse: passJSON(),
+		EvaluateUsage:    agent.Usage{InputTokens: 100_000, OutputTokens: 10_000}, // $0.15 on haiku
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{
+		passSkill("repo-convention-enforcer", true),
+		passSkill("arch-index-alignment", true),
+		passSkill("orphan-directory-detector", true),
+	}
+
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Concurrency = 1
+	opts.MaxCost = 0.25
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if mock.CallCount() != 2 {
+		t.Errorf("mock calls = %d, want 2 (budget reached after the second skill)", mock.CallCount())
+	}
+	if report.BudgetSkipped != 1 || report.Skipped != 1 || report.MaxCost != 0.25 {
+		t.Errorf("BudgetSkipped = %d, Skipped = %d, MaxCost = %v", report.BudgetSkipped, report.Skipped, report.MaxCost)
+	}
+	if r := report.Results[2]; r.Status != "skipped" || r.SkippedReason != "budget exhausted ($0.30 of $0.25 spent)" {
+		t.Errorf("third result = status %q, reason %q", r.Status, r.SkippedReason)
+	}
+	if report.SkipWarning != "" {
+		t.Errorf("SkipWarning = %q, want none for budget skips", report.SkipWarning)
+	}
+}
+
 func TestRun_ModelRouting(t *testing.T) {
 	// Verify that the model from config.ModelsConfig reaches the agent.
 	mock := &agent.MockAgent{
@@ -452,3 +741,178 @@ func TestRun_ModelRouting_NilConfig(t *testing.T) {
 		t.Errorf("call[0].Model = %q, want empty (nil config)", got)
 	}
 }
+
+func TestRun_StagedStopsAfterFailedTier(t *testing.T) {
+	mock := &agent.MockAgent{NameVal: "test", EvaluateResponse: failJSON()}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{
+		passSkill("god-module-detector", true),
+		passSkill("repo-convention-enforcer", true),
+		passSkill("arch-index-alignment", false),
+	}
+	skills[0].Cost = registry.CostHeavy
+	skills[2].Cost = registry.CostModerate
+
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Strategy = orchestrator.StrategyStaged
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("mock calls = %d, want 1 (only the cheap tier runs)", mock.CallCount())
+	}
+	if report.TierSkipped != 2 || report.BlockingFailed != 1 {
+		t.Errorf("TierSkipped = %d, BlockingFailed = %d, want 2, 1", report.TierSkipped, report.BlockingFailed)
+	}
+	for _, i := range []int{0, 2} {
+		if r := report.Results[i]; r.Status != "skipped" || r.SkippedReason != "earlier tier failed" {
+			t.Errorf("result %d = status %q, reason %q", i, r.Status, r.SkippedReason)
+		}
+	}
+}
+
+func TestRun_StagedRunsCheapTierFirst(t *testing.T) {
+	cfg := config.Default()
+	// The cheap tier fails, but its only skill is advisory.
+	mock := &agent.MockAgent{
+		NameVal: "test",
+		EvaluateFunc: func(_ context.Context, _, _ string, model agent.Model, _ agent.ToolPolicy) (string, error) {
+			if string(model) == cfg.Models.Skills.Cheap {
+				return failJSON(), nil
+			}
+			return passJSON(), nil
+		},
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{
+		passSkill("god-module-detector", true),
+		passSkill("repo-convention-enforcer", false),
+	}
+	skills[0].Cost = registry.CostHeavy
+
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Config = cfg
+	opts.Strategy = orchestrator.StrategyStaged
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if mock.CallCount() != 2 || string(mock.EvaluateCalls[0].Model) != cfg.Models.Skills.Cheap {
+		t.Fatalf("calls = %d, first model %q; want the cheap tier dispatched first", mock.CallCount(), mock.EvaluateCalls[0].Model)
+	}
+	if report.Passed != 1 || report.Failed != 1 || report.TierSkipped != 0 {
+		t.Errorf("Passed = %d, Failed = %d, TierSkipped = %d, want 1, 1, 0", report.Passed, report.Failed, report.TierSkipped)
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	for in, want := range map[string]orchestrator.Strategy{
+		"":         orchestrator.StrategyParallel,
+		"parallel": orchestrator.StrategyParallel,
+		"staged":   orchestrator.StrategyStaged,
+	} {
+		if got, err := orchestrator.ParseStrategy(in); err != nil || got != want {
+			t.Errorf("ParseStrategy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := orchestrator.ParseStrategy("waves"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestRun_RecordsBackendFallback(t *testing.T) {
+	down := &agent.MockAgent{NameVal: "down", EvaluateErr: errors.New("503 overloaded")}
+	up := &agent.MockAgent{NameVal: "up", EvaluateResponse: passJSON()}
+	router := &agent.Router{
+		Templates: map[string]agent.Agent{"down": down, "up": up},
+		Chains:    map[string][]string{agent.FamilyClaude: {"down", "up"}},
+	}
+
+	orch := orchestrator.New(router, assets.NewResolver(""))
+	report, err := orch.Run(t.Context(), defaultOpts([]registry.Skill{passSkill("repo-convention-enforcer", true)}, t.TempDir()), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	r := report.Results[0]
+	if r.Status != "pass" || r.Backend != "up" {
+		t.Errorf("result status %q backend %q, want pass via up", r.Status, r.Backend)
+	}
+	if len(r.Fallbacks) != 1 || r.Fallbacks[0] != "down" {
+		t.Errorf("Fallbacks = %v, want [down]", r.Fallbacks)
+	}
+}
+
+func TestRun_BudgetAtDefaultConcurrency(t *testing.T) {
+	// With no --jobs, a budget dispatches serially: the wave must not
+	// start every skill before any cost is known.
+	mock := &agent.MockAgent{
+		NameVal:          "test",
+		EvaluateResponse: passJSON(),
+		EvaluateUsage:    agent.Usage{InputTokens: 100_000, OutputTokens: 10_000}, // $0.15 on haiku
+	}
+
+	orch := newTestOrch(t, mock)
+	skills := []registry.Skill{
+		passSkill("repo-convention-enforcer", true),
+		passSkill("arch-index-alignment", true),
+		passSkill("orphan-directory-detector", true),
+		passSkill("test-coverage-sentinel", true),
+	}
+
+	opts := defaultOpts(skills, t.TempDir())
+	opts.Concurrency = 0
+	opts.MaxCost = 0.1
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("mock calls = %d, want 1 (budget reached after the first skill)", mock.CallCount())
+	}
+	if report.BudgetSkipped != 3 {
+		t.Errorf("BudgetSkipped = %d, want 3", report.BudgetSkipped)
+	}
+}
+
+func TestRun_ContextWindowFromResolvedModelProfile(t *testing.T) {
+	// A profile keyed by the pinned id an alias resolves to applies to
+	// prompt budgeting, as it does to the backend and the cache key.
+	mock := &agent.MockAgent{NameVal: "test", EvaluateResponse: passJSON()}
+	orch := newTestOrch(t, mock)
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "vendor", "example.com", "module-with-a-long-name", "pkg")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// ~120 KB of tree: over a 30k-token window, well within 200k.
+	for i := range 1000 {
+		name := filepath.Join(dir, "generated_source_file_with_a_deliberately_long_name_"+strconv.Itoa(i)+".go")
+		if err := os.WriteFile(name, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Models.Skills.Cheap = "sonnet"
+	cfg.Models.Aliases = map[string]string{"sonnet": "claude-sonnet-4-6"}
+	cfg.Models.Profiles["claude-sonnet-4-6"] = config.ModelProfile{ContextWindow: 30_000}
+
+	skills := []registry.Skill{passSkill("repo-convention-enforcer", true)}
+	opts := defaultOpts(skills, root)
+	opts.Config = cfg
+
+	report, err := orch.Run(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r := report.Results[0]; len(r.Elided) == 0 {
+		t.Errorf("Elided = %v, want the tree collapsed under the id-keyed 30k window", r.Elided)
+	}
+}
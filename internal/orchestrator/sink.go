@@ -1,21 +1,36 @@
 package orchestrator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
-// LoggerSink returns an event channel and a done channel. The event channel
-// is backed by a goroutine that formats events as human-readable strings and
-// calls the provided logger function. The done channel is closed when the
-// goroutine exits (after the event channel is closed).
+// sinkBuffer is the event buffer for the logger sink.
+const sinkBuffer = 64
+
+// LoggerSink returns an event channel and a done channel. Events sent on
+// the channel are formatted as human-readable strings and passed to the
+// provided logger function. The done channel is closed once the event
+// channel has been closed and every event logged.
 func LoggerSink(logger func(string)) (events chan<- Event, done <-chan struct{}) {
-	ch := make(chan Event, 64)
+	bus := NewBus(sinkBuffer)
+	bus.Handle(LogHandler(logger))
 	d := make(chan struct{})
 	go func() {
 		defer close(d)
-		for ev := range ch {
-			logEvent(logger, ev)
-		}
+		bus.Wait()
 	}()
-	return ch, d
+	return bus.Publish(), d
+}
+
+// LogHandler returns a Bus handler that formats events as the
+// human-readable progress lines printed by check, fix, and implement.
+// A nil logger defaults to fmt.Println.
+func LogHandler(logger func(string)) func(Event) {
+	if logger == nil {
+		logger = func(msg string) { fmt.Println(msg) }
+	}
+	return func(ev Event) { logEvent(logger, ev) }
 }
 
 func logEvent(logger func(string), ev Event) {
@@ -40,17 +55,40 @@ func logEvent(logger func(string), ev Event) {
 	}
 }
 
+// resultAnnotations returns the ", ..."-prefixed notes appended to a
+// result's summary: cache hits, repairs, elisions, backend fallbacks,
+// and cost.
+func resultAnnotations(r *Result) string {
+	var b strings.Builder
+	if r.Cached {
+		b.WriteString(", cached")
+	}
+	if r.Repairs > 0 {
+		fmt.Fprintf(&b, ", schema-repaired ×%d", r.Repairs)
+	}
+	if len(r.Elided) > 0 {
+		fmt.Fprintf(&b, ", context elided ×%d", len(r.Elided))
+	}
+	if len(r.Fallbacks) > 0 && r.Backend != "" {
+		fmt.Fprintf(&b, ", via %s after %d failed attempt(s)", r.Backend, len(r.Fallbacks))
+	}
+	if r.CostUSD > 0 {
+		fmt.Fprintf(&b, ", $%.4f", r.CostUSD)
+	}
+	return b.String()
+}
+
 func logResultLine(logger func(string), r *Result) {
 	if r == nil {
 		return
 	}
-	summary := r.SummaryLine()
+	summary := r.SummaryLine() + resultAnnotations(r)
 	switch {
-	case r.Status == "error":
+	case r.Status == "error" || r.Status == "timeout":
 		if r.ErrorDetail != "" {
-			logger(fmt.Sprintf("  ✖ %s [error: %s]", r.Name, r.ErrorDetail))
+			logger(fmt.Sprintf("  ✖ %s [%s: %s]", r.Name, r.Status, r.ErrorDetail))
 		} else {
-			logger(fmt.Sprintf("  ✖ %s [error]", r.Name))
+			logger(fmt.Sprintf("  ✖ %s [%s]", r.Name, r.Status))
 		}
 	case !r.Failed():
 		logger(fmt.Sprintf("  ✔ %s (%s)", r.Name, summary))
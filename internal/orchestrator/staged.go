@@ -0,0 +1,82 @@
+package orchestrator
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Strategy selects how runnable skills are dispatched.
+type Strategy string
+
+// Execution strategies.
+const (
+	// StrategyParallel dispatches every runnable skill at once, bounded
+	// only by Concurrency. The default.
+	StrategyParallel Strategy = "parallel"
+	// StrategyStaged dispatches skills in cost-tier waves (cheap, then
+	// moderate, then heavy) and does not start the next wave once a
+	// mandatory skill has failed.
+	StrategyStaged Strategy = "staged"
+)
+
+// ParseStrategy validates and returns a Strategy from a raw string.
+// Empty parses as StrategyParallel.
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case "", StrategyParallel:
+		return StrategyParallel, nil
+	case StrategyStaged:
+		return StrategyStaged, nil
+	}
+	return "", fmt.Errorf("invalid strategy %q (valid: parallel, staged)", s)
+}
+
+// costWaves groups runnable skills by cost tier, cheapest first,
+// preserving registry order within a tier.
+func costWaves(runnable []indexedSkill) [][]indexedSkill {
+	sorted := slices.Clone(runnable)
+	slices.SortStableFunc(sorted, func(a, b indexedSkill) int {
+		return cmp.Compare(a.skill.Cost.Rank(), b.skill.Cost.Rank())
+	})
+
+	var waves [][]indexedSkill
+	for i, is := range sorted {
+		if i == 0 || is.skill.Cost.Rank() != sorted[i-1].skill.Cost.Rank() {
+			waves = append(waves, nil)
+		}
+		waves[len(waves)-1] = append(waves[len(waves)-1], is)
+	}
+	return waves
+}
+
+// dispatchStaged runs one cost-tier wave at a time. Once a wave leaves a
+// mandatory failure, the remaining waves are recorded as skipped rather
+// than dispatched.
+func (rs *runScope) dispatchStaged(ctx context.Context, runnable []indexedSkill) {
+	waves := costWaves(runnable)
+	for i, wave := range waves {
+		rs.dispatchWave(ctx, wave)
+		if !rs.mandatoryFailed(wave) {
+			continue
+		}
+		for _, rest := range waves[i+1:] {
+			for _, is := range rest {
+				rs.recordSkip(is, skipReasonEarlierTier, skipReasonEarlierTier)
+			}
+		}
+		return
+	}
+}
+
+// mandatoryFailed reports whether any mandatory skill in wave failed.
+func (rs *runScope) mandatoryFailed(wave []indexedSkill) bool {
+	for _, is := range wave {
+		r := &rs.results[is.index]
+		if r.Mandatory && r.Failed() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/pithecene-io/bonsai/internal/skill"
+	"github.com/pithecene-io/bonsai/internal/suppress"
+)
+
+// SuppressedFinding is a finding silenced by an inline bonsai:ignore
+// directive, tagged with the severity it was reported at and the
+// directive's justification.
+type SuppressedFinding struct {
+	Severity  string `json:"severity"`
+	Reason    string `json:"reason"`
+	Directive string `json:"directive"` // "path:line" of the bonsai:ignore comment
+	skill.Finding
+}
+
+// applySuppressions moves findings silenced by inline directives out of
+// o and returns them. Like baselined findings, suppressed findings do not
+// count toward severity totals, exit codes, or BlockingFailed.
+func applySuppressions(ix *suppress.Index, skillName string, o *skill.Output) []SuppressedFinding {
+	if ix == nil {
+		return nil
+	}
+	var matched []SuppressedFinding
+	removeFindings(o, func(severity string, f skill.Finding) bool {
+		d, ok := ix.Match(skillName, f)
+		if !ok {
+			return false
+		}
+		matched = append(matched, SuppressedFinding{
+			Severity:  severity,
+			Reason:    d.Reason,
+			Directive: fmt.Sprintf("%s:%d", f.Path, d.Line),
+			Finding:   f,
+		})
+		return true
+	})
+	return matched
+}
@@ -0,0 +1,36 @@
+package prompt
+
+import "fmt"
+
+// BytesPerToken is the rough bytes-per-token ratio used to estimate
+// prompt sizes without a tokenizer. It errs toward overestimating for
+// source code, which tokenizes denser than prose.
+const BytesPerToken = 4
+
+// EstimateTokens approximates the token count of s.
+func EstimateTokens(s string) int {
+	return (len(s) + BytesPerToken - 1) / BytesPerToken
+}
+
+// layer is an optional titled section of a system prompt.
+type layer struct {
+	title   string // e.g. "Architecture index (docs/ARCH_INDEX.md):"
+	name    string // short name for elision notes
+	content string
+}
+
+// fitLayers keeps layers, in priority order, while the running token
+// total stays within budget, and describes the ones dropped. A budget
+// <= 0 keeps everything.
+func fitLayers(layers []layer, used, budget int) (kept []layer, elided []string) {
+	for _, l := range layers {
+		cost := EstimateTokens(l.title) + EstimateTokens(l.content)
+		if budget > 0 && used+cost > budget {
+			elided = append(elided, fmt.Sprintf("%s (~%d tokens)", l.name, cost))
+			continue
+		}
+		used += cost
+		kept = append(kept, l)
+	}
+	return kept, elided
+}
@@ -185,16 +185,38 @@ type ValidatorOpts struct {
 	SkillBody    string // SKILL.md body (frontmatter stripped)
 	OutputSchema string // output.schema.json content
 	Lite         bool   // Lite skips governance layers for fast evaluation (haiku)
+	MaxTokens    int    // Token budget for the prompt; repo governance layers that do not fit are dropped. 0 = unlimited
 }
 
 // BuildValidator builds a system prompt for skill validation.
 // Injection order: Global CLAUDE.md → Repo CLAUDE.md → AGENTS.md → ARCH_INDEX → SKILL.md → schema → suffix
 func (b *Builder) BuildValidator(opts ValidatorOpts) (string, error) {
+	prompt, _, err := b.BuildValidatorBudgeted(opts)
+	return prompt, err
+}
+
+// BuildValidatorBudgeted builds a validator system prompt within
+// opts.MaxTokens and describes the layers elided to fit. The repo
+// governance layers are optional, kept in priority order (CLAUDE.md,
+// AGENTS.md, ARCH_INDEX.md); the sovereign preamble, skill body, and
+// schema are always included.
+func (b *Builder) BuildValidatorBudgeted(opts ValidatorOpts) (string, []string, error) {
 	var parts []string
 
 	// Preamble + mode
 	parts = append(parts, "You are operating in VALIDATOR mode.", "")
 
+	// SKILL.md body, output schema, and JSON-only suffix
+	tail := []string{
+		"", opts.SkillBody,
+		"",
+		"You must output valid JSON conforming exactly to this schema:",
+		"",
+		opts.OutputSchema,
+		"",
+		"No markdown. No prose. No explanation. No code fences. JSON only.",
+	}
+
 	if opts.Lite {
 		// Minimal preamble for fast evaluation (haiku). Skips all
 		// governance layers to stay within tight token/latency budgets.
@@ -202,46 +224,43 @@ func (b *Builder) BuildValidator(opts ValidatorOpts) (string, error) {
 			"You are a code-quality validator. Evaluate the repository and emit JSON.",
 			"",
 		)
-	} else {
-		// Validator-trimmed governance preamble (sovereign).
-		// Uses a minimal subset of claude.md — omits commit/PR conventions,
-		// gitmoji table, diff-only rules, and other interactive-only content
-		// to keep system prompt small and fast for non-interactive evaluation.
-		claudeVal, err := b.resolver.ReadEmbedded("claude_validator.md")
-		if err != nil {
-			return "", fmt.Errorf("read claude_validator.md: %w", err)
-		}
-		parts = append(parts, string(claudeVal))
-
-		// Repo-local CLAUDE.md (additive)
-		if repoClaude := b.readRepoFile("CLAUDE.md"); repoClaude != "" {
-			parts = append(parts, "", "Repo-local constitution (CLAUDE.md):", "", repoClaude)
-		}
-
-		// AGENTS.md
-		if agentsMD := b.readRepoFile("AGENTS.md"); agentsMD != "" {
-			parts = append(parts, "", "Repo-local constraints (AGENTS.md):", "", agentsMD)
-		}
+		return strings.Join(append(parts, tail...), "\n"), nil, nil
+	}
 
-		// ARCH_INDEX.md
-		if archIndex := b.readArchIndex(); archIndex != "" {
-			parts = append(parts, "", "Architecture index (docs/ARCH_INDEX.md):", "", archIndex)
-		}
+	// Validator-trimmed governance preamble (sovereign).
+	// Uses a minimal subset of claude.md — omits commit/PR conventions,
+	// gitmoji table, diff-only rules, and other interactive-only content
+	// to keep system prompt small and fast for non-interactive evaluation.
+	claudeVal, err := b.resolver.ReadEmbedded("claude_validator.md")
+	if err != nil {
+		return "", nil, fmt.Errorf("read claude_validator.md: %w", err)
 	}
+	parts = append(parts, string(claudeVal))
 
-	// SKILL.md body
-	parts = append(parts, "", opts.SkillBody)
+	used := EstimateTokens(strings.Join(parts, "\n")) + EstimateTokens(strings.Join(tail, "\n"))
+	kept, elided := fitLayers(b.validatorLayers(), used, opts.MaxTokens)
+	for _, l := range kept {
+		parts = append(parts, "", l.title, "", l.content)
+	}
 
-	// Output schema + JSON-only suffix
-	parts = append(parts, "",
-		"You must output valid JSON conforming exactly to this schema:",
-		"",
-		opts.OutputSchema,
-		"",
-		"No markdown. No prose. No explanation. No code fences. JSON only.",
-	)
+	return strings.Join(append(parts, tail...), "\n"), elided, nil
+}
 
-	return strings.Join(parts, "\n"), nil
+// validatorLayers returns the repo governance documents present, in
+// priority order.
+func (b *Builder) validatorLayers() []layer {
+	var layers []layer
+	// Repo-local CLAUDE.md (additive)
+	if repoClaude := b.readRepoFile("CLAUDE.md"); repoClaude != "" {
+		layers = append(layers, layer{"Repo-local constitution (CLAUDE.md):", "CLAUDE.md", repoClaude})
+	}
+	if agentsMD := b.readRepoFile("AGENTS.md"); agentsMD != "" {
+		layers = append(layers, layer{"Repo-local constraints (AGENTS.md):", "AGENTS.md", agentsMD})
+	}
+	if archIndex := b.readArchIndex(); archIndex != "" {
+		layers = append(layers, layer{"Architecture index (docs/ARCH_INDEX.md):", "ARCH_INDEX.md", archIndex})
+	}
+	return layers
 }
 
 // loadContextLayers reads context/*.md files from the repo, sorted.
@@ -322,3 +322,45 @@ func assertContains(t *testing.T, haystack, needle string) {
 		t.Errorf("expected prompt to contain %q", needle)
 	}
 }
+
+func TestBuildValidatorBudgeted_ElidesLowPriorityLayers(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("# REPO-CLAUDE-MARKER"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	archIndex := "# ARCH-INDEX-MARKER\n" + strings.Repeat("x", 40_000)
+	if err := os.WriteFile(filepath.Join(dir, "docs", "ARCH_INDEX.md"), []byte(archIndex), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	b := prompt.NewBuilder(assets.NewResolver(dir), dir)
+	opts := prompt.ValidatorOpts{SkillBody: "SKILL-BODY-MARKER", OutputSchema: "{}"}
+
+	full, elided, err := b.BuildValidatorBudgeted(opts)
+	if err != nil {
+		t.Fatalf("BuildValidatorBudgeted: %v", err)
+	}
+	if len(elided) != 0 || !strings.Contains(full, "ARCH-INDEX-MARKER") {
+		t.Fatalf("unbudgeted prompt elided %v", elided)
+	}
+
+	opts.MaxTokens = prompt.EstimateTokens(full) - 5_000
+	result, elided, err := b.BuildValidatorBudgeted(opts)
+	if err != nil {
+		t.Fatalf("BuildValidatorBudgeted: %v", err)
+	}
+	if len(elided) != 1 || !strings.HasPrefix(elided[0], "ARCH_INDEX.md (~") {
+		t.Errorf("elided = %v, want only ARCH_INDEX.md", elided)
+	}
+	if strings.Contains(result, "ARCH-INDEX-MARKER") {
+		t.Error("ARCH_INDEX.md should be elided")
+	}
+	assertContains(t, result, "# REPO-CLAUDE-MARKER")
+	assertContains(t, result, "SKILL-BODY-MARKER")
+	if got := prompt.EstimateTokens(result); got > opts.MaxTokens {
+		t.Errorf("prompt is ~%d tokens, over budget %d", got, opts.MaxTokens)
+	}
+}
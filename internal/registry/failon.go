@@ -0,0 +1,67 @@
+package registry
+
+import "fmt"
+
+// FailOn is the lowest finding severity that fails a skill.
+type FailOn string
+
+// Failure thresholds, ordered most to least permissive.
+const (
+	FailOnBlocking FailOn = "blocking"
+	FailOnMajor    FailOn = "major"
+	FailOnWarning  FailOn = "warning"
+)
+
+// failOnSet is the set of valid failure thresholds.
+var failOnSet = map[FailOn]struct{}{
+	FailOnBlocking: {},
+	FailOnMajor:    {},
+	FailOnWarning:  {},
+}
+
+// ParseFailOn validates and returns a FailOn from a raw string.
+func ParseFailOn(s string) (FailOn, error) {
+	f := FailOn(s)
+	if _, ok := failOnSet[f]; !ok {
+		return "", fmt.Errorf("invalid fail-on threshold %q (valid: blocking, major, warning)", s)
+	}
+	return f, nil
+}
+
+// FailOnOverrides holds per-bundle and per-mode failure thresholds.
+type FailOnOverrides struct {
+	Bundles map[string]string `yaml:"bundles"`
+	Modes   map[string]string `yaml:"modes"`
+}
+
+// FailOnForBundle returns the failure threshold for a bundle:
+// defaults.fail_on.bundles[name], then defaults.bundles_fail_on, then
+// blocking.
+func (r *Registry) FailOnForBundle(name string) (FailOn, error) {
+	if v, ok := r.Defaults.FailOn.Bundles[name]; ok {
+		return parseFailOnKey(v, "defaults.fail_on.bundles."+name)
+	}
+	if r.Defaults.BundlesFailOn != "" {
+		return parseFailOnKey(r.Defaults.BundlesFailOn, "defaults.bundles_fail_on")
+	}
+	return FailOnBlocking, nil
+}
+
+// FailOnForMode returns the failure threshold for a governance mode:
+// defaults.fail_on.modes[mode], then blocking.
+func (r *Registry) FailOnForMode(mode GovMode) (FailOn, error) {
+	if v, ok := r.Defaults.FailOn.Modes[string(mode)]; ok {
+		return parseFailOnKey(v, "defaults.fail_on.modes."+string(mode))
+	}
+	return FailOnBlocking, nil
+}
+
+// parseFailOnKey parses a threshold read from skills.yaml, naming the
+// offending key on error.
+func parseFailOnKey(v, key string) (FailOn, error) {
+	f, err := ParseFailOn(v)
+	if err != nil {
+		return "", fmt.Errorf("skills.yaml %s: %w", key, err)
+	}
+	return f, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -22,11 +23,13 @@ type Registry struct {
 
 // Defaults holds default values from the registry.
 type Defaults struct {
-	SkillVersion  string   `yaml:"skill_version"`
-	BundlesFailOn string   `yaml:"bundles_fail_on"`
-	CostOrder     []string `yaml:"cost_order"`
-	ModeOrder     []string `yaml:"mode_order"`
-	RequiresDiff  *bool    `yaml:"requires_diff,omitempty"`
+	SkillVersion  string          `yaml:"skill_version"`
+	BundlesFailOn string          `yaml:"bundles_fail_on"`
+	FailOn        FailOnOverrides `yaml:"fail_on"`
+	CostOrder     []string        `yaml:"cost_order"`
+	ModeOrder     []string        `yaml:"mode_order"`
+	RequiresDiff  *bool           `yaml:"requires_diff,omitempty"`
+	Timeout       time.Duration   `yaml:"timeout,omitempty"` // per-skill timeout when a skill sets none
 }
 
 // EffectiveRequiresDiff returns the effective requires_diff default.
@@ -51,13 +54,41 @@ type Skill struct {
 	Trigger      string  `yaml:"trigger"`
 	RequiresDiff *bool   `yaml:"requires_diff,omitempty"`
 	RunWhen      RunWhen `yaml:"run_when"`
+
+	// Engine selects how the skill is evaluated: empty (a model call,
+	// the default) or "native" (a built-in Go implementation).
+	Engine string `yaml:"engine,omitempty"`
+
+	// Config is passed through to exec skills in their stdin document.
+	Config map[string]any `yaml:"config,omitempty"`
+
+	// Timeout bounds a single evaluation of this skill (e.g. "90s").
+	// Zero falls back to defaults.timeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
 }
 
-// RunWhen defines which modes a skill runs in.
+// RunWhen defines which modes a skill runs in and, optionally, which
+// changes make it applicable. Paths and Languages are evaluated against
+// the diff profile; a skill with either set is skipped when no changed
+// file matches.
 type RunWhen struct {
-	Modes []string `yaml:"modes"`
+	Modes     []string `yaml:"modes"`
+	Paths     []string `yaml:"paths,omitempty"`     // globs over changed file paths (see repo.MatchGlob)
+	Languages []string `yaml:"languages,omitempty"` // languages of changed files (see diff.Language)
 }
 
+// Skill triggers: the kind of change a skill is meant to review.
+// A skill whose trigger is not met by the diff profile is skipped as
+// not applicable. An empty or unrecognised trigger behaves as always.
+const (
+	TriggerAlways       = "always"
+	TriggerPatch        = "patch"
+	TriggerStructural   = "structural"
+	TriggerAPI          = "api"
+	TriggerArchitecture = "architecture"
+	TriggerHeavy        = "heavy"
+)
+
 // Bundles maps bundle names to ordered lists of skill names.
 type Bundles map[string][]string
 
@@ -70,6 +101,22 @@ func (s *Skill) EffectiveRequiresDiff(defaultVal bool) bool {
 	return defaultVal
 }
 
+// EngineNative marks a skill evaluated by a built-in Go implementation
+// rather than a model.
+const EngineNative = "native"
+
+// IsNative reports whether the skill declares engine: native.
+func (s *Skill) IsNative() bool { return s.Engine == EngineNative }
+
+// EffectiveTimeout returns the skill's timeout, falling back to the
+// registry default. Zero means unbounded.
+func (s *Skill) EffectiveTimeout(defaultVal time.Duration) time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return defaultVal
+}
+
 // Load loads the skills registry from the resolver.
 // It checks repo-local first, then embedded.
 func Load(resolver *assets.Resolver) (*Registry, error) {
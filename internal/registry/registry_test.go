@@ -2,6 +2,7 @@ package registry_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pithecene-io/bonsai/internal/assets"
 	"github.com/pithecene-io/bonsai/internal/registry"
@@ -167,6 +168,22 @@ func TestEffectiveRequiresDiff(t *testing.T) {
 	}
 }
 
+func TestEffectiveTimeout(t *testing.T) {
+	reg := loadTestRegistry(t)
+	if reg.Defaults.Timeout != 10*time.Minute {
+		t.Errorf("defaults.timeout = %v, want 10m", reg.Defaults.Timeout)
+	}
+
+	s := registry.Skill{Name: "x"}
+	if got := s.EffectiveTimeout(reg.Defaults.Timeout); got != 10*time.Minute {
+		t.Errorf("EffectiveTimeout (inherited) = %v, want 10m", got)
+	}
+	s.Timeout = 90 * time.Second
+	if got := s.EffectiveTimeout(reg.Defaults.Timeout); got != 90*time.Second {
+		t.Errorf("EffectiveTimeout (own) = %v, want 90s", got)
+	}
+}
+
 func TestDefaultsEffectiveRequiresDiff(t *testing.T) {
 	boolPtr := func(v bool) *bool { return &v }
 
@@ -189,3 +206,46 @@ func TestDefaultsEffectiveRequiresDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestFailOnResolution(t *testing.T) {
+	reg := &registry.Registry{Defaults: registry.Defaults{
+		BundlesFailOn: "blocking",
+		FailOn: registry.FailOnOverrides{
+			Bundles: map[string]string{"release": "major", "broken": "critical"},
+			Modes:   map[string]string{"AUDIT": "warning"},
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		resolve func() (registry.FailOn, error)
+		want    registry.FailOn
+		wantErr bool
+	}{
+		{"bundle default", func() (registry.FailOn, error) { return reg.FailOnForBundle("default") }, registry.FailOnBlocking, false},
+		{"bundle override", func() (registry.FailOn, error) { return reg.FailOnForBundle("release") }, registry.FailOnMajor, false},
+		{"invalid override", func() (registry.FailOn, error) { return reg.FailOnForBundle("broken") }, "", true},
+		{"mode default", func() (registry.FailOn, error) { return reg.FailOnForMode(registry.GovModeNormal) }, registry.FailOnBlocking, false},
+		{"mode override", func() (registry.FailOn, error) { return reg.FailOnForMode(registry.GovModeAudit) }, registry.FailOnWarning, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolve()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	if _, err := registry.ParseFailOn("major"); err != nil {
+		t.Errorf("ParseFailOn(major): %v", err)
+	}
+	if _, err := registry.ParseFailOn("info"); err == nil {
+		t.Error("expected error for info threshold")
+	}
+}
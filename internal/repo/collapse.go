@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CollapseTree shortens a sorted file listing until it fits maxBytes
+// (newline-joined) by replacing whole directories with a single
+// "dir/ (N files)" summary line. Deeper directories are collapsed
+// before shallower ones and, within a depth, those holding the most
+// files first. It returns the listing and the number of directories
+// summarized; a listing that does not fit even with every top-level
+// directory collapsed is returned in that state.
+func CollapseTree(files []string, maxBytes int) ([]string, int) {
+	if listingBytes(files) <= maxBytes {
+		return files, 0
+	}
+
+	counts := dirFileCounts(files)
+	collapsed := make(map[string]bool)
+	out, summaries := files, 0
+	for depth := maxDirDepth(counts); depth >= 1; depth-- {
+		under := bytesUnderDirs(files, collapsed, counts, depth)
+		total := listingBytes(out)
+		for _, dir := range largestFirst(under, counts) {
+			gain := under[dir] - len(dirSummary(dir, counts[dir])) - 1
+			if gain <= 0 {
+				continue
+			}
+			collapsed[dir] = true
+			total -= gain
+			if total <= maxBytes {
+				return renderCollapsed(files, collapsed, counts)
+			}
+		}
+		out, summaries = renderCollapsed(files, collapsed, counts)
+	}
+	return out, summaries
+}
+
+// dirSummary is the listing line that stands in for a collapsed directory.
+func dirSummary(dir string, n int) string {
+	return fmt.Sprintf("%s/ (%d files)", dir, n)
+}
+
+// listingBytes is the newline-joined size of a listing.
+func listingBytes(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		n += len(l) + 1
+	}
+	return n
+}
+
+// dirFileCounts counts the files beneath every directory.
+func dirFileCounts(files []string) map[string]int {
+	counts := make(map[string]int)
+	for _, f := range files {
+		for i := range len(f) {
+			if f[i] == '/' {
+				counts[f[:i]]++
+			}
+		}
+	}
+	return counts
+}
+
+func maxDirDepth(counts map[string]int) int {
+	depth := 0
+	for dir := range counts {
+		depth = max(depth, strings.Count(dir, "/")+1)
+	}
+	return depth
+}
+
+// collapsedOwner returns the shallowest collapsed ancestor of file, or "".
+func collapsedOwner(file string, collapsed map[string]bool) string {
+	for i := range len(file) {
+		if file[i] == '/' && collapsed[file[:i]] {
+			return file[:i]
+		}
+	}
+	return ""
+}
+
+// ancestorAt returns file's ancestor directory at depth, or "" when the
+// file is not that deep.
+func ancestorAt(file string, depth int) string {
+	seen := 0
+	for i := range len(file) {
+		if file[i] == '/' {
+			if seen++; seen == depth {
+				return file[:i]
+			}
+		}
+	}
+	return ""
+}
+
+// bytesUnderDirs returns, for each directory at depth, the bytes its
+// files currently occupy in the rendered listing.
+func bytesUnderDirs(files []string, collapsed map[string]bool, counts map[string]int, depth int) map[string]int {
+	under := make(map[string]int)
+	summarized := make(map[string]bool)
+	for _, f := range files {
+		dir := ancestorAt(f, depth)
+		if dir == "" {
+			continue
+		}
+		owner := collapsedOwner(f, collapsed)
+		switch {
+		case owner == "":
+			under[dir] += len(f) + 1
+		case !summarized[owner]:
+			summarized[owner] = true
+			under[dir] += len(dirSummary(owner, counts[owner])) + 1
+		}
+	}
+	return under
+}
+
+// largestFirst orders directories by file count, descending, then name.
+func largestFirst(under, counts map[string]int) []string {
+	return slices.SortedFunc(maps.Keys(under), func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// renderCollapsed lists files, replacing each collapsed directory's
+// files with its summary line at the position of its first file. It
+// also returns the number of summary lines.
+func renderCollapsed(files []string, collapsed map[string]bool, counts map[string]int) ([]string, int) {
+	var out []string
+	emitted := make(map[string]bool)
+	for _, f := range files {
+		owner := collapsedOwner(f, collapsed)
+		switch {
+		case owner == "":
+			out = append(out, f)
+		case !emitted[owner]:
+			emitted[owner] = true
+			out = append(out, dirSummary(owner, counts[owner]))
+		}
+	}
+	return out, len(emitted)
+}
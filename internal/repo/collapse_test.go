@@ -0,0 +1,44 @@
+package repo_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/repo"
+)
+
+func TestCollapseTree_WithinBudget(t *testing.T) {
+	files := []string{"a.go", "pkg/b.go"}
+	got, n := repo.CollapseTree(files, 100)
+	if n != 0 || !slices.Equal(got, files) {
+		t.Errorf("CollapseTree = %v, %d; want the listing unchanged", got, n)
+	}
+}
+
+func TestCollapseTree_DeepestLargestFirst(t *testing.T) {
+	var files []string
+	files = append(files, "README.md", "cmd/main.go")
+	for _, f := range []string{"a", "b", "c", "d", "e", "f"} {
+		files = append(files, "web/node_modules/lib/"+f+".js")
+	}
+	files = append(files, "web/src/app.ts", "web/src/index.ts")
+
+	full := len(strings.Join(files, "\n"))
+	got, n := repo.CollapseTree(files, full-50)
+
+	want := []string{"README.md", "cmd/main.go", "web/node_modules/lib/ (6 files)", "web/src/app.ts", "web/src/index.ts"}
+	if n != 1 || !slices.Equal(got, want) {
+		t.Errorf("CollapseTree = %v, %d; want %v, 1", got, n, want)
+	}
+}
+
+func TestCollapseTree_FallsBackToTopLevel(t *testing.T) {
+	files := []string{"a/x/1.go", "a/x/2.go", "a/y/3.go", "b/4.go", "b/5.go"}
+	got, n := repo.CollapseTree(files, 1)
+
+	want := []string{"a/ (3 files)", "b/ (2 files)"}
+	if n != 2 || !slices.Equal(got, want) {
+		t.Errorf("CollapseTree = %v, %d; want %v, 2", got, n, want)
+	}
+}
@@ -113,7 +113,7 @@ func TestTree(t *testing.T) {
 		t.Fatalf("write: %v", err)
 	}
 
-	tree, err := repo.Tree(dir)
+	tree, err := repo.Tree(dir, nil)
 	if err != nil {
 		t.Fatalf("Tree: %v", err)
 	}
@@ -152,7 +152,7 @@ func TestTreeWithScope(t *testing.T) {
 		t.Fatalf("git add: %v", err)
 	}
 
-	tree, err := repo.TreeWithScope(dir, "src/")
+	tree, err := repo.TreeWithScope(dir, "src/", nil)
 	if err != nil {
 		t.Fatalf("TreeWithScope: %v", err)
 	}
@@ -161,3 +161,20 @@ func TestTreeWithScope(t *testing.T) {
 		t.Errorf("TreeWithScope(src/) = %v, want [src/main.go]", tree)
 	}
 }
+
+func TestInScope(t *testing.T) {
+	prefixes := []string{"src/", "docs"}
+	for file, want := range map[string]bool{
+		"src/main.go":    true,
+		"docs/README.md": true,
+		"cmd/main.go":    false,
+		"srcfile.go":     false,
+	} {
+		if got := repo.InScope(file, prefixes); got != want {
+			t.Errorf("InScope(%q) = %v, want %v", file, got, want)
+		}
+	}
+	if !repo.InScope("cmd/main.go", nil) {
+		t.Error("every file should be in scope with no prefixes")
+	}
+}
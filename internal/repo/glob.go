@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether a slash-separated repo path matches a glob
+// pattern, with gitignore-like conventions:
+//
+//   - "**" matches any number of path segments ("internal/**/*.go")
+//   - a pattern without a slash matches at any depth ("*.go")
+//   - a trailing slash matches everything under a directory ("docs/")
+//
+// Other segments use path.Match syntax. A leading "/" or "./" anchors
+// the pattern at the repo root.
+func MatchGlob(pattern, name string) bool {
+	switch {
+	case strings.HasSuffix(pattern, "/"):
+		pattern += "**"
+	case !strings.Contains(pattern, "/"):
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "./"), "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// MatchAnyGlob reports whether name matches any of the patterns.
+func MatchAnyGlob(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments,
+// expanding "**" to zero or more segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			return matchDoubleStar(pattern[1:], name)
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// matchDoubleStar matches the pattern remaining after a "**" against
+// every suffix of name.
+func matchDoubleStar(rest, name []string) bool {
+	for i := len(name); i >= 0; i-- {
+		if matchSegments(rest, name[i:]) {
+			return true
+		}
+	}
+	return false
+}
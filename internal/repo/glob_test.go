@@ -0,0 +1,32 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/repo"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/cli/check.go", true},
+		{"*.go", "internal/cli/check.go.orig", false},
+		{"docs/", "docs/contracts/CONTRACT_CLI.md", true},
+		{"docs/", "internal/docs/a.md", false},
+		{"internal/**/*.go", "internal/cli/check.go", true},
+		{"internal/**/*.go", "internal/a.go", true},
+		{"internal/*.go", "internal/cli/check.go", false},
+		{"/go.mod", "go.mod", true},
+		{"./cmd/**", "cmd/bonsai/main.go", true},
+		{"**/testdata/**", "internal/diff/testdata/x.json", true},
+		{"api/*.proto", "api/v1/a.proto", false},
+	}
+	for _, tt := range tests {
+		if got := repo.MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
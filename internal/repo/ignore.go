@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile is the repo-root file, in gitignore syntax, listing paths
+// left out of repo trees, diff payloads, and diff profiles.
+const IgnoreFile = ".bonsaiignore"
+
+// Ignore matches repo paths against gitignore-syntax rules. A nil
+// Ignore matches nothing.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+// ignoreRule is one parsed pattern line.
+type ignoreRule struct {
+	glob    string // MatchGlob pattern
+	negate  bool   // "!pattern" re-includes
+	dirOnly bool   // "pattern/" matches directories only
+}
+
+// LoadIgnore reads dir/.bonsaiignore, when present, followed by the
+// extra patterns (config exclude:), which therefore take precedence.
+// On a read error the extra patterns are still returned.
+func LoadIgnore(dir string, extra []string) (*Ignore, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return ParseIgnore(extra), err
+	}
+	lines := strings.Split(string(data), "\n")
+	return ParseIgnore(append(lines, extra...)), nil
+}
+
+// ParseIgnore parses gitignore-syntax lines: blank lines and "#"
+// comments are skipped, "!" negates, a trailing "/" matches only
+// directories, a pattern containing "/" is anchored at the repo root,
+// and "**" matches any number of directories. Later rules win.
+// Unlike git, a file may be re-included inside an ignored directory.
+func ParseIgnore(lines []string) *Ignore {
+	ig := &Ignore{}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r ignoreRule
+		if r.negate = strings.HasPrefix(line, "!"); r.negate {
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`) // "\#" and "\!" escapes
+		if r.dirOnly = strings.HasSuffix(line, "/"); r.dirOnly {
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		r.glob = line
+		ig.rules = append(ig.rules, r)
+	}
+	return ig
+}
+
+// Match reports whether file, or a directory containing it, is ignored.
+func (ig *Ignore) Match(file string) bool {
+	if ig == nil {
+		return false
+	}
+	segs := strings.Split(file, "/")
+	ignored := false
+	for _, r := range ig.rules {
+		if r.matches(segs) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule matches the path or, as a directory,
+// any of its ancestors.
+func (r ignoreRule) matches(segs []string) bool {
+	for i := 1; i <= len(segs); i++ {
+		if i == len(segs) && r.dirOnly {
+			break
+		}
+		if MatchGlob(r.glob, strings.Join(segs[:i], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the files not ignored.
+func (ig *Ignore) Filter(files []string) []string {
+	return FilterPaths(files, func(f string) bool { return !ig.Match(f) })
+}
+
+// Selector returns a predicate keeping paths that are in scope (see
+// InScope) and not ignored.
+func Selector(prefixes []string, ig *Ignore) func(string) bool {
+	return func(f string) bool { return InScope(f, prefixes) && !ig.Match(f) }
+}
+
+// FilterPaths returns the files for which keep reports true.
+func FilterPaths(files []string, keep func(string) bool) []string {
+	var kept []string
+	for _, f := range files {
+		if keep(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,115 @@
+package repo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/gitutil"
+	"github.com/pithecene-io/bonsai/internal/repo"
+)
+
+func TestIgnore_Match(t *testing.T) {
+	ig := repo.ParseIgnore([]string{
+		"# generated code",
+		"",
+		"vendor/",
+		"*.pb.go",
+		"/build",
+		"db/migrations/**/*.sql",
+		"snapshots/",
+		"!snapshots/keep.snap",
+		`\#literal`,
+	})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"vendor/github.com/x/y.go", true},
+		{"third_party/vendor/z.go", true},
+		{"vendor", false}, // a file named vendor is not a directory
+		{"api/v1/service.pb.go", true},
+		{"api/v1/service.go", false},
+		{"build/out.bin", true},
+		{"tools/build/main.go", false}, // "/build" is anchored
+		{"db/migrations/2024/01/init.sql", true},
+		{"db/seeds/init.sql", false},
+		{"ui/snapshots/a.snap", true},
+		{"snapshots/keep.snap", false},
+		{"#literal", true},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := ig.Match(tt.path); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var none *repo.Ignore
+	if none.Match("vendor/x.go") {
+		t.Error("nil Ignore should match nothing")
+	}
+}
+
+func TestLoadIgnore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, repo.IgnoreFile), []byte("gen/\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ig, err := repo.LoadIgnore(dir, []string{"*.lock", "!gen/keep.go"})
+	if err != nil {
+		t.Fatalf("LoadIgnore: %v", err)
+	}
+	for path, want := range map[string]bool{
+		"gen/a.go":    true,
+		"gen/keep.go": false, // config patterns apply after the file
+		"Cargo.lock":  true,
+		"src/main.rs": false,
+	} {
+		if got := ig.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if _, err := repo.LoadIgnore(t.TempDir(), nil); err != nil {
+		t.Errorf("missing %s should not be an error: %v", repo.IgnoreFile, err)
+	}
+}
+
+func TestTree_HonoursIgnore(t *testing.T) {
+	dir := setupTestRepo(t)
+	for _, f := range []string{"vendor/lib.go", "src/main.go", "src/main_gen.go"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("package x\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, repo.IgnoreFile), []byte("vendor/\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := gitutil.Run(dir, "add", "vendor", "src"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+
+	tree, err := repo.TreeWithScope(dir, "src/", []string{"*_gen.go"})
+	if err != nil {
+		t.Fatalf("TreeWithScope: %v", err)
+	}
+	if len(tree) != 1 || tree[0] != "src/main.go" {
+		t.Errorf("TreeWithScope = %v, want [src/main.go]", tree)
+	}
+
+	tree, err = repo.Tree(dir, nil)
+	if err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+	for _, f := range tree {
+		if f == "vendor/lib.go" {
+			t.Errorf("Tree listed ignored %s: %v", f, tree)
+		}
+	}
+}
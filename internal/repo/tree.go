@@ -20,12 +20,25 @@ import (
 //
 //	find . -type f | sed 's|^\./||' | sort
 //
+// Paths matched by .bonsaiignore or the exclude patterns (config
+// exclude:) are left out.
+//
 // Reference: ai-skill.sh:156-161
-func Tree(dir string) ([]string, error) {
+func Tree(dir string, exclude []string) ([]string, error) {
+	ig, err := LoadIgnore(dir, exclude)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
 	if gitutil.IsInsideWorkTree(dir) {
-		return gitTree(dir)
+		files, err = gitTree(dir)
+	} else {
+		files, err = findTree(dir)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return findTree(dir)
+	return ig.Filter(files), nil
 }
 
 // gitTree lists files using git ls-files (tracked + untracked).
@@ -96,9 +109,10 @@ func findTree(dir string) ([]string, error) {
 
 // TreeWithScope returns the repository tree filtered by scope prefixes.
 // Each scope is a comma-separated path prefix. Files must start with
-// at least one prefix to be included.
-func TreeWithScope(dir, scope string) ([]string, error) {
-	full, err := Tree(dir)
+// at least one prefix to be included. Ignored paths are left out as
+// in Tree.
+func TreeWithScope(dir, scope string, exclude []string) ([]string, error) {
+	full, err := Tree(dir, exclude)
 	if err != nil {
 		return nil, err
 	}
@@ -107,20 +121,41 @@ func TreeWithScope(dir, scope string) ([]string, error) {
 		return full, nil
 	}
 
-	prefixes := strings.Split(scope, ",")
-	for i := range prefixes {
-		prefixes[i] = strings.TrimSpace(prefixes[i])
-	}
+	return FilterScope(full, ScopePrefixes(scope)), nil
+}
 
-	var filtered []string
-	for _, f := range full {
-		for _, p := range prefixes {
-			if strings.HasPrefix(f, p) {
-				filtered = append(filtered, f)
-				break
-			}
+// InScope reports whether file starts with at least one of prefixes.
+// Every file is in scope when prefixes is empty.
+func InScope(file string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(file, p) {
+			return true
 		}
 	}
+	return false
+}
+
+// FilterScope returns the files in scope, or files unchanged when
+// prefixes is empty.
+func FilterScope(files, prefixes []string) []string {
+	if len(prefixes) == 0 {
+		return files
+	}
+	return FilterPaths(files, func(f string) bool { return InScope(f, prefixes) })
+}
 
-	return filtered, nil
+// ScopePrefixes splits a comma-separated scope into trimmed path
+// prefixes. An empty scope yields nil.
+func ScopePrefixes(scope string) []string {
+	if scope == "" {
+		return nil
+	}
+	prefixes := strings.Split(scope, ",")
+	for i := range prefixes {
+		prefixes[i] = strings.TrimSpace(prefixes[i])
+	}
+	return prefixes
 }
@@ -0,0 +1,172 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit serializes the report as JUnit XML with one testsuite per
+// invocation and one testcase per skill. Skipped skills become
+// <skipped>, errored skills <error>, and failing skills <failure>
+// carrying their blocking and major findings. Elapsed time is reported
+// in seconds.
+func WriteJUnit(w io.Writer, r *orchestrator.Report, meta Meta) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(buildJUnit(r, meta)); err != nil {
+		return fmt.Errorf("marshal junit: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// buildJUnit converts a report into the JUnit object model.
+func buildJUnit(r *orchestrator.Report, meta Meta) junitTestSuites {
+	suite := junitTestSuite{
+		Name:      toolName + " " + r.Source,
+		Timestamp: r.Timestamp,
+		Properties: []junitProperty{
+			{Name: "source", Value: r.Source},
+			{Name: "blocking_failed", Value: fmt.Sprint(r.BlockingFailed)},
+		},
+	}
+	if r.FailOn != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "fail_on", Value: r.FailOn})
+	}
+
+	var totalMS float64
+	for i := range r.Results {
+		res := &r.Results[i]
+		tc := junitCaseFor(res, meta)
+		switch {
+		case tc.Skipped != nil:
+			suite.Skipped++
+		case tc.Error != nil:
+			suite.Errors++
+		case tc.Failure != nil:
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+		totalMS += res.Elapsed
+	}
+	suite.Tests = len(suite.Cases)
+	suite.Time = junitSeconds(totalMS)
+
+	return junitTestSuites{
+		Name:     toolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+}
+
+// junitCaseFor builds the testcase for a single skill result.
+func junitCaseFor(res *orchestrator.Result, meta Meta) junitTestCase {
+	tc := junitTestCase{
+		Name:      res.Name,
+		ClassName: junitClassName(res.Name, meta),
+		Time:      junitSeconds(res.Elapsed),
+		SystemOut: strings.Join(res.Details(""), "\n"),
+	}
+
+	switch {
+	case res.Status == "skipped":
+		tc.Skipped = &junitMessage{Message: res.SkippedReason}
+	case res.Status == "error" || res.Status == "timeout":
+		tc.Error = &junitMessage{Message: res.ErrorDetail, Type: res.Status, Body: res.ErrorDetail}
+	case res.Failed():
+		tc.Failure = &junitMessage{
+			Message: res.SummaryLine(),
+			Type:    junitFailureType(res),
+			Body:    strings.Join(junitFailureLines(res), "\n"),
+		}
+	}
+	return tc
+}
+
+// junitClassName groups skills by registry domain when known.
+func junitClassName(name string, meta Meta) string {
+	if info, ok := meta.Skills[name]; ok && info.Skill.Domain != "" {
+		return toolName + "." + info.Skill.Domain
+	}
+	return toolName
+}
+
+// junitFailureType distinguishes mandatory from advisory failures.
+func junitFailureType(res *orchestrator.Result) string {
+	if res.Mandatory {
+		return "mandatory"
+	}
+	return "non-mandatory"
+}
+
+// junitFailureLines returns the blocking and major findings of a result.
+func junitFailureLines(res *orchestrator.Result) []string {
+	var lines []string
+	for _, d := range res.BlockingDetails {
+		lines = append(lines, "blocking: "+d)
+	}
+	for _, d := range res.MajorDetails {
+		lines = append(lines, "major: "+d)
+	}
+	return lines
+}
+
+// junitSeconds formats a millisecond duration as JUnit seconds.
+func junitSeconds(ms float64) string {
+	return fmt.Sprintf("%.3f", ms/1000)
+}
@@ -0,0 +1,88 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+	"github.com/pithecene-io/bonsai/internal/registry"
+	"github.com/pithecene-io/bonsai/internal/report"
+)
+
+// junitDoc is the subset of JUnit XML inspected by these tests.
+type junitDoc struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Errors   int `xml:"errors,attr"`
+	Skipped  int `xml:"skipped,attr"`
+	Suites   []struct {
+		Cases []struct {
+			Name      string `xml:"name,attr"`
+			ClassName string `xml:"classname,attr"`
+			Time      string `xml:"time,attr"`
+			Skipped   *struct {
+				Message string `xml:"message,attr"`
+			} `xml:"skipped"`
+			Error *struct {
+				Message string `xml:"message,attr"`
+			} `xml:"error"`
+			Failure *struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"failure"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func TestWriteJUnit(t *testing.T) {
+	r := testReport()
+	r.Results = append(r.Results, orchestrator.Result{Name: "clean-skill", Status: "pass", Elapsed: 1500})
+
+	var buf bytes.Buffer
+	meta := report.Meta{Skills: map[string]report.SkillInfo{
+		"repo-convention-enforcer": {Skill: registry.Skill{Domain: "structural"}},
+	}}
+	if err := report.Write(&buf, report.FormatJUnit, r, meta); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml") {
+		t.Error("missing XML header")
+	}
+
+	var doc junitDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 1 || doc.Skipped != 1 {
+		t.Errorf("totals = tests:%d failures:%d errors:%d skipped:%d, want 4/1/1/1",
+			doc.Tests, doc.Failures, doc.Errors, doc.Skipped)
+	}
+
+	cases := doc.Suites[0].Cases
+	failing := cases[0]
+	if failing.ClassName != "bonsai.structural" {
+		t.Errorf("classname = %q, want bonsai.structural", failing.ClassName)
+	}
+	if failing.Failure == nil || failing.Failure.Type != "mandatory" {
+		t.Fatalf("failure = %+v, want mandatory failure", failing.Failure)
+	}
+	if !strings.Contains(failing.Failure.Body, "blocking: forbidden dir") ||
+		!strings.Contains(failing.Failure.Body, "major: naming drift") {
+		t.Errorf("failure body = %q", failing.Failure.Body)
+	}
+	if strings.Contains(failing.Failure.Body, "odd file") {
+		t.Error("failure body should not include warning findings")
+	}
+
+	if cases[1].Error == nil || cases[1].Error.Message != "boom" {
+		t.Errorf("error case = %+v", cases[1].Error)
+	}
+	if cases[2].Skipped == nil || cases[2].Skipped.Message != "requires_diff without --base" {
+		t.Errorf("skipped case = %+v", cases[2].Skipped)
+	}
+	if cases[3].Time != "1.500" {
+		t.Errorf("time = %q, want 1.500", cases[3].Time)
+	}
+}
@@ -0,0 +1,82 @@
+// Package report renders orchestrator reports into interchange formats
+// consumed by CI systems, code-scanning dashboards, and IDEs (SARIF,
+// JUnit XML).
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+	"github.com/pithecene-io/bonsai/internal/registry"
+)
+
+// Format identifies a report serialization.
+type Format string
+
+// Supported report formats.
+const (
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
+	FormatJUnit Format = "junit"
+)
+
+// formatFilenames maps each format to its default artifact filename.
+var formatFilenames = map[Format]string{
+	FormatJSON:  "ai-check.json",
+	FormatSARIF: "ai-check.sarif",
+	FormatJUnit: "ai-check.junit.xml",
+}
+
+// ParseFormat validates and returns a Format from a raw string.
+func ParseFormat(s string) (Format, error) {
+	f := Format(s)
+	if _, ok := formatFilenames[f]; !ok {
+		return "", fmt.Errorf("invalid format %q (valid: json, sarif, junit)", s)
+	}
+	return f, nil
+}
+
+// DefaultFilename returns the artifact filename used when no explicit
+// output path is given.
+func (f Format) DefaultFilename() string {
+	return formatFilenames[f]
+}
+
+// SkillInfo carries the registry entry and SKILL.md description for a
+// skill, used to describe rules in formats that support them.
+type SkillInfo struct {
+	Skill       registry.Skill
+	Description string
+}
+
+// Meta holds run-level metadata that is not part of the report itself.
+type Meta struct {
+	ToolVersion string               // bonsai version
+	Skills      map[string]SkillInfo // keyed by skill name; may be nil
+}
+
+// Write serializes the report to w in the given format.
+func Write(w io.Writer, f Format, r *orchestrator.Report, meta Meta) error {
+	switch f {
+	case FormatJSON:
+		return writeJSON(w, r)
+	case FormatSARIF:
+		return WriteSARIF(w, r, meta)
+	case FormatJUnit:
+		return WriteJUnit(w, r, meta)
+	default:
+		return fmt.Errorf("unsupported format %q", f)
+	}
+}
+
+// writeJSON writes the bespoke report JSON (ai-check.json schema).
+func writeJSON(w io.Writer, r *orchestrator.Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,302 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// SARIF 2.1.0 constants.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "bonsai"
+	toolURI      = "https://github.com/pithecene-io/bonsai"
+)
+
+// sarifLevels maps bonsai severities to SARIF result levels.
+var sarifLevels = map[string]string{
+	"blocking": "error",
+	"major":    "warning",
+	"warning":  "warning",
+	"info":     "note",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+	Properties  map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level          string             `json:"level"`
+	Message        sarifMessage       `json:"message"`
+	Descriptor     sarifDescriptorRef `json:"descriptor"`
+	AssociatedRule sarifDescriptorRef `json:"associatedRule"`
+}
+
+type sarifDescriptorRef struct {
+	ID    string `json:"id"`
+	Index *int   `json:"index,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID        string             `json:"ruleId"`
+	RuleIndex     int                `json:"ruleIndex"`
+	Level         string             `json:"level"`
+	Message       sarifMessage       `json:"message"`
+	Locations     []sarifLocation    `json:"locations,omitempty"`
+	BaselineState string             `json:"baselineState,omitempty"`
+	Suppressions  []sarifSuppression `json:"suppressions,omitempty"`
+	Properties    map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// WriteSARIF serializes the report as a SARIF 2.1.0 log with one run
+// per invocation, one rule per skill, and one result per finding.
+// Errored and skipped skills are recorded as tool execution
+// notifications rather than results.
+func WriteSARIF(w io.Writer, r *orchestrator.Report, meta Meta) error {
+	log := buildSARIF(r, meta)
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sarif: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// buildSARIF converts a report into the SARIF object model.
+func buildSARIF(r *orchestrator.Report, meta Meta) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        meta.ToolVersion,
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+		Properties: map[string]any{
+			"source":          r.Source,
+			"timestamp":       r.Timestamp,
+			"blocking_failed": r.BlockingFailed,
+		},
+	}
+	if r.FailOn != "" {
+		run.Properties["fail_on"] = r.FailOn
+	}
+
+	inv := sarifInvocation{ExecutionSuccessful: true}
+	for i := range r.Results {
+		res := &r.Results[i]
+		ruleIndex := len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(res, meta))
+
+		if n, ok := sarifNotificationFor(res, ruleIndex); ok {
+			inv.ToolExecutionNotifications = append(inv.ToolExecutionNotifications, n)
+			continue
+		}
+		run.Results = append(run.Results, sarifResultsFor(res, ruleIndex, r.BaselineUsed)...)
+	}
+	run.Invocations = []sarifInvocation{inv}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifRuleFor builds the reporting descriptor for a skill.
+func sarifRuleFor(res *orchestrator.Result, meta Meta) sarifRule {
+	rule := sarifRule{
+		ID:   res.Name,
+		Name: res.Name,
+		DefaultConfiguration: sarifConfiguration{
+			Level: "warning",
+		},
+		Properties: map[string]any{"mandatory": res.Mandatory},
+	}
+	if res.Mandatory {
+		rule.DefaultConfiguration.Level = "error"
+	}
+
+	info, ok := meta.Skills[res.Name]
+	if !ok {
+		return rule
+	}
+	if info.Description != "" {
+		rule.ShortDescription = &sarifMessage{Text: info.Description}
+	}
+	if info.Skill.Domain != "" {
+		rule.Properties["domain"] = info.Skill.Domain
+		rule.Properties["tags"] = []string{info.Skill.Domain}
+	}
+	if info.Skill.Cost != "" {
+		rule.Properties["cost"] = string(info.Skill.Cost)
+	}
+	return rule
+}
+
+// sarifNotificationFor returns a notification for skills that produced
+// no findings because they errored, timed out, or were skipped.
+func sarifNotificationFor(res *orchestrator.Result, ruleIndex int) (sarifNotification, bool) {
+	ref := sarifDescriptorRef{ID: res.Name, Index: &ruleIndex}
+	switch res.Status {
+	case "error":
+		msg := "skill execution failed"
+		if res.ErrorDetail != "" {
+			msg += ": " + res.ErrorDetail
+		}
+		return sarifNotification{
+			Level:          "error",
+			Message:        sarifMessage{Text: msg},
+			Descriptor:     sarifDescriptorRef{ID: "skill-error"},
+			AssociatedRule: ref,
+		}, true
+	case "timeout":
+		return sarifNotification{
+			Level:          "error",
+			Message:        sarifMessage{Text: "skill timed out: " + res.ErrorDetail},
+			Descriptor:     sarifDescriptorRef{ID: "skill-timeout"},
+			AssociatedRule: ref,
+		}, true
+	case "skipped":
+		return sarifNotification{
+			Level:          "note",
+			Message:        sarifMessage{Text: "skill skipped: " + res.SkippedReason},
+			Descriptor:     sarifDescriptorRef{ID: "skill-skipped"},
+			AssociatedRule: ref,
+		}, true
+	}
+	return sarifNotification{}, false
+}
+
+// sarifResultsFor returns one SARIF result per finding. Findings matched
+// by the findings baseline are included with baselineState "unchanged"
+// so dashboards can hide them without losing track of them; when the
+// run used a baseline, all other findings are "new". Findings silenced
+// by inline bonsai:ignore directives carry an "inSource" suppression
+// with the directive's justification.
+func sarifResultsFor(res *orchestrator.Result, ruleIndex int, baselineUsed bool) []sarifResult {
+	state := ""
+	if baselineUsed {
+		state = "new"
+	}
+	var results []sarifResult
+	for _, sev := range orchestrator.Severities {
+		for _, f := range res.FindingsFor(sev) {
+			r := sarifResultFor(res.Name, ruleIndex, sev, f)
+			r.BaselineState = state
+			results = append(results, r)
+		}
+	}
+	for _, bf := range res.BaselinedFindings {
+		r := sarifResultFor(res.Name, ruleIndex, bf.Severity, bf.Finding)
+		r.BaselineState = "unchanged"
+		results = append(results, r)
+	}
+	for _, sf := range res.SuppressedFindings {
+		r := sarifResultFor(res.Name, ruleIndex, sf.Severity, sf.Finding)
+		r.BaselineState = state
+		r.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: sf.Reason}}
+		results = append(results, r)
+	}
+	return results
+}
+
+// sarifResultFor builds a single SARIF result from a finding.
+func sarifResultFor(ruleID string, ruleIndex int, severity string, f skill.Finding) sarifResult {
+	result := sarifResult{
+		RuleID:     ruleID,
+		RuleIndex:  ruleIndex,
+		Level:      sarifLevels[severity],
+		Message:    sarifMessage{Text: f.Message},
+		Properties: map[string]any{"severity": severity},
+	}
+	if f.Rule != "" {
+		result.Properties["rule"] = f.Rule
+	}
+	if f.HasLocation() {
+		result.Locations = []sarifLocation{sarifLocationFor(f)}
+	}
+	return result
+}
+
+// sarifLocationFor maps a finding's repo-relative path and line range to
+// a SARIF physical location rooted at %SRCROOT%.
+func sarifLocationFor(f skill.Finding) sarifLocation {
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Path), URIBaseID: "%SRCROOT%"},
+	}}
+	if f.StartLine > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.StartLine, EndLine: f.EndLine}
+	}
+	return loc
+}
@@ -0,0 +1,290 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/orchestrator"
+	"github.com/pithecene-io/bonsai/internal/registry"
+	"github.com/pithecene-io/bonsai/internal/report"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// sarifDoc is the subset of SARIF 2.1.0 inspected by these tests.
+type sarifDoc struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+				Rules   []struct {
+					ID               string `json:"id"`
+					ShortDescription *struct {
+						Text string `json:"text"`
+					} `json:"shortDescription"`
+					DefaultConfiguration struct {
+						Level string `json:"level"`
+					} `json:"defaultConfiguration"`
+					Properties map[string]any `json:"properties"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Invocations []struct {
+			ToolExecutionNotifications []struct {
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"toolExecutionNotifications"`
+		} `json:"invocations"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex int    `json:"ruleIndex"`
+			Level     string `json:"level"`
+			Message   struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region *struct {
+						StartLine int `json:"startLine"`
+						EndLine   int `json:"endLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			Properties    map[string]any `json:"properties"`
+			BaselineState string         `json:"baselineState"`
+			Suppressions  []struct {
+				Kind          string `json:"kind"`
+				Justification string `json:"justification"`
+			} `json:"suppressions"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func testReport() *orchestrator.Report {
+	return &orchestrator.Report{
+		Source:    "bundle:default",
+		Timestamp: "20260101-120000",
+		Results: []orchestrator.Result{
+			{
+				Name:            "repo-convention-enforcer",
+				Status:          "fail",
+				Mandatory:       true,
+				ExitCode:        1,
+				BlockingDetails: []string{"forbidden dir"},
+				MajorDetails:    []string{"naming drift"},
+				WarningDetails:  []string{"odd file"},
+				InfoDetails:     []string{"looks fine"},
+			},
+			{Name: "broken-skill", Status: "error", ExitCode: 1, ErrorDetail: "boom"},
+			{Name: "diff-skill", Status: "skipped", SkippedReason: "requires_diff without --base"},
+		},
+	}
+}
+
+func writeSARIF(t *testing.T, meta report.Meta) sarifDoc {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := report.Write(&buf, report.FormatSARIF, testReport(), meta); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return doc
+}
+
+func TestWriteSARIF_RulesAndResults(t *testing.T) {
+	doc := writeSARIF(t, report.Meta{
+		ToolVersion: "1.2.3",
+		Skills: map[string]report.SkillInfo{
+			"repo-convention-enforcer": {
+				Skill:       registry.Skill{Name: "repo-convention-enforcer", Domain: "structural", Cost: registry.CostCheap, Mandatory: true},
+				Description: "Enforces repo conventions.",
+			},
+		},
+	})
+
+	if doc.Version != "2.1.0" {
+		t.Errorf("version = %q, want 2.1.0", doc.Version)
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("runs = %d, want 1", len(doc.Runs))
+	}
+	run := doc.Runs[0]
+	if run.Tool.Driver.Name != "bonsai" || run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("driver = %s@%s", run.Tool.Driver.Name, run.Tool.Driver.Version)
+	}
+
+	if len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("rules = %d, want 3 (one per skill)", len(run.Tool.Driver.Rules))
+	}
+	rule := run.Tool.Driver.Rules[0]
+	if rule.ShortDescription == nil || rule.ShortDescription.Text != "Enforces repo conventions." {
+		t.Errorf("shortDescription = %+v", rule.ShortDescription)
+	}
+	if rule.DefaultConfiguration.Level != "error" {
+		t.Errorf("mandatory rule level = %q, want error", rule.DefaultConfiguration.Level)
+	}
+	if rule.Properties["domain"] != "structural" || rule.Properties["cost"] != "cheap" {
+		t.Errorf("rule properties = %v", rule.Properties)
+	}
+
+	wantLevels := []string{"error", "warning", "warning", "note"}
+	if len(run.Results) != len(wantLevels) {
+		t.Fatalf("results = %d, want %d", len(run.Results), len(wantLevels))
+	}
+	for i, want := range wantLevels {
+		if run.Results[i].Level != want {
+			t.Errorf("result[%d].level = %q, want %q", i, run.Results[i].Level, want)
+		}
+		if run.Results[i].RuleID != "repo-convention-enforcer" || run.Results[i].RuleIndex != 0 {
+			t.Errorf("result[%d] rule = %s/%d", i, run.Results[i].RuleID, run.Results[i].RuleIndex)
+		}
+	}
+}
+
+func TestWriteSARIF_ErrorAndSkippedAsNotifications(t *testing.T) {
+	doc := writeSARIF(t, report.Meta{})
+
+	notes := doc.Runs[0].Invocations[0].ToolExecutionNotifications
+	if len(notes) != 2 {
+		t.Fatalf("notifications = %d, want 2", len(notes))
+	}
+	if notes[0].Level != "error" || notes[0].Message.Text != "skill execution failed: boom" {
+		t.Errorf("error notification = %+v", notes[0])
+	}
+	if notes[1].Level != "note" {
+		t.Errorf("skipped notification level = %q, want note", notes[1].Level)
+	}
+}
+
+func TestWriteSARIF_Locations(t *testing.T) {
+	r := testReport()
+	r.Results[0].BlockingFindings = []skill.Finding{
+		{Message: "forbidden dir", Path: "tmp/x.go", StartLine: 4, EndLine: 9, Rule: "no-tmp"},
+	}
+	r.Results[0].MajorFindings = []skill.Finding{{Message: "naming drift"}}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, report.FormatSARIF, r, report.Meta{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	results := doc.Runs[0].Results
+	blocking := results[0]
+	if blocking.Message.Text != "forbidden dir" || blocking.Properties["rule"] != "no-tmp" {
+		t.Errorf("blocking result = %+v", blocking)
+	}
+	if len(blocking.Locations) != 1 {
+		t.Fatalf("locations = %d, want 1", len(blocking.Locations))
+	}
+	phys := blocking.Locations[0].PhysicalLocation
+	if phys.ArtifactLocation.URI != "tmp/x.go" || phys.Region == nil ||
+		phys.Region.StartLine != 4 || phys.Region.EndLine != 9 {
+		t.Errorf("physicalLocation = %+v", phys)
+	}
+	if len(results[1].Locations) != 0 {
+		t.Errorf("message-only finding should have no locations, got %+v", results[1].Locations)
+	}
+	// Warning and info fall back to detail lines when no structured findings exist.
+	if results[2].Message.Text != "odd file" {
+		t.Errorf("fallback result = %q, want odd file", results[2].Message.Text)
+	}
+}
+
+func TestWriteSARIF_BaselinedFindings(t *testing.T) {
+	r := testReport()
+	r.Results[0].BaselinedFindings = []orchestrator.BaselinedFinding{
+		{Severity: "blocking", Finding: skill.Finding{Message: "legacy"}},
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, report.FormatSARIF, r, report.Meta{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	results := doc.Runs[0].Results
+	last := results[len(results)-1]
+	if last.Message.Text != "legacy" || last.BaselineState != "unchanged" || last.Level != "error" {
+		t.Errorf("baselined result = %+v", last)
+	}
+	if results[0].BaselineState != "" {
+		t.Errorf("active result baselineState = %q, want empty without baseline_used", results[0].BaselineState)
+	}
+
+	// With a baseline in use, every other result is new.
+	r.BaselineUsed = true
+	buf.Reset()
+	if err := report.Write(&buf, report.FormatSARIF, r, report.Meta{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	doc = sarifDoc{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	results = doc.Runs[0].Results
+	for _, res := range results[:len(results)-1] {
+		if res.BaselineState != "new" {
+			t.Errorf("active result %q baselineState = %q, want new", res.Message.Text, res.BaselineState)
+		}
+	}
+	if last := results[len(results)-1]; last.BaselineState != "unchanged" {
+		t.Errorf("baselined result baselineState = %q, want unchanged", last.BaselineState)
+	}
+}
+
+func TestWriteSARIF_SuppressedFindings(t *testing.T) {
+	r := testReport()
+	r.Results[0].SuppressedFindings = []orchestrator.SuppressedFinding{
+		{
+			Severity: "blocking", Reason: "test fixture", Directive: "a.go:2",
+			Finding: skill.Finding{Message: "secret", Path: "a.go", StartLine: 3},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, report.FormatSARIF, r, report.Meta{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	results := doc.Runs[0].Results
+	last := results[len(results)-1]
+	if last.Message.Text != "secret" || len(last.Suppressions) != 1 {
+		t.Fatalf("suppressed result = %+v", last)
+	}
+	if s := last.Suppressions[0]; s.Kind != "inSource" || s.Justification != "test fixture" {
+		t.Errorf("suppression = %+v", s)
+	}
+	if len(results[0].Suppressions) != 0 {
+		t.Errorf("active result suppressions = %+v, want none", results[0].Suppressions)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if _, err := report.ParseFormat("sarif"); err != nil {
+		t.Errorf("ParseFormat(sarif): %v", err)
+	}
+	if _, err := report.ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
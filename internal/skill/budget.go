@@ -0,0 +1,152 @@
+package skill
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pithecene-io/bonsai/internal/prompt"
+	"github.com/pithecene-io/bonsai/internal/repo"
+)
+
+// outputReserve is the share of the context window, in tokens, held
+// back for the model's response.
+const outputReserve = 8192
+
+// promptOverhead approximates the tokens of the fixed user-prompt
+// scaffolding (instructions and section labels).
+const promptOverhead = 100
+
+// generatedFiles names lockfiles and other machine-written files whose
+// diffs are dropped first when a prompt exceeds its budget.
+var generatedFiles = []string{
+	"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml",
+	"Cargo.lock", "poetry.lock", "Gemfile.lock", "composer.lock",
+	"*.min.js", "*.pb.go", "*_generated.go", "*.gen.go",
+}
+
+// fitUserPrompt degrades the tree and diff in opts until the user
+// prompt fits budget tokens, and describes what was elided. Lockfile
+// and generated-file diffs are dropped first, then large directories in
+// the tree are collapsed into file counts. A diff that still does not
+// fit is left to SplitDiff by tightening opts.DiffBudget. A budget
+// <= 0 leaves opts unchanged.
+func fitUserPrompt(opts RunOpts, budget int) (RunOpts, []string) {
+	if budget <= 0 || userPromptTokens(opts) <= budget {
+		return opts, nil
+	}
+
+	var elided []string
+	if diff, dropped := dropGeneratedDiffs(opts.DiffPayload); len(dropped) > 0 {
+		opts.DiffPayload = diff
+		elided = append(elided, fmt.Sprintf("diff of %d lockfile/generated file(s): %s",
+			len(dropped), strings.Join(dropped, ", ")))
+	}
+
+	treeBudget := max(budget-prompt.EstimateTokens(opts.DiffPayload)-promptOverhead, budget/4)
+	if prompt.EstimateTokens(opts.RepoTree) > treeBudget {
+		lines, n := repo.CollapseTree(strings.Split(opts.RepoTree, "\n"), treeBudget*prompt.BytesPerToken)
+		if n > 0 {
+			opts.RepoTree = strings.Join(lines, "\n")
+			elided = append(elided, fmt.Sprintf("repo tree: %d directories collapsed to file counts", n))
+		}
+	}
+
+	// Whatever the tree leaves bounds each diff chunk; never less than
+	// half the budget, so a tree that could not shrink still leaves
+	// chunks of useful size.
+	diffBytes := max(budget-prompt.EstimateTokens(opts.RepoTree)-promptOverhead, budget/2) * prompt.BytesPerToken
+	if len(opts.DiffPayload) > diffBytes && (opts.DiffBudget <= 0 || diffBytes < opts.DiffBudget) {
+		opts.DiffBudget = diffBytes
+	}
+	return opts, elided
+}
+
+// userPromptTokens estimates the tokens of the user prompt for opts.
+func userPromptTokens(opts RunOpts) int {
+	return prompt.EstimateTokens(opts.RepoTree) + prompt.EstimateTokens(opts.DiffPayload) + promptOverhead
+}
+
+// dropGeneratedDiffs removes the file sections of lockfiles and
+// generated files (by name, or by a "// Code generated ... DO NOT EDIT."
+// header) from a unified diff and returns the dropped paths.
+func dropGeneratedDiffs(diff string) (string, []string) {
+	var kept strings.Builder
+	var dropped []string
+	for _, section := range splitBefore(diff, "diff --git ") {
+		file := diffSectionPath(section)
+		if file != "" && isGenerated(file, section) {
+			dropped = append(dropped, file)
+			continue
+		}
+		kept.WriteString(section)
+	}
+	return kept.String(), dropped
+}
+
+// diffSectionPath returns the b/ path from a section's "diff --git"
+// header, or "" for text outside any file section.
+func diffSectionPath(section string) string {
+	header, _, _ := strings.Cut(section, "\n")
+	_, b, ok := strings.Cut(header, " b/")
+	if !strings.HasPrefix(header, "diff --git ") || !ok {
+		return ""
+	}
+	return b
+}
+
+// isGenerated reports whether a diff section belongs to a lockfile or
+// generated file.
+func isGenerated(file, section string) bool {
+	base := path.Base(file)
+	for _, pattern := range generatedFiles {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return hasGeneratedMarker(section)
+}
+
+// generatedMarker is the Go convention for marking generated files
+// (go.dev/s/generatedcode), which many other generators follow.
+var generatedMarker = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// generatedMarkerLines bounds how far into a file the marker is looked
+// for; generators write it in the file header.
+const generatedMarkerLines = 20
+
+// hasGeneratedMarker reports whether one of the first lines of the
+// section's new file is the generated-code marker. Removed lines and
+// mentions deeper in a file, such as in prose, string literals, or
+// tests, do not count.
+func hasGeneratedMarker(section string) bool {
+	num := 0 // new-file line number; 0 before the first hunk
+	for line := range strings.Lines(section) {
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			num = max(hunkNewStart(line), 1)
+		case num == 0, strings.HasPrefix(line, "-"):
+		case num > generatedMarkerLines:
+			return false
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, " "):
+			if generatedMarker.MatchString(line[1:]) {
+				return true
+			}
+			num++
+		}
+	}
+	return false
+}
+
+// hunkNewStart returns the first new-file line of a hunk header such as
+// "@@ -10,4 +12,6 @@", or 0 when it cannot be parsed.
+func hunkNewStart(header string) int {
+	_, after, _ := strings.Cut(header, " +")
+	digits, _, _ := strings.Cut(after, ",")
+	digits, _, _ = strings.Cut(digits, " ")
+	n, _ := strconv.Atoi(digits)
+	return n
+}
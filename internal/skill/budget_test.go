@@ -0,0 +1,161 @@
+package skill_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/prompt"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+func TestRunner_Run_ElidesToFitContextWindow(t *testing.T) {
+	var prompts []string
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateFunc: func(_ context.Context, _, userPrompt string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			prompts = append(prompts, userPrompt)
+			return `{"skill":"test-skill","version":"v1","status":"pass","blocking":[],"major":[],"warning":[],"info":[]}`, nil
+		},
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""))
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill.", OutputSchema: `{"type":"object"}`}
+
+	// A lockfile diff alone overflows the window; the vendored tree
+	// needs collapsing once the remaining diff is accounted for.
+	model := agent.Model("haiku")
+	window := agent.DefaultContextWindow
+	lockfile := fileDiff("go.sum", 1, window*prompt.BytesPerToken/20)
+	var tree []string
+	for i := range window * prompt.BytesPerToken / 25 {
+		tree = append(tree, fmt.Sprintf("vendor/example.com/mod/file%06d.go", i))
+	}
+	tree = append(tree, "main.go")
+
+	out, err := runner.Run(t.Context(), def, skill.RunOpts{
+		RepoTree:    strings.Join(tree, "\n"),
+		DiffPayload: fileDiff("main.go", 1, 3) + lockfile,
+		BaseRef:     "main",
+		Model:       model,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(prompts) != 1 {
+		t.Fatalf("agent calls = %d, want 1", len(prompts))
+	}
+	p := prompts[0]
+	if strings.Contains(p, "b/go.sum") || !strings.Contains(p, "b/main.go") {
+		t.Error("lockfile diff should be dropped and the source diff kept")
+	}
+	if !strings.Contains(p, "vendor/example.com/mod/ (") {
+		t.Error("vendored directory should be collapsed to a file count")
+	}
+	if !strings.Contains(p, "Elided to fit the prompt budget") {
+		t.Errorf("prompt should note the elisions:\n%.500s", p)
+	}
+	if len(out.Elided) != 2 || !strings.Contains(out.Elided[0], "go.sum") || !strings.Contains(out.Elided[1], "repo tree") {
+		t.Errorf("Elided = %v, want the lockfile and tree elisions", out.Elided)
+	}
+	if got := prompt.EstimateTokens(p); got > window {
+		t.Errorf("user prompt is ~%d tokens, over the %d-token window", got, window)
+	}
+}
+
+func TestRunner_Run_NoElisionWithinBudget(t *testing.T) {
+	var userPrompt string
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateFunc: func(_ context.Context, _, u string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			userPrompt = u
+			return `{"skill":"test-skill","version":"v1","status":"pass","blocking":[],"major":[],"warning":[],"info":[]}`, nil
+		},
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""))
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill.", OutputSchema: `{"type":"object"}`}
+
+	out, err := runner.Run(t.Context(), def, skill.RunOpts{
+		RepoTree:    "go.sum\nmain.go",
+		DiffPayload: fileDiff("go.sum", 1, 3),
+		BaseRef:     "main",
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(out.Elided) != 0 || strings.Contains(userPrompt, "Elided") || !strings.Contains(userPrompt, "b/go.sum") {
+		t.Errorf("small prompt should be sent whole; Elided = %v", out.Elided)
+	}
+}
+
+func TestRunner_Run_ConfiguredContextWindow(t *testing.T) {
+	var userPrompt string
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateFunc: func(_ context.Context, _, u string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			userPrompt = u
+			return `{"skill":"test-skill","version":"v1","status":"pass","blocking":[],"major":[],"warning":[],"info":[]}`, nil
+		},
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""))
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill.", OutputSchema: `{"type":"object"}`}
+
+	// The lockfile fits the default window but not the configured 32k
+	// one.
+	const window = 32_000
+	out, err := runner.Run(t.Context(), def, skill.RunOpts{
+		RepoTree:      "go.sum\nmain.go",
+		DiffPayload:   fileDiff("main.go", 1, 3) + fileDiff("go.sum", 1, window*prompt.BytesPerToken/20),
+		BaseRef:       "main",
+		Model:         agent.Model("haiku"),
+		ContextWindow: window,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(out.Elided) != 1 || strings.Contains(userPrompt, "b/go.sum") {
+		t.Errorf("lockfile should be elided under the configured window; Elided = %v", out.Elided)
+	}
+}
+
+func TestRunner_Run_GeneratedMarkerOnlyInFileHeader(t *testing.T) {
+	var userPrompt string
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateFunc: func(_ context.Context, _, u string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			userPrompt = u
+			return `{"skill":"test-skill","version":"v1","status":"pass","blocking":[],"major":[],"warning":[],"info":[]}`, nil
+		},
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""))
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill.", OutputSchema: `{"type":"object"}`}
+
+	generated := "diff --git a/api/client.go b/api/client.go\n--- /dev/null\n+++ b/api/client.go\n" +
+		"@@ -0,0 +1,3 @@\n+// Code generated by protoc-gen-go. DO NOT EDIT.\n+\n+package api\n"
+	// A hand-written file that removes the marker near the top and
+	// mentions it in a string literal further down is not generated.
+	handWritten := "diff --git a/gen/marker.go b/gen/marker.go\n--- a/gen/marker.go\n+++ b/gen/marker.go\n" +
+		"@@ -1,2 +1,1 @@\n-// Code generated by hand. DO NOT EDIT.\n package gen\n" +
+		"@@ -40,0 +40,1 @@\n+// Code generated by tool. DO NOT EDIT.\n"
+
+	const window = 32_000
+	out, err := runner.Run(t.Context(), def, skill.RunOpts{
+		RepoTree:      "api/client.go\ngen/marker.go\ngo.sum",
+		DiffPayload:   generated + handWritten + fileDiff("go.sum", 1, window*prompt.BytesPerToken/20),
+		BaseRef:       "main",
+		Model:         agent.Model("haiku"),
+		ContextWindow: window,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.Contains(userPrompt, "b/api/client.go") {
+		t.Error("file with a generated-code header should be dropped")
+	}
+	if !strings.Contains(userPrompt, "b/gen/marker.go") {
+		t.Errorf("hand-written file mentioning the marker should be kept; Elided = %v", out.Elided)
+	}
+}
@@ -0,0 +1,157 @@
+package skill
+
+import (
+	"slices"
+	"strings"
+)
+
+// SplitDiff splits a unified diff into chunks of at most budget bytes,
+// breaking on file boundaries where possible and on hunk boundaries
+// within an oversized file (each hunk chunk repeats the file header).
+// A single hunk larger than budget is split on line boundaries. A
+// budget <= 0, or a diff already within it, yields the diff unchanged.
+func SplitDiff(diff string, budget int) []string {
+	if budget <= 0 || len(diff) <= budget {
+		return []string{diff}
+	}
+
+	var chunks []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, piece := range diffPieces(diff, budget) {
+		if cur.Len()+len(piece) > budget {
+			flush()
+		}
+		cur.WriteString(piece)
+	}
+	flush()
+	return chunks
+}
+
+// diffPieces breaks a diff into indivisible units no larger than budget
+// (bar a header that alone exceeds it): whole files, or a file's header
+// plus one hunk or part of a hunk.
+func diffPieces(diff string, budget int) []string {
+	var pieces []string
+	for _, file := range splitBefore(diff, "diff --git ") {
+		if len(file) <= budget {
+			pieces = append(pieces, file)
+			continue
+		}
+		hunks := splitBefore(file, "@@ ")
+		header, hunks := hunks[0], hunks[1:]
+		if len(hunks) == 0 {
+			pieces = append(pieces, splitLines(header, "", budget)...)
+			continue
+		}
+		for _, h := range hunks {
+			pieces = append(pieces, splitLines(h, header, budget)...)
+		}
+	}
+	return pieces
+}
+
+// splitBefore splits s into segments that each begin at a line starting
+// with marker; text before the first marker forms its own segment.
+func splitBefore(s, marker string) []string {
+	var segs []string
+	start := 0
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], marker) && i > start && s[i-1] == '\n' {
+			segs = append(segs, s[start:i])
+			start = i
+		}
+		next := strings.IndexByte(s[i:], '\n')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return append(segs, s[start:])
+}
+
+// splitLines prefixes header to text, splitting text on line
+// boundaries so each piece (header included) fits budget where the
+// lines allow.
+func splitLines(text, header string, budget int) []string {
+	if len(header)+len(text) <= budget {
+		return []string{header + text}
+	}
+	var pieces []string
+	var cur strings.Builder
+	cur.WriteString(header)
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if cur.Len() > len(header) && cur.Len()+len(line) > budget {
+			pieces = append(pieces, cur.String())
+			cur.Reset()
+			cur.WriteString(header)
+		}
+		cur.WriteString(line)
+	}
+	if cur.Len() > len(header) {
+		pieces = append(pieces, cur.String())
+	}
+	return pieces
+}
+
+// MergeOutputs reduces the outputs of one skill evaluated over several
+// diff chunks into a single output: findings and notes are unioned
+// without duplicates, the status is fail if any chunk failed, and
+// details keep the first value seen for each key.
+func MergeOutputs(outputs []*Output) *Output {
+	if len(outputs) == 0 {
+		return nil
+	}
+	merged := &Output{
+		Skill:    outputs[0].Skill,
+		Version:  outputs[0].Version,
+		Status:   "pass",
+		Blocking: []Finding{},
+		Major:    []Finding{},
+		Warning:  []Finding{},
+		Info:     []Finding{},
+		Cached:   true,
+	}
+	for _, o := range outputs {
+		if o.Status == "fail" {
+			merged.Status = "fail"
+		}
+		merged.Blocking = appendNew(merged.Blocking, o.Blocking...)
+		merged.Major = appendNew(merged.Major, o.Major...)
+		merged.Warning = appendNew(merged.Warning, o.Warning...)
+		merged.Info = appendNew(merged.Info, o.Info...)
+		merged.Notes = appendNew(merged.Notes, o.Notes...)
+		merged.Details = mergeDetails(merged.Details, o.Details)
+		merged.Cached = merged.Cached && o.Cached
+		merged.Repairs += o.Repairs
+	}
+	return merged
+}
+
+// appendNew appends the elements of add not already in dst.
+func appendNew[T comparable](dst []T, add ...T) []T {
+	for _, v := range add {
+		if !slices.Contains(dst, v) {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+// mergeDetails copies keys of src missing from dst.
+func mergeDetails(dst, src map[string]any) map[string]any {
+	for k, v := range src {
+		if dst == nil {
+			dst = make(map[string]any, len(src))
+		}
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+	return dst
+}
@@ -0,0 +1,147 @@
+package skill_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/prompt"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// fileDiff builds a one-file unified diff with the given hunks, each
+// adding lines lines.
+func fileDiff(name string, hunks, lines int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", name, name, name, name)
+	for h := range hunks {
+		fmt.Fprintf(&b, "@@ -%d,0 +%d,%d @@\n", h*100, h*100, lines)
+		for l := range lines {
+			fmt.Fprintf(&b, "+line %d of hunk %d\n", l, h)
+		}
+	}
+	return b.String()
+}
+
+func TestSplitDiff_WithinBudget(t *testing.T) {
+	diff := fileDiff("a.go", 1, 3)
+	if got := skill.SplitDiff(diff, 0); len(got) != 1 || got[0] != diff {
+		t.Errorf("budget 0: got %d chunks, want the diff unchanged", len(got))
+	}
+	if got := skill.SplitDiff(diff, len(diff)); len(got) != 1 || got[0] != diff {
+		t.Errorf("exact budget: got %d chunks, want the diff unchanged", len(got))
+	}
+}
+
+func TestSplitDiff_FileBoundaries(t *testing.T) {
+	a, b, c := fileDiff("a.go", 1, 5), fileDiff("b.go", 1, 5), fileDiff("c.go", 1, 5)
+	chunks := skill.SplitDiff(a+b+c, len(a)+len(b))
+
+	if len(chunks) != 2 || chunks[0] != a+b || chunks[1] != c {
+		t.Fatalf("chunks = %q, want [a+b, c]", chunks)
+	}
+}
+
+func TestSplitDiff_HunkBoundariesRepeatHeader(t *testing.T) {
+	big := fileDiff("big.go", 3, 20)
+	budget := len(big) / 2
+	chunks := skill.SplitDiff(big, budget)
+
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want the file split", len(chunks))
+	}
+	for i, c := range chunks {
+		if len(c) > budget {
+			t.Errorf("chunk %d is %d bytes, over budget %d", i, len(c), budget)
+		}
+		if !strings.HasPrefix(c, "diff --git a/big.go b/big.go\n") {
+			t.Errorf("chunk %d does not start with the file header:\n%s", i, c)
+		}
+	}
+	var hunks int
+	for _, c := range chunks {
+		hunks += strings.Count(c, "\n@@ ")
+	}
+	if hunks != 3 {
+		t.Errorf("hunk headers across chunks = %d, want 3", hunks)
+	}
+}
+
+func TestSplitDiff_OversizedHunkSplitsOnLines(t *testing.T) {
+	big := fileDiff("big.go", 1, 200)
+	chunks := skill.SplitDiff(big, 1024)
+
+	var added int
+	for i, c := range chunks {
+		if len(c) > 1024 {
+			t.Errorf("chunk %d is %d bytes, over budget", i, len(c))
+		}
+		added += strings.Count(c, "\n+line ")
+	}
+	if added != 200 {
+		t.Errorf("added lines across chunks = %d, want 200", added)
+	}
+}
+
+func TestMergeOutputs(t *testing.T) {
+	shared := skill.Finding{Message: "shared", Path: "a.go", StartLine: 3}
+	merged := skill.MergeOutputs([]*skill.Output{
+		{Skill: "s", Version: "v1", Status: "pass", Major: []skill.Finding{shared}, Notes: []string{"n"}, Cached: true},
+		{Skill: "s", Version: "v1", Status: "fail", Blocking: []skill.Finding{{Message: "bad"}}, Major: []skill.Finding{shared}, Notes: []string{"n"}, Repairs: 1},
+	})
+
+	if merged.Status != "fail" {
+		t.Errorf("Status = %q, want fail when any chunk fails", merged.Status)
+	}
+	if len(merged.Blocking) != 1 || len(merged.Major) != 1 || len(merged.Notes) != 1 {
+		t.Errorf("got %d blocking, %d major, %d notes; want 1, 1, 1", len(merged.Blocking), len(merged.Major), len(merged.Notes))
+	}
+	if merged.Warning == nil || merged.Info == nil {
+		t.Error("empty severities must be non-nil for the output schema")
+	}
+	if merged.Cached || merged.Repairs != 1 {
+		t.Errorf("Cached = %v, Repairs = %d; want false, 1", merged.Cached, merged.Repairs)
+	}
+}
+
+func TestRunner_Run_ChunksLargeDiff(t *testing.T) {
+	var prompts []string
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateFunc: func(_ context.Context, _, userPrompt string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			prompts = append(prompts, userPrompt)
+			status, blocking := "pass", "[]"
+			if strings.Contains(userPrompt, "b.go") {
+				status, blocking = "fail", `["problem in b.go"]`
+			}
+			return fmt.Sprintf(`{"skill":"test-skill","version":"v1","status":%q,"blocking":%s,"major":[],"warning":[],"info":["seen"]}`,
+				status, blocking), nil
+		},
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""))
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill.", OutputSchema: `{"type":"object"}`}
+
+	a, b := fileDiff("a.go", 1, 5), fileDiff("b.go", 1, 5)
+	out, err := runner.Run(t.Context(), def, skill.RunOpts{
+		RepoTree:    "a.go\nb.go",
+		DiffPayload: a + b,
+		BaseRef:     "main",
+		DiffBudget:  len(a),
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(prompts) != 2 {
+		t.Fatalf("agent calls = %d, want one per chunk", len(prompts))
+	}
+	if !strings.Contains(prompts[0], "Diff chunk 1 of 2 (base: main)") || strings.Contains(prompts[0], "b.go b/b.go") {
+		t.Errorf("first chunk prompt malformed:\n%s", prompts[0])
+	}
+	if out.Status != "fail" || len(out.Blocking) != 1 || len(out.Info) != 1 {
+		t.Errorf("merged = status %q, %d blocking, %d info; want fail, 1, 1", out.Status, len(out.Blocking), len(out.Info))
+	}
+}
@@ -1,29 +1,48 @@
 package skill
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/pithecene-io/bonsai/internal/diff"
 	"github.com/pithecene-io/bonsai/internal/gitutil"
+	"github.com/pithecene-io/bonsai/internal/repo"
 )
 
 // BuildDiffPayload builds the diff payload for skill invocation,
 // including synthetic diffs for untracked files. This matches the
 // behavior in ai-skill.sh lines 258-286.
 func BuildDiffPayload(repoRoot, baseRef string) (string, error) {
+	return BuildScopedDiffPayload(repoRoot, baseRef, nil, nil)
+}
+
+// BuildScopedDiffPayload builds the diff payload restricted to files
+// under the scope prefixes (see repo.InScope): tracked file sections
+// and untracked files outside the scope are left out. Nil prefixes
+// include everything. Files matched by .bonsaiignore or the exclude
+// patterns (config exclude:) are always left out.
+func BuildScopedDiffPayload(repoRoot, baseRef string, prefixes, exclude []string) (string, error) {
 	if baseRef == "" || !gitutil.IsInsideWorkTree(repoRoot) {
 		return "", nil
 	}
 
+	// An unreadable .bonsaiignore degrades to the exclude patterns.
+	ig, _ := repo.LoadIgnore(repoRoot, exclude)
+	keep := repo.Selector(prefixes, ig)
+
 	diffPayload, err := gitutil.Diff(repoRoot, baseRef)
 	if err != nil {
 		diffPayload = ""
 	}
+	diffPayload = diff.SelectDiff(diffPayload, keep)
 
 	// Untracked query may fail; skip synthetic diffs on error.
 	untracked, _ := gitutil.UntrackedFiles(repoRoot)
+	untracked = repo.FilterPaths(untracked, keep)
 	if len(untracked) > 0 {
 		diffPayload += BuildSyntheticUntrackedDiff(repoRoot, untracked)
 	}
@@ -31,41 +50,100 @@ func BuildDiffPayload(repoRoot, baseRef string) (string, error) {
 	return diffPayload, nil
 }
 
+// Size caps for synthetic untracked diffs. Untracked files are often
+// build outputs or data not yet gitignored; content past a cap is
+// replaced by a "[truncated N bytes]" marker.
+const (
+	maxUntrackedFileBytes  = 64 * 1024
+	maxUntrackedTotalBytes = 512 * 1024
+)
+
 // BuildSyntheticUntrackedDiff creates fake unified diff headers for
-// untracked files. Matches ai-skill.sh lines 264-285.
+// untracked files. Matches ai-skill.sh lines 264-285, except that
+// binary files (by .gitattributes or a NUL byte, as git decides) get a
+// "Binary files /dev/null and b/<file> differ" stanza instead of their
+// content, and text is capped per file and in total.
 func BuildSyntheticUntrackedDiff(repoRoot string, files []string) string {
 	var buf strings.Builder
+	binary := diff.BinaryAttrFiles(repoRoot, files)
+	remaining := maxUntrackedTotalBytes
 	for _, f := range files {
-		fullPath := filepath.Join(repoRoot, f)
-		info, err := os.Stat(fullPath)
-		if err != nil {
-			continue
-		}
-		if !info.Mode().IsRegular() {
-			continue
-		}
-
-		// Detect file mode
-		fmode := "100644"
-		if info.Mode()&0o111 != 0 {
-			fmode = "100755"
-		}
-
-		fmt.Fprintf(&buf, "\ndiff --git a/%s b/%s\n", f, f)
-		fmt.Fprintf(&buf, "new file mode %s\n", fmode)
-		buf.WriteString("--- /dev/null\n")
-		fmt.Fprintf(&buf, "+++ b/%s\n", f)
-
-		// Read file content for diff body
-		content, err := os.ReadFile(fullPath)
-		if err != nil {
-			continue
-		}
-		lines := strings.Split(string(content), "\n")
-		fmt.Fprintf(&buf, "@@ -0,0 +1,%d @@\n", len(lines))
-		for _, line := range lines {
-			buf.WriteString("+" + line + "\n")
-		}
+		remaining -= writeUntrackedFile(&buf, repoRoot, f, binary[f], min(maxUntrackedFileBytes, remaining))
 	}
 	return buf.String()
 }
+
+// writeUntrackedFile writes the synthetic diff of one untracked file,
+// including at most limit bytes of its content, and returns the content
+// bytes written. Binary content is sniffed independently of limit, so a
+// small cap cannot hide a NUL byte. Missing and non-regular files are
+// skipped.
+func writeUntrackedFile(buf *strings.Builder, repoRoot, f string, binary bool, limit int) int {
+	fullPath := filepath.Join(repoRoot, f)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return 0
+	}
+	if !info.Mode().IsRegular() {
+		return 0
+	}
+
+	// Read file content for diff body, up to the cap
+	content, err := readHead(fullPath, limit)
+	if err != nil {
+		return 0
+	}
+
+	// Detect file mode
+	fmode := "100644"
+	if info.Mode()&0o111 != 0 {
+		fmode = "100755"
+	}
+
+	fmt.Fprintf(buf, "\ndiff --git a/%s b/%s\n", f, f)
+	fmt.Fprintf(buf, "new file mode %s\n", fmode)
+	if binary || diff.SniffBinary(fullPath) {
+		fmt.Fprintf(buf, "Binary files /dev/null and b/%s differ\n", f)
+		return 0
+	}
+	buf.WriteString("--- /dev/null\n")
+	fmt.Fprintf(buf, "+++ b/%s\n", f)
+
+	// Truncate on a line boundary where there is one
+	if i := bytes.LastIndexByte(content, '\n'); i >= 0 && int64(len(content)) < info.Size() {
+		content = content[:i+1]
+	}
+	writeAddedHunk(buf, content, info.Size()-int64(len(content)))
+	return len(content)
+}
+
+// readHead reads at most limit bytes of a file.
+func readHead(path string, limit int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(io.LimitReader(f, int64(limit)))
+}
+
+// writeAddedHunk writes content as an all-added hunk. When truncated
+// bytes remain, the hunk ends with a "\ [truncated N bytes]" marker,
+// styled like git's "\ No newline at end of file".
+func writeAddedHunk(buf *strings.Builder, content []byte, truncated int64) {
+	text := string(content)
+	if truncated > 0 {
+		text = strings.TrimSuffix(text, "\n")
+	}
+	lines := strings.Split(text, "\n")
+	if text == "" {
+		lines = nil
+	}
+	fmt.Fprintf(buf, "@@ -0,0 +1,%d @@\n", len(lines))
+	for _, line := range lines {
+		buf.WriteString("+" + line + "\n")
+	}
+	if truncated > 0 {
+		fmt.Fprintf(buf, "\\ [truncated %d bytes]\n", truncated)
+	}
+}
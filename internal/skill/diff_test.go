@@ -1,11 +1,13 @@
 package skill_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pithecene-io/bonsai/internal/gitutil"
 	"github.com/pithecene-io/bonsai/internal/skill"
 )
 
@@ -84,3 +86,187 @@ func TestBuildSyntheticUntrackedDiff_Directory(t *testing.T) {
 		t.Errorf("expected empty result for directory, got: %q", result)
 	}
 }
+
+func TestBuildScopedDiffPayload(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := gitutil.Run(dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	write("api/handler.go", "package api\n")
+	write("web/app.ts", "export {}\n")
+	run("add", ".")
+	run("commit", "-m", "initial")
+
+	write("api/handler.go", "package api\n\nfunc Handle() {}\n")
+	write("web/app.ts", "export const x = 1\n")
+	write("api/new.go", "package api\n")
+	write("web/new.ts", "export {}\n")
+
+	payload, err := skill.BuildScopedDiffPayload(dir, "HEAD", []string{"api/"}, nil)
+	if err != nil {
+		t.Fatalf("BuildScopedDiffPayload: %v", err)
+	}
+
+	for _, want := range []string{"b/api/handler.go", "b/api/new.go"} {
+		if !strings.Contains(payload, want) {
+			t.Errorf("payload missing %s", want)
+		}
+	}
+	if strings.Contains(payload, "web/") {
+		t.Errorf("payload contains out-of-scope files:\n%s", payload)
+	}
+}
+
+func TestBuildScopedDiffPayload_HonoursIgnore(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{{"init"}, {"config", "user.email", "test@test.com"}, {"config", "user.name", "Test"}} {
+		if _, err := gitutil.Run(dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	files := map[string]string{
+		".bonsaiignore":         "gen/\n",
+		"gen/api.go":            "package gen\n",
+		"main.go":               "package main\n",
+		"main_test.go.snapshot": "snap\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := gitutil.Run(dir, "commit", "--allow-empty", "-m", "initial"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+
+	payload, err := skill.BuildScopedDiffPayload(dir, "HEAD", nil, []string{"*.snapshot"})
+	if err != nil {
+		t.Fatalf("BuildScopedDiffPayload: %v", err)
+	}
+
+	if !strings.Contains(payload, "b/main.go") {
+		t.Error("payload missing main.go")
+	}
+	if strings.Contains(payload, "gen/api.go") || strings.Contains(payload, ".snapshot") {
+		t.Errorf("payload contains ignored files:\n%s", payload)
+	}
+}
+
+func TestBuildSyntheticUntrackedDiff_Binary(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := gitutil.Run(dir, "init"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.dat binary\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), []byte("\x89PNG\r\n\x1a\n\x00\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "table.dat"), []byte("plain text\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := skill.BuildSyntheticUntrackedDiff(dir, []string{"logo.png", "table.dat"})
+
+	for _, f := range []string{"logo.png", "table.dat"} {
+		if !strings.Contains(result, "Binary files /dev/null and b/"+f+" differ") {
+			t.Errorf("missing binary stanza for %s", f)
+		}
+	}
+	if strings.Contains(result, "+++ ") || strings.Contains(result, "plain text") {
+		t.Errorf("binary files should not include content:\n%s", result)
+	}
+}
+
+func TestBuildSyntheticUntrackedDiff_TruncatesLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	line := strings.Repeat("x", 99) + "\n"
+	big := strings.Repeat(line, 1000) // 100 KB
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(big), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := skill.BuildSyntheticUntrackedDiff(dir, []string{"big.txt"})
+
+	if len(result) >= len(big) {
+		t.Errorf("diff is %d bytes, want it capped below the %d-byte file", len(result), len(big))
+	}
+	if !strings.Contains(result, "\\ [truncated ") {
+		t.Errorf("missing truncation marker:\n%.200s", result)
+	}
+	for _, l := range strings.Split(strings.TrimSpace(result), "\n") {
+		if strings.HasPrefix(l, "+x") && len(l) != 100 {
+			t.Fatalf("content truncated mid-line: %q", l)
+		}
+	}
+}
+
+func TestBuildSyntheticUntrackedDiff_TotalCap(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := range 20 {
+		name := fmt.Sprintf("data%02d.csv", i)
+		content := strings.Repeat(strings.Repeat("1,", 49)+"1\n", 600) // 60 KB
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, name)
+	}
+
+	result := skill.BuildSyntheticUntrackedDiff(dir, files)
+
+	if len(result) > 600*1024 {
+		t.Errorf("diff is %d bytes, want the total capped", len(result))
+	}
+	if !strings.Contains(result, "diff --git a/data19.csv b/data19.csv") {
+		t.Error("files past the total cap should still be listed")
+	}
+}
+
+func TestBuildSyntheticUntrackedDiff_BinaryPastCap(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := range 9 {
+		name := fmt.Sprintf("data%02d.csv", i)
+		content := strings.Repeat(strings.Repeat("1,", 49)+"1\n", 600) // 60 KB
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, name)
+	}
+	// The total cap leaves well under 200 bytes for this file, so its
+	// NUL byte lies past the content that is read.
+	blob := strings.Repeat("a", 200) + "\x00" + strings.Repeat("b", 100)
+	if err := os.WriteFile(filepath.Join(dir, "blob.bin"), []byte(blob), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := skill.BuildSyntheticUntrackedDiff(dir, append(files, "blob.bin"))
+
+	if !strings.Contains(result, "Binary files /dev/null and b/blob.bin differ") {
+		t.Error("missing binary stanza for a file whose NUL byte lies past the content cap")
+	}
+	if strings.Contains(result, "+++ b/blob.bin") {
+		t.Error("binary file past the content cap should not include content")
+	}
+}
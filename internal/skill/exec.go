@@ -0,0 +1,116 @@
+package skill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExecInput is the JSON document an exec skill receives on stdin.
+type ExecInput struct {
+	Skill    string         `json:"skill"`
+	RepoRoot string         `json:"repo_root"`
+	RepoTree []string       `json:"repo_tree"`
+	Diff     string         `json:"diff"`
+	BaseRef  string         `json:"base_ref"`
+	Scope    []string       `json:"scope"`
+	Config   map[string]any `json:"config"`
+}
+
+// maxExecStderr bounds how much of an exec skill's stderr is quoted in
+// an error.
+const maxExecStderr = 2 * 1024
+
+// execWaitDelay bounds how long a cancelled exec skill may hold its
+// output pipes open after being killed, e.g. via a backgrounded child.
+const execWaitDelay = 5 * time.Second
+
+// execCredentialEnv lists the model-provider credentials bonsai itself
+// uses; they are withheld from exec skills, which are often third-party
+// scripts with no need for them.
+var execCredentialEnv = []string{
+	"ANTHROPIC_API_KEY",
+	"ANTHROPIC_AUTH_TOKEN",
+	"CLAUDE_CODE_OAUTH_TOKEN",
+	"BONSAI_PROVIDER_ANTHROPIC_API_KEY",
+}
+
+// runExec runs an exec skill: the input document is written to the
+// entrypoint's stdin and its stdout must be a unified-schema JSON
+// object. The process runs in the repo root with BONSAI_SKILL_DIR set
+// to the skill directory, and otherwise inherits bonsai's environment
+// minus execCredentialEnv and withheld. A non-zero exit is tolerated
+// when stdout is valid output, since linters commonly exit non-zero on
+// findings.
+func runExec(ctx context.Context, def *Definition, opts RunOpts, withheld []string) (*Output, error) {
+	input, err := json.Marshal(ExecInput{
+		Skill:    def.Name,
+		RepoRoot: opts.RepoRoot,
+		RepoTree: treeLines(opts.RepoTree),
+		Diff:     opts.DiffPayload,
+		BaseRef:  opts.BaseRef,
+		Scope:    opts.Scope,
+		Config:   opts.Config,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode exec input: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, def.Exec)
+	cmd.Dir = opts.RepoRoot
+	cmd.Env = append(execEnv(os.Environ(), withheld), "BONSAI_SKILL_DIR="+def.Dir)
+	cmd.WaitDelay = execWaitDelay
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	output, parseErr := ParseOutput(stdout.String())
+	if parseErr == nil {
+		return output, nil
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("exec %s: %w%s", def.Exec, runErr, stderrTail(stderr.String()))
+	}
+	return nil, fmt.Errorf("exec %s: %w%s", def.Exec, parseErr, stderrTail(stderr.String()))
+}
+
+// execEnv returns environ without the variables in execCredentialEnv
+// or withheld.
+func execEnv(environ, withheld []string) []string {
+	out := make([]string, 0, len(environ))
+	for _, e := range environ {
+		name, _, _ := strings.Cut(e, "=")
+		if !slices.Contains(execCredentialEnv, name) && !slices.Contains(withheld, name) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// treeLines splits a newline-joined repo tree into paths.
+func treeLines(tree string) []string {
+	if tree == "" {
+		return []string{}
+	}
+	return strings.Split(tree, "\n")
+}
+
+// stderrTail formats the end of a process's stderr for an error message.
+func stderrTail(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if stderr == "" {
+		return ""
+	}
+	if len(stderr) > maxExecStderr {
+		stderr = "…" + stderr[len(stderr)-maxExecStderr:]
+	}
+	return "\nstderr: " + stderr
+}
@@ -0,0 +1,160 @@
+package skill_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/agent"
+	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// writeExecSkill creates a repo-local skill whose exec entrypoint is
+// the given shell script, returning the repo root.
+func writeExecSkill(t *testing.T, entry, script string) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "ai", "skills", "my-lint", "v1")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"SKILL.md":           "---\nname: my-lint\nexec: " + entry + "\n---\n\nRuns my-lint.\n",
+		"input.schema.json":  "{}",
+		"output.schema.json": "{}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if script != "" {
+		if err := os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func runExecSkill(t *testing.T, root string, opts skill.RunOpts, runnerOpts ...skill.RunnerOption) (*skill.Output, *agent.MockAgent, error) {
+	t.Helper()
+	def, err := skill.Load(assets.NewResolver(root), "my-lint", "v1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	mock := &agent.MockAgent{NameVal: "test"}
+	opts.RepoRoot = root
+	out, err := skill.NewRunner(mock, nil, runnerOpts...).Run(context.Background(), def, opts)
+	return out, mock, err
+}
+
+const validOutput = `{"skill":"my-lint","version":"v1","status":"fail","blocking":[{"message":"bad","path":"a.go","start_line":3}],"major":[],"warning":[],"info":[]}`
+
+func TestRunner_Exec_ReceivesInputAndParsesOutput(t *testing.T) {
+	root := writeExecSkill(t, "run.sh", `cat > "$BONSAI_SKILL_DIR/input.json"
+echo '`+validOutput+`'
+exit 1
+`)
+
+	out, mock, err := runExecSkill(t, root, skill.RunOpts{
+		RepoTree:    "a.go\nb.go",
+		DiffPayload: "diff --git a/a.go b/a.go",
+		BaseRef:     "main",
+		Scope:       []string{"internal/"},
+		Config:      map[string]any{"strict": true},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(mock.EvaluateCalls) != 0 {
+		t.Error("exec skill must not invoke the agent")
+	}
+	if len(out.Blocking) != 1 || out.Blocking[0].Location() != "a.go:3" {
+		t.Errorf("Blocking = %v", out.Blocking)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "ai", "skills", "my-lint", "v1", "input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var in skill.ExecInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		t.Fatalf("stdin is not JSON: %v", err)
+	}
+	if in.Skill != "my-lint" || in.RepoRoot != root || in.BaseRef != "main" || len(in.RepoTree) != 2 ||
+		in.Diff == "" || in.Scope[0] != "internal/" || in.Config["strict"] != true {
+		t.Errorf("unexpected input: %+v", in)
+	}
+}
+
+func TestRunner_Exec_InvalidOutputReportsStderr(t *testing.T) {
+	root := writeExecSkill(t, "run.sh", "echo 'linter crashed' >&2\nexit 2\n")
+
+	_, _, err := runExecSkill(t, root, skill.RunOpts{})
+	if err == nil || !strings.Contains(err.Error(), "linter crashed") {
+		t.Errorf("expected error quoting stderr, got %v", err)
+	}
+}
+
+func TestRunner_Exec_SchemaViolation(t *testing.T) {
+	root := writeExecSkill(t, "run.sh", `echo '{"skill":"my-lint","status":"pass"}'`+"\n")
+
+	_, _, err := runExecSkill(t, root, skill.RunOpts{})
+	if err == nil || !strings.Contains(err.Error(), "missing required key") {
+		t.Errorf("expected schema validation error, got %v", err)
+	}
+}
+
+func TestLoad_ExecEntrypointNotFound(t *testing.T) {
+	root := writeExecSkill(t, "no-such-linter-binary", "")
+
+	_, err := skill.Load(assets.NewResolver(root), "my-lint", "v1")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected entrypoint error, got %v", err)
+	}
+}
+
+func TestRunner_Exec_WithholdsProviderCredentials(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-parent")
+	t.Setenv("BONSAI_EXEC_TEST_VAR", "kept")
+	root := writeExecSkill(t, "run.sh", `env > "$BONSAI_SKILL_DIR/env.txt"
+echo '`+validOutput+`'
+`)
+
+	if _, _, err := runExecSkill(t, root, skill.RunOpts{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "ai", "skills", "my-lint", "v1", "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := string(data)
+	if strings.Contains(env, "ANTHROPIC_API_KEY") {
+		t.Error("exec skill environment should not include ANTHROPIC_API_KEY")
+	}
+	if !strings.Contains(env, "BONSAI_EXEC_TEST_VAR=kept") {
+		t.Error("exec skill environment should inherit other variables")
+	}
+}
+
+func TestRunner_Exec_WithholdsConfiguredCredentials(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "sk-or-parent")
+	root := writeExecSkill(t, "run.sh", `env > "$BONSAI_SKILL_DIR/env.txt"
+echo '`+validOutput+`'
+`)
+
+	if _, _, err := runExecSkill(t, root, skill.RunOpts{}, skill.WithWithheldEnv("OPENROUTER_API_KEY")); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "ai", "skills", "my-lint", "v1", "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "OPENROUTER_API_KEY") {
+		t.Error("exec skill environment should not include the withheld OPENROUTER_API_KEY")
+	}
+}
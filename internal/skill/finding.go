@@ -0,0 +1,105 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Finding is a single skill finding. Skills may emit either a plain
+// string (legacy form, message only) or an object carrying a source
+// location and an optional rule id.
+type Finding struct {
+	Message   string `json:"message"`
+	Path      string `json:"path,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+}
+
+// findingObject is the object form of a Finding, used to decode without
+// recursing into Finding.UnmarshalJSON.
+type findingObject Finding
+
+// UnmarshalJSON accepts both the legacy string form and the object form.
+func (f *Finding) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var msg string
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		*f = Finding{Message: msg}
+		return nil
+	}
+	if len(data) == 0 || data[0] != '{' {
+		return fmt.Errorf("finding must be a string or object, got %s", data)
+	}
+	var obj findingObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*f = Finding(obj)
+	return nil
+}
+
+// HasLocation reports whether the finding points at a file.
+func (f Finding) HasLocation() bool { return f.Path != "" }
+
+// Location formats the finding's location as path, path:line, or
+// path:start-end. Returns "" when the finding has no path.
+func (f Finding) Location() string {
+	if f.Path == "" {
+		return ""
+	}
+	switch {
+	case f.StartLine <= 0:
+		return f.Path
+	case f.EndLine <= f.StartLine:
+		return f.Path + ":" + strconv.Itoa(f.StartLine)
+	default:
+		return fmt.Sprintf("%s:%d-%d", f.Path, f.StartLine, f.EndLine)
+	}
+}
+
+// String renders the finding as a single display line, prefixed with
+// its location and rule id when present.
+func (f Finding) String() string {
+	s := f.Message
+	if f.Rule != "" {
+		s = "[" + f.Rule + "] " + s
+	}
+	if loc := f.Location(); loc != "" {
+		s = loc + ": " + s
+	}
+	return s
+}
+
+// validate checks the invariants the JSON schema places on object findings.
+func (f Finding) validate() error {
+	switch {
+	case f.Message == "":
+		return errors.New("message is required")
+	case f.StartLine < 0 || f.EndLine < 0:
+		return errors.New("line numbers must be positive")
+	case f.EndLine > 0 && f.EndLine < f.StartLine:
+		return fmt.Errorf("end_line %d before start_line %d", f.EndLine, f.StartLine)
+	case f.StartLine > 0 && f.Path == "":
+		return errors.New("start_line requires path")
+	}
+	return nil
+}
+
+// Lines renders each finding via String.
+func Lines(findings []Finding) []string {
+	if findings == nil {
+		return nil
+	}
+	lines := make([]string, len(findings))
+	for i := range findings {
+		lines[i] = findings[i].String()
+	}
+	return lines
+}
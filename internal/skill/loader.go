@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +21,8 @@ type Definition struct {
 	OutputSchema string // output.schema.json content
 	InputSchema  string // input.schema.json content
 	Source       string // "repo-local", "user", or "embedded"
+	Dir          string // Skill directory on disk; empty for embedded skills
+	Exec         string // Resolved exec entrypoint; empty for prompt-based skills
 }
 
 // Load loads a skill definition from the resolver.
@@ -75,16 +78,59 @@ func Load(resolver *assets.Resolver, name, version string) (*Definition, error)
 		OutputSchema: string(outputSchema),
 		InputSchema:  string(inputSchema),
 		Source:       source,
+		Dir:          fsPath,
 	}
 
-	// Extract description from frontmatter if present
-	if desc, ok := frontmatter["description"]; ok {
-		def.Description = desc
+	if err := def.applyFrontmatter(frontmatter); err != nil {
+		return nil, fmt.Errorf("skill %s/%s: %w", name, version, err)
 	}
 
 	return def, nil
 }
 
+// applyFrontmatter sets the description and resolves the exec
+// entrypoint declared in SKILL.md frontmatter.
+func (d *Definition) applyFrontmatter(fm map[string]string) error {
+	if desc, ok := fm["description"]; ok {
+		d.Description = desc
+	}
+	if entry := fm["exec"]; entry != "" {
+		path, err := resolveExec(d.Dir, entry)
+		if err != nil {
+			return err
+		}
+		d.Exec = path
+	}
+	return nil
+}
+
+// resolveExec locates an exec entrypoint: absolute paths are used as
+// is, relative paths resolve against the skill directory, and bare
+// names not found there are looked up on PATH. Embedded skills cannot
+// declare an entrypoint since there is no file to execute.
+func resolveExec(skillDir, entry string) (string, error) {
+	if skillDir == "" {
+		return "", fmt.Errorf("exec entrypoint %q requires a skill directory on disk (embedded skills cannot exec)", entry)
+	}
+	if filepath.IsAbs(entry) {
+		return entry, nil
+	}
+	if p := filepath.Join(skillDir, entry); fileExists(p) {
+		return p, nil
+	}
+	p, err := exec.LookPath(entry)
+	if err != nil {
+		return "", fmt.Errorf("exec entrypoint %q not found in %s or on PATH", entry, skillDir)
+	}
+	return p, nil
+}
+
+// fileExists reports whether path names an existing regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
 // parseFrontmatter strips YAML frontmatter from SKILL.md content.
 // Returns the body (after frontmatter) and a map of frontmatter key-value pairs.
 // Matches shell: sed '1{/^---$/!q}; 1,/^---$/d'
@@ -0,0 +1,19 @@
+package skill
+
+import "context"
+
+// NativeSkill is a skill evaluated by Go code instead of a model. Native
+// skills implement deterministic checks that are pure functions of the
+// repository tree and diff, so they cost nothing, finish instantly, and
+// always produce the same output for the same input.
+type NativeSkill interface {
+	Run(ctx context.Context, in NativeInput) (*Output, error)
+}
+
+// NativeInput is the repository context handed to a native skill: the
+// same tree and diff a model-backed skill sees in its prompt.
+type NativeInput struct {
+	RepoRoot    string   // Repository root, for reading governance docs
+	Files       []string // Repository tree (tracked + untracked), scope applied
+	DiffPayload string   // Diff content (from --base); empty without a base
+}
@@ -12,12 +12,24 @@ type Output struct {
 	Skill    string         `json:"skill"`
 	Version  string         `json:"version"`
 	Status   string         `json:"status"`
-	Blocking []string       `json:"blocking"`
-	Major    []string       `json:"major"`
-	Warning  []string       `json:"warning"`
-	Info     []string       `json:"info"`
+	Blocking []Finding      `json:"blocking"`
+	Major    []Finding      `json:"major"`
+	Warning  []Finding      `json:"warning"`
+	Info     []Finding      `json:"info"`
 	Notes    []string       `json:"notes,omitempty"`
 	Details  map[string]any `json:"details,omitempty"`
+
+	// Cached is set when the output was served from the result cache
+	// rather than a fresh agent invocation. Not part of the schema.
+	Cached bool `json:"-"`
+
+	// Repairs counts the schema-repair re-prompts needed before the
+	// response validated. Not part of the schema.
+	Repairs int `json:"-"`
+
+	// Elided describes context left out of the prompt to fit the
+	// model's context window. Not part of the schema.
+	Elided []string `json:"-"`
 }
 
 // validStatuses is the set of allowed status enum values.
@@ -53,7 +65,8 @@ func ParseOutput(raw string) (*Output, error) {
 	return &out, nil
 }
 
-// validate checks required-key presence and enum constraints.
+// validate checks required-key presence, enum constraints, and
+// finding invariants.
 func validate(o *Output, keys map[string]json.RawMessage) []string {
 	var errs []string
 	for _, key := range requiredKeys {
@@ -64,9 +77,32 @@ func validate(o *Output, keys map[string]json.RawMessage) []string {
 	if o.Status != "" && !validStatuses[o.Status] {
 		errs = append(errs, fmt.Sprintf("status: must be \"pass\" or \"fail\", got %q", o.Status))
 	}
+	for _, sev := range o.severities() {
+		for i, f := range sev.findings {
+			if err := f.validate(); err != nil {
+				errs = append(errs, fmt.Sprintf("%s[%d]: %v", sev.label, i, err))
+			}
+		}
+	}
 	return errs
 }
 
+// severityFindings pairs a severity label with its findings.
+type severityFindings struct {
+	label    string
+	findings []Finding
+}
+
+// severities returns the four finding arrays in severity order.
+func (o *Output) severities() []severityFindings {
+	return []severityFindings{
+		{"blocking", o.Blocking},
+		{"major", o.Major},
+		{"warning", o.Warning},
+		{"info", o.Info},
+	}
+}
+
 // ShouldFail returns true if the output indicates a blocking failure.
 // Matches ai-skill.sh exit code logic: exit 1 only if status == "fail"
 // AND blocking is non-empty.
@@ -74,6 +110,26 @@ func (o *Output) ShouldFail() bool {
 	return o.Status == "fail" && len(o.Blocking) > 0
 }
 
+// ShouldFailAt reports whether the output has findings at or above the
+// threshold severity ("blocking", "major", or "warning"). Info findings
+// never fail. An empty or unknown threshold behaves like "blocking".
+func (o *Output) ShouldFailAt(threshold string) bool {
+	switch threshold {
+	case "warning":
+		if len(o.Warning) > 0 {
+			return true
+		}
+		fallthrough
+	case "major":
+		if len(o.Major) > 0 {
+			return true
+		}
+		fallthrough
+	default:
+		return o.ShouldFail()
+	}
+}
+
 // stripCodeFences removes markdown code fence wrappers (```json / ```)
 // from JSON responses.
 func stripCodeFences(s string) string {
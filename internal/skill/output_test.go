@@ -140,6 +140,70 @@ func TestParseOutput_NonStringInArray(t *testing.T) {
 	}
 }
 
+func TestParseOutput_StructuredFindings(t *testing.T) {
+	raw := `{
+		"skill": "test",
+		"version": "v1",
+		"status": "fail",
+		"blocking": [{"message": "secret committed", "path": "cmd/main.go", "start_line": 12, "end_line": 14, "rule": "no-secrets"}],
+		"major": ["legacy string finding"],
+		"warning": [{"message": "missing doc", "path": "README.md"}],
+		"info": []
+	}`
+
+	out, err := skill.ParseOutput(raw)
+	if err != nil {
+		t.Fatalf("ParseOutput: %v", err)
+	}
+	want := skill.Finding{Message: "secret committed", Path: "cmd/main.go", StartLine: 12, EndLine: 14, Rule: "no-secrets"}
+	if len(out.Blocking) != 1 || out.Blocking[0] != want {
+		t.Errorf("Blocking = %+v, want [%+v]", out.Blocking, want)
+	}
+	if len(out.Major) != 1 || out.Major[0] != (skill.Finding{Message: "legacy string finding"}) {
+		t.Errorf("Major = %+v", out.Major)
+	}
+	if !out.ShouldFail() {
+		t.Error("expected ShouldFail to be true")
+	}
+}
+
+func TestParseOutput_InvalidFinding(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding string
+	}{
+		{"missing message", `{"path": "a.go", "start_line": 1}`},
+		{"end before start", `{"message": "m", "path": "a.go", "start_line": 5, "end_line": 2}`},
+		{"line without path", `{"message": "m", "start_line": 3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := `{"skill": "t", "version": "v1", "status": "pass",
+				"blocking": [], "major": [` + tt.finding + `], "warning": [], "info": []}`
+			if _, err := skill.ParseOutput(raw); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	tests := []struct {
+		f    skill.Finding
+		want string
+	}{
+		{skill.Finding{Message: "plain"}, "plain"},
+		{skill.Finding{Message: "m", Path: "a.go"}, "a.go: m"},
+		{skill.Finding{Message: "m", Path: "a.go", StartLine: 3}, "a.go:3: m"},
+		{skill.Finding{Message: "m", Path: "a.go", StartLine: 3, EndLine: 7, Rule: "r1"}, "a.go:3-7: [r1] m"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
 func TestParseOutput_InvalidJSON(t *testing.T) {
 	_, err := skill.ParseOutput("not json at all")
 	if err == nil {
@@ -153,3 +217,30 @@ func TestParseOutput_Empty(t *testing.T) {
 		t.Error("expected error for empty response")
 	}
 }
+
+func TestOutput_ShouldFailAt(t *testing.T) {
+	out := &skill.Output{
+		Status:  "pass",
+		Major:   []skill.Finding{{Message: "drift"}},
+		Warning: []skill.Finding{{Message: "odd"}},
+	}
+	tests := []struct {
+		threshold string
+		want      bool
+	}{
+		{"", false},
+		{"blocking", false},
+		{"major", true},
+		{"warning", true},
+	}
+	for _, tt := range tests {
+		if got := out.ShouldFailAt(tt.threshold); got != tt.want {
+			t.Errorf("ShouldFailAt(%q) = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+
+	warnOnly := &skill.Output{Status: "pass", Warning: []skill.Finding{{Message: "odd"}}}
+	if warnOnly.ShouldFailAt("major") {
+		t.Error("warning-only output should not fail at major")
+	}
+}
@@ -6,71 +6,293 @@ import (
 	"strings"
 
 	"github.com/pithecene-io/bonsai/internal/agent"
+	"github.com/pithecene-io/bonsai/internal/cache"
 	"github.com/pithecene-io/bonsai/internal/prompt"
 )
 
 // RunOpts holds options for running a skill.
 type RunOpts struct {
-	RepoTree    string      // Repository tree listing
-	DiffPayload string      // Diff content (from --base)
-	BaseRef     string      // Base ref for diff context
-	Model       agent.Model // Model override (e.g. "haiku", "sonnet"); empty = agent default
+	RepoTree      string      // Repository tree listing
+	DiffPayload   string      // Diff content (from --base)
+	BaseRef       string      // Base ref for diff context
+	Model         agent.Model // Model override (e.g. "haiku", "sonnet"); empty = agent default
+	DiffBudget    int         // Max diff bytes per evaluation; larger diffs are chunked. 0 = unlimited
+	ContextWindow int         // Tokens the prompt is budgeted against. 0 = agent.DefaultContextWindow
+	ModelIdentity string      // Resolved model id and generation settings for the cache key. "" = Model
+
+	// Exec skills only: the working directory, path scope, and the
+	// registry entry's config, passed through in the stdin document.
+	RepoRoot string
+	Scope    []string
+	Config   map[string]any
+}
+
+// contextWindow returns the configured context window, defaulting to
+// agent.DefaultContextWindow.
+func (o RunOpts) contextWindow() int {
+	if o.ContextWindow > 0 {
+		return o.ContextWindow
+	}
+	return agent.DefaultContextWindow
+}
+
+// modelIdentity returns the model identity hashed into cache keys.
+func (o RunOpts) modelIdentity() string {
+	if o.ModelIdentity != "" {
+		return o.ModelIdentity
+	}
+	return string(o.Model)
 }
 
+// maxEchoedResponse bounds how much of a rejected response is echoed
+// back in a repair prompt; truncated or runaway output is the common
+// failure mode and need not be replayed in full.
+const maxEchoedResponse = 8 * 1024
+
 // Runner invokes skills via an AI agent.
 type Runner struct {
-	agent   agent.Agent
-	builder *prompt.Builder
+	agent         agent.Agent
+	builder       *prompt.Builder
+	cache         *cache.Store // nil disables result caching
+	repairRetries int          // re-prompts after a response fails validation
+	withheldEnv   []string     // extra variables withheld from exec skills
+}
+
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithCache enables content-addressed caching of validated skill
+// outputs. A nil store leaves caching disabled.
+func WithCache(c *cache.Store) RunnerOption {
+	return func(r *Runner) { r.cache = c }
+}
+
+// WithRepairRetries sets how many times a response that fails output
+// validation is sent back to the model, together with the validation
+// error, for correction. Zero (the default) records the first invalid
+// response as an error.
+func WithRepairRetries(n int) RunnerOption {
+	return func(r *Runner) { r.repairRetries = max(n, 0) }
+}
+
+// WithWithheldEnv withholds the named environment variables, such as a
+// configured provider's api_key_env, from exec skills in addition to
+// the Anthropic credentials that are always withheld.
+func WithWithheldEnv(names ...string) RunnerOption {
+	return func(r *Runner) { r.withheldEnv = append(r.withheldEnv, names...) }
 }
 
 // NewRunner creates a skill runner.
-func NewRunner(a agent.Agent, b *prompt.Builder) *Runner {
-	return &Runner{agent: a, builder: b}
+func NewRunner(a agent.Agent, b *prompt.Builder, opts ...RunnerOption) *Runner {
+	r := &Runner{agent: a, builder: b}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
 }
 
-// Run invokes a skill and returns validated output.
+// Run invokes a skill and returns validated output. When a cache is
+// configured, a previously validated response for identical inputs is
+// returned without invoking the agent. Skills declaring an exec
+// entrypoint run that executable instead and are never cached.
+//
+// A diff larger than opts.DiffBudget is split on file and hunk
+// boundaries; each chunk is evaluated separately (and cached
+// separately) and the outputs are merged with MergeOutputs.
+//
+// Prompts are budgeted against the model's context window: governance
+// layers, lockfile/generated diffs, and tree detail are elided as
+// needed, noted in the prompt, and reported in Output.Elided.
 func (r *Runner) Run(ctx context.Context, def *Definition, opts RunOpts) (*Output, error) {
-	// Build system prompt (validator pattern)
-	systemPrompt, err := r.builder.BuildValidator(prompt.ValidatorOpts{
+	if def.Exec != "" {
+		return runExec(ctx, def, opts, r.withheldEnv)
+	}
+
+	// Build system prompt (validator pattern), leaving at least half
+	// the window for the repository context.
+	window := opts.contextWindow() - outputReserve
+	systemPrompt, elided, err := r.builder.BuildValidatorBudgeted(prompt.ValidatorOpts{
 		SkillBody:    def.Body,
 		OutputSchema: def.OutputSchema,
 		Lite:         opts.Model.IsLite(),
+		MaxTokens:    window / 2,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("build system prompt: %w", err)
 	}
 
-	// Build user prompt
-	userPrompt := buildUserPrompt(opts)
+	opts, userElided := fitUserPrompt(opts, window-prompt.EstimateTokens(systemPrompt))
+	elided = append(elided, userElided...)
 
-	// Invoke agent non-interactively
-	response, err := r.agent.Evaluate(ctx, systemPrompt, userPrompt, opts.Model, agent.ToolsDisabled)
+	output, err := r.runChunks(ctx, def, systemPrompt, opts, elided)
 	if err != nil {
-		return nil, fmt.Errorf("agent invocation: %w", err)
+		return nil, err
+	}
+	output.Elided = elided
+	return output, nil
+}
+
+// runChunks evaluates the diff in opts whole or, when it exceeds
+// opts.DiffBudget, chunk by chunk, merging the chunk outputs.
+func (r *Runner) runChunks(ctx context.Context, def *Definition, systemPrompt string, opts RunOpts, elided []string) (*Output, error) {
+	chunks := SplitDiff(opts.DiffPayload, opts.DiffBudget)
+	if len(chunks) == 1 {
+		return r.runPrompt(ctx, def, systemPrompt, buildUserPrompt(opts, elided), opts)
+	}
+
+	outputs := make([]*Output, 0, len(chunks))
+	for i, chunk := range chunks {
+		userPrompt := buildChunkPrompt(opts, chunk, i, len(chunks), elided)
+		output, err := r.runPrompt(ctx, def, systemPrompt, userPrompt, opts)
+		if err != nil {
+			return nil, fmt.Errorf("diff chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		outputs = append(outputs, output)
+	}
+	return MergeOutputs(outputs), nil
+}
+
+// runPrompt evaluates one user prompt, consulting and filling the cache.
+func (r *Runner) runPrompt(ctx context.Context, def *Definition, systemPrompt, userPrompt string, opts RunOpts) (*Output, error) {
+	key := cacheKey(def, systemPrompt, userPrompt, opts.modelIdentity())
+	if output, ok := r.cached(key); ok {
+		return output, nil
 	}
 
-	// Validate response
-	output, err := ParseOutput(response)
+	response, output, err := r.evaluate(ctx, systemPrompt, userPrompt, opts.Model)
 	if err != nil {
-		return nil, fmt.Errorf("validate output: %w", err)
+		return nil, err
 	}
 
+	r.store(key, response)
 	return output, nil
 }
 
+// evaluate invokes the agent and validates its response. A response
+// that fails validation is re-prompted with the validation error and
+// the rejected response, up to repairRetries times.
+func (r *Runner) evaluate(ctx context.Context, systemPrompt, userPrompt string, model agent.Model) (string, *Output, error) {
+	request := userPrompt
+	for attempt := 0; ; attempt++ {
+		response, err := r.agent.Evaluate(ctx, systemPrompt, request, model, agent.ToolsDisabled)
+		if err != nil {
+			return "", nil, fmt.Errorf("agent invocation: %w", err)
+		}
+
+		output, err := ParseOutput(response)
+		if err == nil {
+			output.Repairs = attempt
+			return response, output, nil
+		}
+		if attempt >= r.repairRetries {
+			if attempt > 0 {
+				return "", nil, fmt.Errorf("validate output (after %d repair attempt(s)): %w", attempt, err)
+			}
+			return "", nil, fmt.Errorf("validate output: %w", err)
+		}
+		request = buildRepairPrompt(userPrompt, response, err)
+	}
+}
+
+// buildRepairPrompt asks the model to correct a response that failed
+// output validation. The original request is repeated so the model can
+// re-derive its verdict if the rejected response was truncated.
+func buildRepairPrompt(userPrompt, response string, validationErr error) string {
+	if len(response) > maxEchoedResponse {
+		response = response[:maxEchoedResponse] + "\n[... truncated]"
+	}
+	return strings.Join([]string{
+		userPrompt,
+		"",
+		"Your previous response was rejected because it did not conform to the output schema:",
+		validationErr.Error(),
+		"",
+		"Previous response:",
+		response,
+		"",
+		"Respond again with a single corrected JSON object only. No prose, no code fences.",
+	}, "\n")
+}
+
+// cacheKey hashes every input that determines a skill's verdict: the
+// assembled prompts, the model identity (resolved model and generation
+// settings), and the skill definition. Body and schemas are already
+// embedded in the system prompt but are hashed separately so the key
+// does not depend on prompt layout.
+func cacheKey(def *Definition, systemPrompt, userPrompt, modelIdentity string) string {
+	return cache.Key(
+		systemPrompt,
+		userPrompt,
+		modelIdentity,
+		def.Name,
+		def.Body,
+		def.InputSchema,
+		def.OutputSchema,
+	)
+}
+
+// cached returns a previously validated output for key. Entries that no
+// longer validate (e.g. after a schema tightening) are treated as misses.
+func (r *Runner) cached(key string) (*Output, bool) {
+	if r.cache == nil {
+		return nil, false
+	}
+	data, ok := r.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	output, err := ParseOutput(string(data))
+	if err != nil {
+		return nil, false
+	}
+	output.Cached = true
+	return output, true
+}
+
+// store records a validated response. Cache write failures are
+// non-fatal: the evaluation already succeeded.
+func (r *Runner) store(key, response string) {
+	if r.cache == nil {
+		return
+	}
+	_ = r.cache.Put(key, []byte(response))
+}
+
 // buildUserPrompt constructs the user prompt matching ai-skill.sh behavior.
-func buildUserPrompt(opts RunOpts) string {
+func buildUserPrompt(opts RunOpts, elided []string) string {
+	return assembleUserPrompt(opts.RepoTree, fmt.Sprintf("Diff (base: %s):", opts.BaseRef), opts.DiffPayload, elided)
+}
+
+// buildChunkPrompt constructs the user prompt for one chunk of a diff
+// too large to evaluate at once.
+func buildChunkPrompt(opts RunOpts, chunk string, i, n int, elided []string) string {
+	label := fmt.Sprintf("Diff chunk %d of %d (base: %s). The other chunks are evaluated separately; "+
+		"report only findings visible in this chunk:", i+1, n, opts.BaseRef)
+	return assembleUserPrompt(opts.RepoTree, label, chunk, elided)
+}
+
+// assembleUserPrompt lays out the repository tree and a labelled diff,
+// followed by a note of any context elided to fit the prompt budget.
+func assembleUserPrompt(repoTree, diffLabel, diff string, elided []string) string {
 	var parts []string
 
 	parts = append(parts, "Evaluate the following repository.")
 	parts = append(parts, "")
 	parts = append(parts, "Repository tree:")
-	parts = append(parts, opts.RepoTree)
+	parts = append(parts, repoTree)
+
+	if diff != "" {
+		parts = append(parts, "")
+		parts = append(parts, diffLabel)
+		parts = append(parts, diff)
+	}
 
-	if opts.DiffPayload != "" {
+	if len(elided) > 0 {
 		parts = append(parts, "")
-		parts = append(parts, fmt.Sprintf("Diff (base: %s):", opts.BaseRef))
-		parts = append(parts, opts.DiffPayload)
+		parts = append(parts, "Elided to fit the prompt budget (do not report findings about their absence):")
+		for _, e := range elided {
+			parts = append(parts, "- "+e)
+		}
 	}
 
 	parts = append(parts, "")
@@ -1,11 +1,14 @@
 package skill_test
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/pithecene-io/bonsai/internal/agent"
 	"github.com/pithecene-io/bonsai/internal/assets"
+	"github.com/pithecene-io/bonsai/internal/cache"
 	"github.com/pithecene-io/bonsai/internal/prompt"
 	"github.com/pithecene-io/bonsai/internal/skill"
 )
@@ -136,3 +139,130 @@ func TestRunner_Run_WithDiffPayload(t *testing.T) {
 		t.Error("expected ShouldFail = true for blocking findings")
 	}
 }
+
+func TestRunner_Run_CacheHitSkipsAgent(t *testing.T) {
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateResponse: `{"skill": "test-skill", "version": "v1", "status": "fail",
+			"blocking": [{"message": "bad", "path": "a.go", "start_line": 2}],
+			"major": [], "warning": [], "info": []}`,
+	}
+	store, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.Open: %v", err)
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""), skill.WithCache(store))
+
+	def := &skill.Definition{
+		Name:         "test-skill",
+		Body:         "You are a test skill.",
+		OutputSchema: `{"type":"object"}`,
+		InputSchema:  `{"type":"object"}`,
+	}
+	opts := skill.RunOpts{RepoTree: "a.go\n", Model: "haiku"}
+
+	first, err := runner.Run(t.Context(), def, opts)
+	if err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	if first.Cached {
+		t.Error("first run should not be served from cache")
+	}
+
+	second, err := runner.Run(t.Context(), def, opts)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if !second.Cached || !second.ShouldFail() || second.Blocking[0].Path != "a.go" {
+		t.Errorf("cached output = %+v", second)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("agent calls = %d, want 1", mock.CallCount())
+	}
+
+	// Any input change is a miss.
+	opts.RepoTree = "a.go\nb.go\n"
+	if _, err := runner.Run(t.Context(), def, opts); err != nil {
+		t.Fatalf("third Run: %v", err)
+	}
+	opts.Model = "sonnet"
+	if _, err := runner.Run(t.Context(), def, opts); err != nil {
+		t.Fatalf("fourth Run: %v", err)
+	}
+	if mock.CallCount() != 3 {
+		t.Errorf("agent calls = %d, want 3 after input changes", mock.CallCount())
+	}
+
+	// Re-pinning the alias or editing the profile is a miss too.
+	opts.ModelIdentity = "claude-sonnet-4-5 max_tokens=8192 temperature=default thinking_budget=0"
+	if _, err := runner.Run(t.Context(), def, opts); err != nil {
+		t.Fatalf("fifth Run: %v", err)
+	}
+	if mock.CallCount() != 4 {
+		t.Errorf("agent calls = %d, want 4 after model identity change", mock.CallCount())
+	}
+}
+
+func TestRunner_Run_InvalidResponseNotCached(t *testing.T) {
+	mock := &agent.MockAgent{NameVal: "mock", EvaluateResponse: "not json"}
+	store, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.Open: %v", err)
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""), skill.WithCache(store))
+	def := &skill.Definition{Name: "test-skill", Body: "b"}
+
+	for range 2 {
+		if _, err := runner.Run(t.Context(), def, skill.RunOpts{}); err == nil {
+			t.Fatal("expected error for invalid response")
+		}
+	}
+	if mock.CallCount() != 2 {
+		t.Errorf("agent calls = %d, want 2 (failures must not be cached)", mock.CallCount())
+	}
+}
+
+func TestRunner_Run_RepairsInvalidResponse(t *testing.T) {
+	validJSON := `{"skill": "test-skill", "version": "v1", "status": "pass",
+		"blocking": [], "major": [], "warning": [], "info": []}`
+	responses := []string{"Sure! The repo looks fine.", validJSON}
+	calls := 0
+	mock := &agent.MockAgent{
+		NameVal: "mock",
+		EvaluateFunc: func(_ context.Context, _, _ string, _ agent.Model, _ agent.ToolPolicy) (string, error) {
+			calls++
+			return responses[calls-1], nil
+		},
+	}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""), skill.WithRepairRetries(2))
+
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill."}
+	out, err := runner.Run(t.Context(), def, skill.RunOpts{RepoTree: "file1.go\n"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if out.Repairs != 1 || len(mock.EvaluateCalls) != 2 {
+		t.Errorf("Repairs = %d, calls = %d; want 1, 2", out.Repairs, len(mock.EvaluateCalls))
+	}
+
+	repair := mock.EvaluateCalls[1].UserPrompt
+	for _, want := range []string{"file1.go", "rejected", "Sure! The repo looks fine."} {
+		if !strings.Contains(repair, want) {
+			t.Errorf("repair prompt missing %q:\n%s", want, repair)
+		}
+	}
+}
+
+func TestRunner_Run_RepairRetriesExhausted(t *testing.T) {
+	mock := &agent.MockAgent{NameVal: "mock", EvaluateResponse: "still not json"}
+	runner := skill.NewRunner(mock, prompt.NewBuilder(assets.NewResolver(""), ""), skill.WithRepairRetries(2))
+
+	def := &skill.Definition{Name: "test-skill", Body: "You are a test skill."}
+	_, err := runner.Run(t.Context(), def, skill.RunOpts{RepoTree: "file1.go\n"})
+	if err == nil || !strings.Contains(err.Error(), "after 2 repair attempt(s)") {
+		t.Errorf("err = %v, want exhausted repair error", err)
+	}
+	if len(mock.EvaluateCalls) != 3 {
+		t.Errorf("calls = %d, want 3 (initial + 2 repairs)", len(mock.EvaluateCalls))
+	}
+}
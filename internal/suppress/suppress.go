@@ -0,0 +1,157 @@
+// Package suppress parses inline bonsai:ignore directives from source
+// files and matches them against skill findings.
+//
+// A directive lives in a source comment and names the skills it applies
+// to and a mandatory justification:
+//
+//	token := "sk-test" // bonsai:ignore hardcoded-secret-pattern-detector reason="test fixture"
+//	# bonsai:ignore-file repo-convention-enforcer reason="vendored upstream layout"
+//
+// "bonsai:ignore" suppresses findings located on the directive's own line
+// or on the line immediately below it. "bonsai:ignore-file" suppresses
+// every finding in the file. Several skills may be listed separated by
+// commas. Directives without a non-empty reason are not honoured, so
+// every suppression carries a reviewable justification.
+package suppress
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pithecene-io/bonsai/internal/skill"
+)
+
+// maxLineBytes bounds the scanner buffer; longer lines are unlikely to
+// hold directives and would otherwise abort scanning of the file.
+const maxLineBytes = 1 << 20
+
+// directiveRe matches a directive introduced by a common comment leader
+// (//, #, --, ;, /*, <!--).
+var directiveRe = regexp.MustCompile(
+	`(?:^|\s)(?://+|#+|--|;+|/\*+|<!--)\s*bonsai:(ignore(?:-file)?)\s+([A-Za-z0-9_.,-]+)\s+reason="([^"]*)"`,
+)
+
+// Directive is a parsed bonsai:ignore comment.
+type Directive struct {
+	Skills []string // skills the directive applies to
+	Reason string   // justification (never empty)
+	Line   int      // 1-based line of the comment
+	File   bool     // true for bonsai:ignore-file
+}
+
+// AppliesTo reports whether the directive names skillName.
+func (d Directive) AppliesTo(skillName string) bool {
+	for _, s := range d.Skills {
+		if s == skillName {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether the directive suppresses a finding spanning
+// lines start..end.
+func (d Directive) covers(start, end int) bool {
+	if d.File {
+		return true
+	}
+	if start == 0 {
+		return false
+	}
+	if end < start {
+		end = start
+	}
+	return d.Line >= start-1 && d.Line <= end
+}
+
+// ParseLine extracts a directive from a single source line. lineNo is
+// recorded on the returned directive.
+func ParseLine(line string, lineNo int) (Directive, bool) {
+	m := directiveRe.FindStringSubmatch(line)
+	if m == nil {
+		return Directive{}, false
+	}
+	reason := strings.TrimSpace(m[3])
+	if reason == "" {
+		return Directive{}, false
+	}
+	var skills []string
+	for _, s := range strings.Split(m[2], ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			skills = append(skills, s)
+		}
+	}
+	if len(skills) == 0 {
+		return Directive{}, false
+	}
+	return Directive{Skills: skills, Reason: reason, Line: lineNo, File: m[1] == "ignore-file"}, true
+}
+
+// ParseFile reads every directive in the file at path.
+func ParseFile(path string) ([]Directive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var directives []Directive
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for n := 1; sc.Scan(); n++ {
+		if d, ok := ParseLine(sc.Text(), n); ok {
+			directives = append(directives, d)
+		}
+	}
+	return directives, sc.Err()
+}
+
+// Index lazily parses and caches directives for files under a repository
+// root. It is safe for concurrent use.
+type Index struct {
+	root  string
+	mu    sync.Mutex
+	files map[string][]Directive
+}
+
+// NewIndex returns an Index for the repository rooted at root.
+func NewIndex(root string) *Index {
+	return &Index{root: root, files: make(map[string][]Directive)}
+}
+
+// Match returns the directive suppressing finding f reported by
+// skillName. Findings without a path (including legacy string
+// findings), or whose path escapes the repository root or cannot be
+// read, are never suppressed; the baseline is the way to accept them.
+func (ix *Index) Match(skillName string, f skill.Finding) (Directive, bool) {
+	if f.Path == "" {
+		return Directive{}, false
+	}
+	for _, d := range ix.directives(f.Path) {
+		if d.AppliesTo(skillName) && d.covers(f.StartLine, f.EndLine) {
+			return d, true
+		}
+	}
+	return Directive{}, false
+}
+
+// directives returns the cached directives for a repo-relative path.
+func (ix *Index) directives(rel string) []Directive {
+	rel = filepath.Clean(filepath.FromSlash(rel))
+	if !filepath.IsLocal(rel) {
+		return nil
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ds, ok := ix.files[rel]; ok {
+		return ds
+	}
+	ds, _ := ParseFile(filepath.Join(ix.root, rel)) // unreadable files have no directives
+	ix.files[rel] = ds
+	return ds
+}
@@ -0,0 +1,84 @@
+package suppress_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pithecene-io/bonsai/internal/skill"
+	"github.com/pithecene-io/bonsai/internal/suppress"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		ok     bool
+		skills []string
+		reason string
+		file   bool
+	}{
+		{"go trailing", `x := 1 // bonsai:ignore secret-scan reason="test fixture"`, true, []string{"secret-scan"}, "test fixture", false},
+		{"hash", `# bonsai:ignore a,b reason="legacy"`, true, []string{"a", "b"}, "legacy", false},
+		{"sql", `-- bonsai:ignore a reason="generated"`, true, []string{"a"}, "generated", false},
+		{"block", `/* bonsai:ignore-file a reason="vendored" */`, true, []string{"a"}, "vendored", true},
+		{"missing reason", `// bonsai:ignore a`, false, nil, "", false},
+		{"empty reason", `// bonsai:ignore a reason=""`, false, nil, "", false},
+		{"no comment leader", `bonsai:ignore a reason="x"`, false, nil, "", false},
+		{"not a directive", `// ignore this`, false, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := suppress.ParseLine(tt.line, 7)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if len(d.Skills) != len(tt.skills) || d.Skills[0] != tt.skills[0] {
+				t.Errorf("Skills = %v, want %v", d.Skills, tt.skills)
+			}
+			if d.Reason != tt.reason || d.File != tt.file || d.Line != 7 {
+				t.Errorf("directive = %+v", d)
+			}
+		})
+	}
+}
+
+func TestIndexMatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.py", "import os\n# bonsai:ignore scan reason=\"fixture\"\nKEY = 'x'\nOTHER = 'y'\n")
+	write("b.go", "// bonsai:ignore-file scan reason=\"vendored\"\npackage b\n")
+
+	ix := suppress.NewIndex(dir)
+	tests := []struct {
+		name  string
+		skill string
+		f     skill.Finding
+		want  bool
+	}{
+		{"line below directive", "scan", skill.Finding{Message: "m", Path: "a.py", StartLine: 3}, true},
+		{"directive line", "scan", skill.Finding{Message: "m", Path: "a.py", StartLine: 2}, true},
+		{"two lines below", "scan", skill.Finding{Message: "m", Path: "a.py", StartLine: 4}, false},
+		{"other skill", "lint", skill.Finding{Message: "m", Path: "a.py", StartLine: 3}, false},
+		{"no line", "scan", skill.Finding{Message: "m", Path: "a.py"}, false},
+		{"file directive", "scan", skill.Finding{Message: "m", Path: "b.go"}, true},
+		{"no path", "scan", skill.Finding{Message: "m"}, false},
+		{"missing file", "scan", skill.Finding{Message: "m", Path: "c.go", StartLine: 1}, false},
+		{"escapes root", "scan", skill.Finding{Message: "m", Path: "../a.py", StartLine: 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := ix.Match(tt.skill, tt.f); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -248,6 +248,25 @@ func (m Model) View() string {
 	return ansi.Wrap(b.String(), m.width, "")
 }
 
+// annotateResult overrides the meta and timing columns for result
+// details the skill state alone does not convey.
+func annotateResult(s skillEntry, meta, timing string) (string, string) {
+	if s.result == nil {
+		return meta, timing
+	}
+	if s.result.Status == "timeout" {
+		style := styleWarning
+		if s.mandatory {
+			style = styleFailed
+		}
+		meta = style.Render("[timeout]")
+	}
+	if s.result.Cached {
+		timing = styleDim.Render("cached")
+	}
+	return meta, timing
+}
+
 func (m Model) renderSkill(s skillEntry) string {
 	var icon, name, meta, timing string
 
@@ -290,6 +309,8 @@ func (m Model) renderSkill(s skillEntry) string {
 		timing = styleDim.Render("—")
 	}
 
+	meta, timing = annotateResult(s, meta, timing)
+
 	// Layout: "  {icon} {name} {meta} {timing}"
 	// Pad name to fill available space so meta/timing columns align.
 	// View() applies ansi.Wrap to hard-break any line exceeding width.